@@ -1,136 +1,116 @@
 package main
 
 import (
-	"container/heap"
 	"fmt"
 	"math"
-)
-
-// Node represents a point in the grid.
-type Node struct {
-	x, y    int
-	g, h, f float64
-	parent  *Node
-	index   int // index in the priority queue
-}
-
-// PriorityQueue implements heap.Interface and holds Nodes.
-type PriorityQueue []*Node
-
-func (pq PriorityQueue) Len() int { return len(pq) }
-
-func (pq PriorityQueue) Less(i, j int) bool {
-	return pq[i].f < pq[j].f
-}
-
-func (pq PriorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].index = i
-	pq[j].index = j
-}
-
-func (pq *PriorityQueue) Push(x interface{}) {
-	n := len(*pq)
-	node := x.(*Node)
-	node.index = n
-	*pq = append(*pq, node)
-}
-
-func (pq *PriorityQueue) Pop() interface{} {
-	old := *pq
-	n := len(old)
-	node := old[n-1]
-	node.index = -1 // for safety
-	*pq = old[0 : n-1]
-	return node
-}
-
-// heuristic estimates the cost from current node to the goal.
-func heuristic(a, b *Node) float64 {
-	return math.Abs(float64(a.x-b.x)) + math.Abs(float64(a.y-b.y))
-}
-
-// neighbors returns the adjacent nodes of the current node.
-func neighbors(node *Node, grid [][]int) []*Node {
-	dirs := []struct{ dx, dy int }{
-		{0, -1}, {-1, 0}, {1, 0}, {0, 1},
-	}
-	var result []*Node
-	for _, dir := range dirs {
-		x, y := node.x+dir.dx, node.y+dir.dy
-		if x >= 0 && x < len(grid) && y >= 0 && y < len(grid[0]) && grid[x][y] == 0 {
-			result = append(result, &Node{x: x, y: y})
-		}
-	}
-	return result
-}
-
-// reconstructPath builds the path from start to goal.
-func reconstructPath(node *Node) []*Node {
-	var path []*Node
-	for current := node; current != nil; current = current.parent {
-		path = append([]*Node{current}, path...)
-	}
-	return path
-}
-
-// aStar finds the shortest path between start and goal nodes in a grid.
-func aStar(start, goal *Node, grid [][]int) []*Node {
-	openSet := &PriorityQueue{}
-	heap.Init(openSet)
-	heap.Push(openSet, start)
-
-	cameFrom := make(map[Node]*Node)
-
-	gScore := make(map[Node]float64)
-	gScore[*start] = 0
+	"strings"
 
-	fScore := make(map[Node]float64)
-	fScore[*start] = heuristic(start, goal)
-
-	for openSet.Len() > 0 {
-		current := heap.Pop(openSet).(*Node)
-
-		if current.x == goal.x && current.y == goal.y {
-			return reconstructPath(current)
-		}
-
-		for _, neighbor := range neighbors(current, grid) {
-			tentativeGScore := gScore[*current] + 1
-
-			if g, exists := gScore[*neighbor]; !exists || tentativeGScore < g {
-				cameFrom[*neighbor] = current
-				gScore[*neighbor] = tentativeGScore
-				fScore[*neighbor] = tentativeGScore + heuristic(neighbor, goal)
-				heap.Push(openSet, neighbor)
-			}
-		}
-	}
-
-	return nil // no path found
-}
+	"go-mastery/a-star-algorithm/astar"
+	"go-mastery/graphs/graph"
+)
 
 func main() {
 	// Define a simple grid: 0 = walkable, 1 = obstacle
-	grid := [][]int{
+	grid := graph.NewGrid([][]int{
 		{0, 1, 0, 0, 0},
 		{0, 1, 0, 1, 0},
 		{0, 0, 0, 1, 0},
 		{0, 1, 1, 1, 0},
 		{0, 0, 0, 0, 0},
-	}
+	})
 
-	start := &Node{x: 0, y: 0}
-	goal := &Node{x: 4, y: 4}
+	start := graph.Cell{Row: 0, Col: 0}
+	goal := graph.Cell{Row: 4, Col: 4}
 
-	path := aStar(start, goal, grid)
-	if path != nil {
-		fmt.Println("Path found:")
-		for _, node := range path {
-			fmt.Printf("(%d, %d) -> ", node.x, node.y)
+	path, cost, err := astar.Search(grid, start, goal, astar.Manhattan(goal))
+	if err != nil {
+		fmt.Println(err)
+	} else {
+		fmt.Printf("Path found (cost %g):\n", cost)
+		for _, cell := range path {
+			fmt.Printf("(%d, %d) -> ", cell.Row, cell.Col)
 		}
 		fmt.Println("Goal")
-	} else {
-		fmt.Println("No path found.")
 	}
+
+	// A warehouse floor: '.' costs 1, '~' is a wet patch costing 3 and '#'
+	// is a shelf. The robot may move diagonally, so Octile is the heuristic.
+	floor := []string{
+		"..........",
+		".####.~~~.",
+		".#....~~~.",
+		".#.##.~~~.",
+		"......~~~.",
+	}
+	terrain := map[rune]float64{'.': 1, '~': 3, '#': math.Inf(1)}
+	costs := make([][]float64, len(floor))
+	for r, line := range floor {
+		for _, ch := range line {
+			costs[r] = append(costs[r], terrain[ch])
+		}
+	}
+	warehouse := graph.NewWeightedGrid(costs)
+	warehouse.SetMoves(graph.EightWay)
+	dock, shelf := graph.Cell{Row: 4, Col: 0}, graph.Cell{Row: 2, Col: 9}
+	route, cost, err := astar.Search(warehouse, dock, shelf, astar.Octile(shelf))
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	fmt.Printf("\nWarehouse route (cost %.2f):\n", cost)
+	rows := make([][]byte, len(floor))
+	for r, line := range floor {
+		rows[r] = []byte(line)
+	}
+	for _, c := range route {
+		rows[c.Row][c.Col] = '*'
+	}
+	for _, row := range rows {
+		fmt.Println(string(row))
+	}
+
+	// Any graph works with a heuristic that fits it. Here the straight-line
+	// distance never exceeds the length of a road.
+	type city struct {
+		name string
+		x, y float64 // km
+	}
+	var (
+		a = city{"A", 0, 0}
+		b = city{"B", 40, 30}
+		c = city{"C", 30, -40}
+		d = city{"D", 80, 0}
+		e = city{"E", 120, 10}
+	)
+	roads := graph.NewUndirected[city]()
+	roads.AddWeightedEdge(a, b, 55)
+	roads.AddWeightedEdge(a, c, 50)
+	roads.AddWeightedEdge(b, d, 60)
+	roads.AddWeightedEdge(c, d, 70)
+	roads.AddWeightedEdge(d, e, 45)
+	roads.AddWeightedEdge(b, e, 120)
+	crowFlies := func(from city) float64 { return math.Hypot(e.x-from.x, e.y-from.y) }
+
+	trip, km, err := astar.Search(roads, a, e, crowFlies)
+	if err != nil {
+		fmt.Println(err)
+		return
+	}
+	names := make([]string, len(trip))
+	for i, stop := range trip {
+		names[i] = stop.name
+	}
+	fmt.Printf("\nRoad trip: %s (%g km)\n", strings.Join(names, " -> "), km)
+	// Output:
+	// Path found (cost 8):
+	// (0, 0) -> (1, 0) -> (2, 0) -> (3, 0) -> (4, 0) -> (4, 1) -> (4, 2) -> (4, 3) -> (4, 4) -> Goal
+	//
+	// Warehouse route (cost 13.83):
+	// ......***.
+	// .####*~~~*
+	// .#...*~~~*
+	// .#.##*~~~.
+	// ******~~~.
+	//
+	// Road trip: A -> B -> D -> E (160 km)
 }
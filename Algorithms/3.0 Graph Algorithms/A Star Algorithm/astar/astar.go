@@ -0,0 +1,99 @@
+// Package astar finds shortest paths with the A* search algorithm, which
+// uses a heuristic estimate of the remaining distance to explore the most
+// promising vertices first.
+package astar
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-mastery/graphs/graph"
+	"go-mastery/heaps/pq"
+)
+
+var (
+	// ErrNegativeWeight is returned, wrapped with the edge, when the search
+	// meets an edge with a negative weight
+	ErrNegativeWeight = errors.New("negative edge weight")
+	// ErrNoVertex is returned, wrapped with the vertex, when the start or
+	// goal is not in the graph
+	ErrNoVertex = errors.New("vertex not in graph")
+	// ErrNoPath is returned when the goal cannot be reached
+	ErrNoPath = errors.New("no path")
+)
+
+// key orders the open set: lowest estimated total first and, among equal
+// totals, the vertex estimated to be closest to the goal
+type key struct {
+	f float64 // length of the best path so far plus the heuristic
+	h float64 // the heuristic alone
+}
+
+func (a key) less(b key) bool {
+	return a.f < b.f || a.f == b.f && a.h < b.h
+}
+
+// Search returns a shortest path from start to goal, both included, and
+// its length. It works on any graph.Graph: a grid from graph.NewGrid or
+// graph.NewWeightedGrid, or any other graph with a suitable heuristic.
+//
+// Each vertex is expanded at most once. The path is a shortest one if the
+// heuristic is consistent: it never drops by more than the weight of an
+// edge, which implies it never overestimates. Every heuristic in this
+// package is consistent for the moves it is documented for. Edge weights
+// must not be negative.
+func Search[V comparable](g graph.Graph[V], start, goal V, h Heuristic[V]) ([]V, float64, error) {
+	for _, v := range []V{start, goal} {
+		if !g.HasVertex(v) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrNoVertex, v)
+		}
+	}
+	cameFrom := make(map[V]V)
+	gScore := map[V]float64{start: 0} // length of the best path found so far
+	closed := make(map[V]bool)        // vertices already expanded
+
+	// A vertex is pushed again when a better path to it is found; the
+	// older entries are popped after it is closed and are skipped
+	open := pq.New[V](key.less)
+	open.Push(start, key{h(start), h(start)})
+	for open.Len() > 0 {
+		item, _ := open.Pop()
+		current := item.Value
+		if closed[current] {
+			continue
+		}
+		if current == goal {
+			return reconstructPath(cameFrom, start, goal), gScore[goal], nil
+		}
+		closed[current] = true
+		for next, weight := range g.Adjacent(current) {
+			if weight < 0 {
+				return nil, 0, fmt.Errorf("%w: %v -> %v (%g)", ErrNegativeWeight, current, next, weight)
+			}
+			if closed[next] {
+				continue
+			}
+			tentative := gScore[current] + weight
+			if best, seen := gScore[next]; seen && tentative >= best {
+				continue
+			}
+			cameFrom[next] = current
+			gScore[next] = tentative
+			estimate := h(next)
+			open.Push(next, key{tentative + estimate, estimate})
+		}
+	}
+	return nil, 0, fmt.Errorf("%w from %v to %v", ErrNoPath, start, goal)
+}
+
+// reconstructPath follows cameFrom back from goal to start
+func reconstructPath[V comparable](cameFrom map[V]V, start, goal V) []V {
+	path := []V{goal}
+	for v := goal; v != start; {
+		v = cameFrom[v]
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
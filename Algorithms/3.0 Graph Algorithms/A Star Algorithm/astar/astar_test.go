@@ -0,0 +1,294 @@
+package astar
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+// bfsDistance returns the number of moves between two cells, or -1
+func bfsDistance(g *graph.Grid, start, goal graph.Cell) int {
+	dist := map[graph.Cell]int{start: 0}
+	queue := []graph.Cell{start}
+	for len(queue) > 0 {
+		c := queue[0]
+		queue = queue[1:]
+		if c == goal {
+			return dist[c]
+		}
+		for next := range g.Adjacent(c) {
+			if _, seen := dist[next]; !seen {
+				dist[next] = dist[c] + 1
+				queue = append(queue, next)
+			}
+		}
+	}
+	return -1
+}
+
+// relax computes shortest distances by relaxing every edge until nothing
+// changes
+func relax[V comparable](g graph.Graph[V], source V) map[V]float64 {
+	dist := make(map[V]float64)
+	for _, v := range g.Vertices() {
+		dist[v] = math.Inf(1)
+	}
+	dist[source] = 0
+	for changed := true; changed; {
+		changed = false
+		for _, v := range g.Vertices() {
+			for next, w := range g.Adjacent(v) {
+				if dist[v]+w < dist[next] {
+					dist[next] = dist[v] + w
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// checkPath fails unless path runs from start to goal over edges of g with
+// a total weight of cost
+func checkPath[V comparable](t *testing.T, g graph.Graph[V], path []V, start, goal V, cost float64) {
+	t.Helper()
+	if len(path) == 0 || path[0] != start || path[len(path)-1] != goal {
+		t.Fatalf("path %v does not run from %v to %v", path, start, goal)
+	}
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		w, ok := math.Inf(1), false
+		for next, weight := range g.Adjacent(path[i-1]) {
+			if next == path[i] {
+				w, ok = min(w, weight), true
+			}
+		}
+		if !ok {
+			t.Fatalf("path %v uses a missing edge %v -> %v", path, path[i-1], path[i])
+		}
+		total += w
+	}
+	if !near(total, cost) {
+		t.Fatalf("path %v weighs %v; want %v", path, total, cost)
+	}
+}
+
+// near reports whether two path lengths are equal up to rounding, as sums
+// of √2 differ in the last bits when added in another order
+func near(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*max(1, math.Abs(b))
+}
+
+// randomTerrain returns a size×size grid of cells costing 1 to 4, with
+// about one in four cells an obstacle
+func randomTerrain(size int) [][]float64 {
+	costs := make([][]float64, size)
+	for r := range costs {
+		costs[r] = make([]float64, size)
+		for c := range costs[r] {
+			costs[r][c] = 1 + float64(rand.IntN(7))/2
+			if rand.IntN(4) == 0 {
+				costs[r][c] = math.Inf(1)
+			}
+		}
+	}
+	costs[0][0], costs[size-1][size-1] = 1, 1
+	return costs
+}
+
+func TestSearchGrid(t *testing.T) {
+	for range 200 {
+		cells := make([][]int, 8)
+		for r := range cells {
+			cells[r] = make([]int, 8)
+			for c := range cells[r] {
+				if rand.IntN(3) == 0 {
+					cells[r][c] = 1
+				}
+			}
+		}
+		start, goal := graph.Cell{Row: 0, Col: 0}, graph.Cell{Row: 7, Col: 7}
+		cells[0][0], cells[7][7] = 0, 0
+		g := graph.NewGrid(cells)
+
+		path, cost, err := Search(g, start, goal, Manhattan(goal))
+		want := bfsDistance(g, start, goal)
+		if want < 0 {
+			if !errors.Is(err, ErrNoPath) {
+				t.Fatalf("found %v, %v but there is no path", path, err)
+			}
+			continue
+		}
+		if err != nil || cost != float64(want) || len(path)-1 != want {
+			t.Fatalf("path %v costs %v, %v; want %d moves", path, cost, err, want)
+		}
+		checkPath[graph.Cell](t, g, path, start, goal, cost)
+	}
+}
+
+func TestSearchWeightedGrid(t *testing.T) {
+	heuristics := map[graph.Moves]map[string]func(graph.Cell) Heuristic[graph.Cell]{
+		graph.FourWay: {
+			"Zero":      func(graph.Cell) Heuristic[graph.Cell] { return Zero[graph.Cell]() },
+			"Manhattan": Manhattan,
+			"Euclidean": Euclidean,
+			"Octile":    Octile,
+			"Chebyshev": Chebyshev,
+		},
+		graph.EightWay: {
+			"Zero":      func(graph.Cell) Heuristic[graph.Cell] { return Zero[graph.Cell]() },
+			"Euclidean": Euclidean,
+			"Octile":    Octile,
+			"Chebyshev": Chebyshev,
+		},
+	}
+	for range 50 {
+		g := graph.NewWeightedGrid(randomTerrain(8))
+		start, goal := graph.Cell{Row: 0, Col: 0}, graph.Cell{Row: 7, Col: 7}
+		for moves, byName := range heuristics {
+			g.SetMoves(moves)
+			want := relax[graph.Cell](g, start)[goal]
+			for name, heuristic := range byName {
+				path, cost, err := Search(g, start, goal, heuristic(goal))
+				if math.IsInf(want, 1) {
+					if !errors.Is(err, ErrNoPath) {
+						t.Fatalf("%s found %v, %v but there is no path", name, path, err)
+					}
+					continue
+				}
+				if err != nil || !near(cost, want) {
+					t.Fatalf("%s with moves %d: cost %v, %v; want %v", name, moves, cost, err, want)
+				}
+				checkPath[graph.Cell](t, g, path, start, goal, cost)
+			}
+		}
+	}
+}
+
+func TestScale(t *testing.T) {
+	for range 50 {
+		costs := randomTerrain(10)
+		for _, row := range costs {
+			for c := range row {
+				row[c] *= 2 // the cheapest cell now costs 2
+			}
+		}
+		g := graph.NewWeightedGrid(costs)
+		g.SetMoves(graph.EightWay)
+		start, goal := graph.Cell{Row: 0, Col: 0}, graph.Cell{Row: 9, Col: 9}
+		want := relax[graph.Cell](g, start)[goal]
+		if math.IsInf(want, 1) {
+			continue
+		}
+		_, cost, err := Search(g, start, goal, Scale(Octile(goal), 2))
+		if err != nil || !near(cost, want) {
+			t.Fatalf("Octile scaled to the cheapest cell: cost %v, %v; want %v", cost, err, want)
+		}
+		// Weighted A* with a factor of 6 may be up to 3 times too long
+		path, cost, err := Search(g, start, goal, Scale(Octile(goal), 6))
+		if err != nil || cost < want || cost > 3*want+1e-9 {
+			t.Fatalf("weighted A*: cost %v, %v; want between %v and %v", cost, err, want, 3*want)
+		}
+		checkPath[graph.Cell](t, g, path, start, goal, cost)
+	}
+}
+
+func TestHeuristics(t *testing.T) {
+	goal := graph.Cell{Row: 1, Col: 2}
+	c := graph.Cell{Row: 4, Col: -2} // 3 rows and 4 columns away
+	for name, test := range map[string]struct {
+		h    Heuristic[graph.Cell]
+		want float64
+	}{
+		"Zero":      {Zero[graph.Cell](), 0},
+		"Manhattan": {Manhattan(goal), 7},
+		"Euclidean": {Euclidean(goal), 5},
+		"Octile":    {Octile(goal), 1 + 3*math.Sqrt2},
+		"Chebyshev": {Chebyshev(goal), 4},
+		"Scale":     {Scale(Manhattan(goal), 1.5), 10.5},
+	} {
+		if got := test.h(c); !near(got, test.want) {
+			t.Errorf("%s(%v) = %v; want %v", name, c, got, test.want)
+		}
+		if got := test.h(goal); got != 0 {
+			t.Errorf("%s at the goal = %v; want 0", name, got)
+		}
+	}
+}
+
+// counting wraps a graph and records which vertices were expanded
+type counting[V comparable] struct {
+	graph.Graph[V]
+	expanded []V
+}
+
+func (c *counting[V]) Adjacent(v V) iter.Seq2[V, float64] {
+	c.expanded = append(c.expanded, v)
+	return c.Graph.Adjacent(v)
+}
+
+func TestClosedSet(t *testing.T) {
+	// On an open eight-way grid, Manhattan overestimates and leads the
+	// search to vertices it later finds shorter paths to; they are still
+	// expanded only once
+	g := graph.NewGrid([][]int{
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+		{0, 0, 0, 0, 0, 0},
+	})
+	g.SetMoves(graph.EightWay)
+	c := &counting[graph.Cell]{Graph: g}
+	goal := graph.Cell{Row: 3, Col: 5}
+	for _, h := range []Heuristic[graph.Cell]{Zero[graph.Cell](), Manhattan(goal), Octile(goal)} {
+		c.expanded = nil
+		if _, _, err := Search[graph.Cell](c, graph.Cell{}, goal, h); err != nil {
+			t.Fatal(err)
+		}
+		seen := make(map[graph.Cell]bool)
+		for _, v := range c.expanded {
+			if seen[v] {
+				t.Fatalf("expanded %v twice in %v", v, c.expanded)
+			}
+			seen[v] = true
+		}
+	}
+	// Octile is exact on an open eight-way grid, so only the cells on one
+	// shortest path are expanded
+	c.expanded = nil
+	if _, _, err := Search[graph.Cell](c, graph.Cell{}, goal, Octile(goal)); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.expanded) != 5 {
+		t.Fatalf("Octile expanded %v; want the 5 cells before the goal", c.expanded)
+	}
+}
+
+func TestSearchWeighted(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddWeightedEdge("A", "B", 1)
+	g.AddWeightedEdge("B", "D", 10)
+	g.AddWeightedEdge("A", "C", 3)
+	g.AddWeightedEdge("C", "D", 3)
+
+	path, cost, err := Search(g, "A", "D", Zero[string]())
+	if err != nil || cost != 6 || !slices.Equal(path, []string{"A", "C", "D"}) {
+		t.Fatalf("Search(A, D) = %v, %v, %v; want [A C D] costing 6", path, cost, err)
+	}
+	if _, _, err := Search(g, "D", "A", Zero[string]()); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("Search(D, A) against the edge directions: err = %v", err)
+	}
+	if _, _, err := Search(g, "A", "Z", Zero[string]()); !errors.Is(err, ErrNoVertex) {
+		t.Fatalf("Search(A, Z): err = %v; want ErrNoVertex", err)
+	}
+	g.AddWeightedEdge("C", "E", -1)
+	g.AddWeightedEdge("E", "F", 1)
+	if _, _, err := Search(g, "A", "F", Zero[string]()); !errors.Is(err, ErrNegativeWeight) {
+		t.Fatalf("Search(A, F) over a negative edge: err = %v", err)
+	}
+}
@@ -0,0 +1,72 @@
+package astar
+
+import (
+	"math"
+
+	"go-mastery/graphs/graph"
+)
+
+// Heuristic estimates the length of the shortest path from a vertex to the
+// goal
+type Heuristic[V comparable] func(v V) float64
+
+// Zero estimates every distance as 0, which turns A* into Dijkstra's
+// algorithm
+func Zero[V comparable]() Heuristic[V] {
+	return func(V) float64 { return 0 }
+}
+
+// Scale multiplies a heuristic by factor. The grid heuristics assume that
+// every cell costs at least 1; on a weighted grid whose cheapest cell costs
+// c, Scale(h, c) keeps them consistent and makes them tighter. A factor
+// above that gives weighted A*, which expands fewer vertices but may
+// return a path up to factor/c times longer than the shortest.
+func Scale[V comparable](h Heuristic[V], factor float64) Heuristic[V] {
+	return func(v V) float64 { return factor * h(v) }
+}
+
+// delta returns the absolute row and column differences between two cells
+func delta(a, b graph.Cell) (dr, dc float64) {
+	return math.Abs(float64(a.Row - b.Row)), math.Abs(float64(a.Col - b.Col))
+}
+
+// Manhattan is the number of straight steps to the goal. It is exact on an
+// open grid with four-way moves, but overestimates when diagonal moves are
+// allowed.
+func Manhattan(goal graph.Cell) Heuristic[graph.Cell] {
+	return func(c graph.Cell) float64 {
+		dr, dc := delta(c, goal)
+		return dr + dc
+	}
+}
+
+// Euclidean is the straight-line distance to the goal. It is consistent
+// for both four-way and eight-way moves, but lower than the other
+// heuristics, so A* expands more cells.
+func Euclidean(goal graph.Cell) Heuristic[graph.Cell] {
+	return func(c graph.Cell) float64 {
+		dr, dc := delta(c, goal)
+		return math.Hypot(dr, dc)
+	}
+}
+
+// Octile is the length of the shortest eight-way route on an open grid:
+// diagonal steps of length √2 until level with the goal, then straight
+// steps. It is the exact heuristic for graph.EightWay grids.
+func Octile(goal graph.Cell) Heuristic[graph.Cell] {
+	return func(c graph.Cell) float64 {
+		dr, dc := delta(c, goal)
+		return max(dr, dc) + (math.Sqrt2-1)*min(dr, dc)
+	}
+}
+
+// Chebyshev is the number of eight-way steps to the goal, counting a
+// diagonal step as 1. It suits grids where diagonal moves cost the same as
+// straight ones; on graph.EightWay grids it is consistent but looser than
+// Octile.
+func Chebyshev(goal graph.Cell) Heuristic[graph.Cell] {
+	return func(c graph.Cell) float64 {
+		dr, dc := delta(c, goal)
+		return max(dr, dc)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"go-mastery/all-pairs-shortest-paths/allpairs"
+	"go-mastery/graphs/graph"
+)
+
+func main() {
+	// Round-trip latency in milliseconds between datacenters
+	links := graph.NewUndirected[string]()
+	links.AddWeightedEdge("fra", "ams", 8)
+	links.AddWeightedEdge("fra", "lon", 15)
+	links.AddWeightedEdge("ams", "lon", 6)
+	links.AddWeightedEdge("lon", "nyc", 70)
+	links.AddWeightedEdge("nyc", "sfo", 65)
+	links.AddWeightedEdge("fra", "sin", 160)
+	links.AddWeightedEdge("sfo", "sin", 170)
+
+	table, err := allpairs.FloydWarshall(links)
+	if err != nil {
+		log.Fatal(err)
+	}
+	vertices := table.Vertices()
+	fmt.Print("     ")
+	for _, to := range vertices {
+		fmt.Printf("%5s", to)
+	}
+	fmt.Println()
+	for _, from := range vertices {
+		fmt.Printf("%5s", from)
+		for _, to := range vertices {
+			fmt.Printf("%5g", table.Dist(from, to))
+		}
+		fmt.Println()
+	}
+	// Output:
+	//        fra  ams  lon  nyc  sfo  sin
+	//   fra    0    8   14   84  149  160
+	//   ams    8    0    6   76  141  168
+	//   lon   14    6    0   70  135  174
+	//   nyc   84   76   70    0   65  235
+	//   sfo  149  141  135   65    0  170
+	//   sin  160  168  174  235  170    0
+
+	next, _ := table.Next("fra", "sfo")
+	fmt.Println("fra -> sfo goes first to", next) // Output: fra -> sfo goes first to ams
+	fmt.Println(strings.Join(table.Path("fra", "sfo"), " -> "))
+	// Output: fra -> ams -> lon -> nyc -> sfo
+
+	// Johnson's algorithm gives the same table, running Dijkstra from
+	// every datacenter in parallel
+	johnson, err := allpairs.Johnson(links)
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Println("Johnson sin -> nyc:", johnson.Dist("sin", "nyc")) // Output: Johnson sin -> nyc: 235
+}
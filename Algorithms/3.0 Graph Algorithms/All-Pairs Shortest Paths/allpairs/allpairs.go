@@ -0,0 +1,131 @@
+// Package allpairs finds the shortest paths between every pair of
+// vertices, with Floyd-Warshall for dense graphs and Johnson's algorithm
+// for sparse ones. Edge weights may be negative; a negative cycle is
+// reported as a *bellmanford.NegativeCycleError.
+package allpairs
+
+import (
+	"math"
+	"slices"
+
+	"go-mastery/bellman-ford-algorithm/bellmanford"
+	"go-mastery/graphs/graph"
+)
+
+// Matrix holds the length of a shortest path between every ordered pair
+// of vertices and the first step of such a path, so that any route can be
+// rebuilt without storing it
+type Matrix[V comparable] struct {
+	vertices []V
+	index    map[V]int
+	dist     []float64 // dist[i*n+j]; +Inf if j cannot be reached from i
+	next     []int     // index of the vertex after i on a path to j, or -1
+}
+
+func newMatrix[V comparable](vertices []V) *Matrix[V] {
+	n := len(vertices)
+	m := &Matrix[V]{
+		vertices: vertices,
+		index:    make(map[V]int, n),
+		dist:     make([]float64, n*n),
+		next:     make([]int, n*n),
+	}
+	for i, v := range vertices {
+		m.index[v] = i
+	}
+	for i := range m.dist {
+		m.dist[i] = math.Inf(1)
+		m.next[i] = -1
+	}
+	return m
+}
+
+// Vertices returns the vertices in the order of the graph's Vertices
+func (m *Matrix[V]) Vertices() []V {
+	return slices.Clone(m.vertices)
+}
+
+// Dist returns the length of a shortest path from u to v, or +Inf if there
+// is none or either vertex is unknown
+func (m *Matrix[V]) Dist(u, v V) float64 {
+	i, ok := m.index[u]
+	j, ok2 := m.index[v]
+	if !ok || !ok2 {
+		return math.Inf(1)
+	}
+	return m.dist[i*len(m.vertices)+j]
+}
+
+// Next returns the vertex after u on a shortest path from u to v. It
+// reports false if there is no such path or u equals v.
+func (m *Matrix[V]) Next(u, v V) (V, bool) {
+	i, ok := m.index[u]
+	j, ok2 := m.index[v]
+	if !ok || !ok2 || i == j || m.next[i*len(m.vertices)+j] < 0 {
+		var zero V
+		return zero, false
+	}
+	return m.vertices[m.next[i*len(m.vertices)+j]], true
+}
+
+// Path returns a shortest path from u to v, both included, or nil if there
+// is none
+func (m *Matrix[V]) Path(u, v V) []V {
+	if math.IsInf(m.Dist(u, v), 1) {
+		return nil
+	}
+	path := []V{u}
+	for u != v {
+		u, _ = m.Next(u, v)
+		path = append(path, u)
+	}
+	return path
+}
+
+// FloydWarshall computes all shortest paths by trying every vertex k in
+// turn as a stop between every pair i, j. It takes O(V³) time and O(V²)
+// space whatever the number of edges, which suits dense graphs.
+func FloydWarshall[V comparable](g graph.Graph[V]) (*Matrix[V], error) {
+	m := newMatrix(g.Vertices())
+	n := len(m.vertices)
+	for i, u := range m.vertices {
+		m.dist[i*n+i], m.next[i*n+i] = 0, i
+		for v, weight := range g.Adjacent(u) {
+			j := m.index[v]
+			if weight < m.dist[i*n+j] {
+				m.dist[i*n+j], m.next[i*n+j] = weight, j
+			}
+		}
+	}
+	for k := range n {
+		rowK := m.dist[k*n : (k+1)*n]
+		for i := range n {
+			ik := m.dist[i*n+k]
+			if math.IsInf(ik, 1) {
+				continue
+			}
+			rowI, nextI := m.dist[i*n:(i+1)*n], m.next[i*n:(i+1)*n]
+			for j, kj := range rowK {
+				if ik+kj < rowI[j] {
+					rowI[j], nextI[j] = ik+kj, nextI[k]
+				}
+			}
+		}
+	}
+	// A vertex with a negative path to itself is on a negative cycle. The
+	// next-hop table cannot be trusted to trace it, so Bellman-Ford from
+	// that vertex finds and reports the cycle.
+	for i, v := range m.vertices {
+		if m.dist[i*n+i] < 0 {
+			return nil, negativeCycle(g, v)
+		}
+	}
+	return m, nil
+}
+
+// negativeCycle returns the error describing a negative cycle that can be
+// reached from v
+func negativeCycle[V comparable](g graph.Graph[V], v V) error {
+	_, err := bellmanford.ShortestPaths(g, v)
+	return err
+}
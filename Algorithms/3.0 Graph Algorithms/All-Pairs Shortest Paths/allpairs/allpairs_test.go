@@ -0,0 +1,150 @@
+package allpairs
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/bellman-ford-algorithm/bellmanford"
+	"go-mastery/graphs/graph"
+	"go-mastery/graphs/graphtest"
+)
+
+var algorithms = []struct {
+	name string
+	run  func(graph.Graph[int]) (*Matrix[int], error)
+}{
+	{"FloydWarshall", FloydWarshall[int]},
+	{"Johnson", Johnson[int]},
+}
+
+// randomGraph returns a graph with integer weights. With potentials the
+// weights are reduced costs, so some are negative but no cycle is.
+func randomGraph(rng *rand.Rand, n int, potentials bool) *graph.AdjacencyList[int] {
+	if potentials {
+		return graphtest.Random(rng, true, n, 3*n, graphtest.ReducedCosts(rng, n))
+	}
+	return graphtest.Random(rng, rng.IntN(4) != 0, n, 3*n, graphtest.Weights(rng, -2, 8))
+}
+
+func TestAllPairs(t *testing.T) {
+	const n = 15
+	rng := graphtest.Rand(t)
+	for range 100 {
+		g := randomGraph(rng, n, true)
+		for _, alg := range algorithms {
+			m, err := alg.run(g)
+			if err != nil {
+				t.Fatalf("%s: %v", alg.name, err)
+			}
+			for u := range n {
+				want, _ := bellmanford.ShortestPaths(g, u)
+				for v := range n {
+					d, reached := want.Dist[v]
+					if !reached {
+						if got := m.Dist(u, v); !math.IsInf(got, 1) || m.Path(u, v) != nil {
+							t.Fatalf("%s: unreachable %d -> %d has distance %v", alg.name, u, v, got)
+						}
+						continue
+					}
+					if got := m.Dist(u, v); got != d {
+						t.Fatalf("%s: Dist(%d, %d) = %v; want %v", alg.name, u, v, got, d)
+					}
+					path, total := m.Path(u, v), 0.0
+					for i := 1; i < len(path); i++ {
+						w, ok := g.Weight(path[i-1], path[i])
+						if !ok {
+							t.Fatalf("%s: Path(%d, %d) = %v uses a missing edge", alg.name, u, v, path)
+						}
+						total += w
+					}
+					if path[0] != u || path[len(path)-1] != v || total != d {
+						t.Fatalf("%s: Path(%d, %d) = %v weighs %v; want %v", alg.name, u, v, path, total, d)
+					}
+				}
+			}
+		}
+	}
+}
+
+func TestNegativeCycle(t *testing.T) {
+	const n = 8
+	rng := graphtest.Rand(t)
+	for range 200 {
+		g := randomGraph(rng, n, false)
+		_, hasCycle := FloydWarshall(g)
+		for _, alg := range algorithms {
+			_, err := alg.run(g)
+			if (err != nil) != (hasCycle != nil) {
+				t.Fatalf("%s: err = %v; Floyd-Warshall: %v", alg.name, err, hasCycle)
+			}
+			if err == nil {
+				continue
+			}
+			var cycleErr *bellmanford.NegativeCycleError[int]
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("%s: err = %v; want a *NegativeCycleError", alg.name, err)
+			}
+			cycle, total := cycleErr.Cycle, 0.0
+			for i, u := range cycle {
+				w, ok := g.Weight(u, cycle[(i+1)%len(cycle)])
+				if !ok {
+					t.Fatalf("%s: cycle %v uses a missing edge", alg.name, cycle)
+				}
+				total += w
+			}
+			if total >= 0 {
+				t.Fatalf("%s: cycle %v weighs %v", alg.name, cycle, total)
+			}
+		}
+	}
+}
+
+func TestMatrixLookups(t *testing.T) {
+	g := graph.NewGrid([][]int{
+		{0, 1, 0},
+		{0, 0, 0},
+	})
+	for _, run := range []func(graph.Graph[graph.Cell]) (*Matrix[graph.Cell], error){FloydWarshall[graph.Cell], Johnson[graph.Cell]} {
+		m, err := run(g)
+		if err != nil {
+			t.Fatal(err)
+		}
+		a, b := graph.Cell{Row: 0, Col: 0}, graph.Cell{Row: 0, Col: 2}
+		if d := m.Dist(a, b); d != 4 {
+			t.Fatalf("Dist around the wall = %v; want 4", d)
+		}
+		if next, ok := m.Next(a, b); !ok || next != (graph.Cell{Row: 1, Col: 0}) {
+			t.Fatalf("Next = %v, %t", next, ok)
+		}
+		if _, ok := m.Next(a, a); ok || m.Dist(a, a) != 0 || len(m.Path(a, a)) != 1 {
+			t.Fatalf("a vertex to itself: Dist %v, Path %v", m.Dist(a, a), m.Path(a, a))
+		}
+		outside := graph.Cell{Row: 0, Col: 1}
+		if !math.IsInf(m.Dist(a, outside), 1) || m.Path(outside, a) != nil {
+			t.Fatalf("a blocked cell is reachable")
+		}
+
+		// Changing the returned slice must not remap rows and columns
+		vertices := m.Vertices()
+		slices.Reverse(vertices)
+		vertices[0] = outside
+		if !slices.Equal(m.Vertices(), g.Vertices()) || m.Dist(a, b) != 4 {
+			t.Fatalf("changing Vertices() changed the matrix: %v, Dist %v", m.Vertices(), m.Dist(a, b))
+		}
+	}
+}
+
+func BenchmarkAllPairs(b *testing.B) {
+	// A sparse graph: about 4 edges per vertex
+	g := randomGraph(graphtest.Rand(b), 200, true)
+	for _, alg := range algorithms {
+		b.Run(alg.name, func(b *testing.B) {
+			for range b.N {
+				alg.run(g)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+package allpairs
+
+import (
+	"errors"
+	"iter"
+	"runtime"
+	"sync"
+
+	"go-mastery/bellman-ford-algorithm/bellmanford"
+	"go-mastery/dijkstras-algorithm/dijkstra"
+	"go-mastery/graphs/graph"
+)
+
+// node is a vertex of the graph extended with Johnson's extra source
+type node[V comparable] struct {
+	v      V
+	source bool
+}
+
+// extended is g with an extra source vertex that has an edge of weight 0
+// to every vertex
+type extended[V comparable] struct {
+	g graph.Graph[V]
+}
+
+func (e extended[V]) Directed() bool { return true }
+
+func (e extended[V]) Vertices() []node[V] {
+	vertices := []node[V]{{source: true}}
+	for _, v := range e.g.Vertices() {
+		vertices = append(vertices, node[V]{v: v})
+	}
+	return vertices
+}
+
+func (e extended[V]) HasVertex(n node[V]) bool {
+	return n.source || e.g.HasVertex(n.v)
+}
+
+func (e extended[V]) Adjacent(n node[V]) iter.Seq2[node[V], float64] {
+	return func(yield func(node[V], float64) bool) {
+		if n.source {
+			for _, v := range e.g.Vertices() {
+				if !yield(node[V]{v: v}, 0) {
+					return
+				}
+			}
+			return
+		}
+		for v, weight := range e.g.Adjacent(n.v) {
+			if !yield(node[V]{v: v}, weight) {
+				return
+			}
+		}
+	}
+}
+
+// reweighted is g with each edge u -> v weighted w + h(u) - h(v), which is
+// never negative when h holds shortest distances from Johnson's source.
+// Every path from u to v changes by the same h(u) - h(v), so shortest
+// paths stay shortest.
+type reweighted[V comparable] struct {
+	graph.Graph[V]
+	h map[V]float64
+}
+
+func (r reweighted[V]) Adjacent(u V) iter.Seq2[V, float64] {
+	return func(yield func(V, float64) bool) {
+		for v, weight := range r.Graph.Adjacent(u) {
+			// Mathematically at least 0; clamp rounding errors so that
+			// Dijkstra does not reject the edge
+			if !yield(v, max(0, weight+r.h[u]-r.h[v])) {
+				return
+			}
+		}
+	}
+}
+
+// Johnson computes all shortest paths by reweighting the edges so that
+// none is negative and then running Dijkstra from every vertex. The
+// weights come from one Bellman-Ford run from an extra vertex joined to
+// every vertex by an edge of weight 0. The Dijkstra runs are spread over
+// GOMAXPROCS goroutines, so g must be safe for concurrent reads and must
+// not be changed during the call. It takes O(V·E log V) time, which grows
+// more slowly than Floyd-Warshall's O(V³) when E is far below V², though
+// Floyd-Warshall's tight loops win on small graphs.
+func Johnson[V comparable](g graph.Graph[V]) (*Matrix[V], error) {
+	potentials, err := bellmanford.ShortestPaths[node[V]](extended[V]{g}, node[V]{source: true})
+	if err != nil {
+		var cycleErr *bellmanford.NegativeCycleError[node[V]]
+		if errors.As(err, &cycleErr) {
+			// The extra source has no incoming edges, so it is never on
+			// the cycle
+			cycle := make([]V, len(cycleErr.Cycle))
+			for i, n := range cycleErr.Cycle {
+				cycle[i] = n.v
+			}
+			return nil, &bellmanford.NegativeCycleError[V]{Cycle: cycle, Weight: cycleErr.Weight}
+		}
+		return nil, err
+	}
+	h := make(map[V]float64, len(potentials.Dist))
+	for n, d := range potentials.Dist {
+		if !n.source {
+			h[n.v] = d
+		}
+	}
+	positive := reweighted[V]{Graph: g, h: h}
+
+	m := newMatrix(g.Vertices())
+	sources := make(chan int)
+	errs := make(chan error, 1)
+	var wg sync.WaitGroup
+	for range min(runtime.GOMAXPROCS(0), len(m.vertices)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range sources {
+				if err := m.fillRow(positive, i); err != nil {
+					select {
+					case errs <- err:
+					default:
+					}
+				}
+			}
+		}()
+	}
+	for i := range m.vertices {
+		sources <- i
+	}
+	close(sources)
+	wg.Wait()
+	select {
+	case err := <-errs:
+		return nil, err
+	default:
+		return m, nil
+	}
+}
+
+// fillRow runs Dijkstra from vertex i of the reweighted graph and stores
+// the true distances and first hops in row i. Rows are disjoint, so
+// goroutines can fill different rows at once.
+func (m *Matrix[V]) fillRow(g reweighted[V], i int) error {
+	source := m.vertices[i]
+	tree, err := dijkstra.ShortestPaths[V](g, source)
+	if err != nil {
+		return err
+	}
+	n := len(m.vertices)
+	dist, next := m.dist[i*n:(i+1)*n], m.next[i*n:(i+1)*n]
+	next[i] = i
+	for v, d := range tree.Dist {
+		j := m.index[v]
+		dist[j] = d - g.h[source] + g.h[v]
+	}
+	// The first hop to v is v itself if its predecessor is the source, and
+	// otherwise the first hop to its predecessor. Walk up the tree to a
+	// vertex whose first hop is known, then give it to the walked ones.
+	var walked []int
+	for v := range tree.Dist {
+		j := m.index[v]
+		for next[j] < 0 {
+			prev := tree.Prev[m.vertices[j]]
+			if prev == source {
+				next[j] = j
+				break
+			}
+			walked = append(walked, j)
+			j = m.index[prev]
+		}
+		for _, w := range walked {
+			next[w] = next[j]
+		}
+		walked = walked[:0]
+	}
+	return nil
+}
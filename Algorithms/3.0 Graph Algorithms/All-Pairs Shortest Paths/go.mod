@@ -0,0 +1,18 @@
+module go-mastery/all-pairs-shortest-paths
+
+go 1.24.0
+
+require (
+	go-mastery/bellman-ford-algorithm v0.0.0
+	go-mastery/dijkstras-algorithm v0.0.0
+	go-mastery/graphs v0.0.0
+)
+
+require go-mastery/heaps v0.0.0 // indirect
+
+replace (
+	go-mastery/bellman-ford-algorithm => "../Bellman-Ford Algorithm"
+	go-mastery/dijkstras-algorithm => "../Dijskras Algorithm"
+	go-mastery/graphs => "../../../Data Structures/5.0 Graphs"
+	go-mastery/heaps => "../../../Data Structures/7.0 Heaps"
+)
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"go-mastery/bellman-ford-algorithm/bellmanford"
+	"go-mastery/graphs/graph"
+)
+
+func main() {
+	// Shipping costs between warehouses. The negative edge is a rebate
+	// paid for taking returns back to the depot.
+	costs := graph.NewDirected[string]()
+	costs.AddWeightedEdge("factory", "depot", 4)
+	costs.AddWeightedEdge("factory", "hub", 5)
+	costs.AddWeightedEdge("hub", "depot", -3)
+	costs.AddWeightedEdge("depot", "store", 2)
+	costs.AddWeightedEdge("hub", "store", 6)
+
+	tree, err := bellmanford.ShortestPaths(costs, "factory")
+	if err != nil {
+		log.Fatal(err)
+	}
+	for _, v := range costs.Vertices() {
+		fmt.Printf("%s: %g via %s\n", v, tree.Dist[v], strings.Join(tree.PathTo(v), " -> "))
+	}
+	// Output:
+	// factory: 0 via factory
+	// depot: 2 via factory -> hub -> depot
+	// hub: 5 via factory -> hub
+	// store: 4 via factory -> hub -> depot -> store
+
+	// SPFA gives the same answer, usually with far fewer edge checks
+	tree, _ = bellmanford.SPFA(costs, "factory")
+	fmt.Println("SPFA store:", tree.Dist["store"]) // Output: SPFA store: 4
+
+	// A rebate larger than the cost of the round trip creates a loop that
+	// pays more each time it is driven
+	costs.AddWeightedEdge("depot", "hub", 1)
+	_, err = bellmanford.SPFA(costs, "factory")
+	var cycle *bellmanford.NegativeCycleError[string]
+	if errors.As(err, &cycle) {
+		fmt.Println(err)         // Output: negative cycle hub -> depot -> hub (weight -2)
+		fmt.Println(cycle.Cycle) // Output: [hub depot]
+	}
+}
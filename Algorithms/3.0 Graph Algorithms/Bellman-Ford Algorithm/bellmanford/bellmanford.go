@@ -0,0 +1,156 @@
+// Package bellmanford finds shortest paths in graphs whose edge weights
+// may be negative, with the Bellman-Ford algorithm and its queue-based
+// variant SPFA, and reports the negative cycles that make shortest paths
+// undefined.
+package bellmanford
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"go-mastery/graphs/graph"
+)
+
+// ErrNoVertex is returned, wrapped with the vertex, when the source is not
+// in the graph
+var ErrNoVertex = errors.New("vertex not in graph")
+
+// NegativeCycleError reports a cycle of negative total weight that can be
+// reached from the source. Going round it again and again makes paths
+// arbitrarily short, so no shortest paths exist.
+type NegativeCycleError[V comparable] struct {
+	// Cycle holds the vertices of the cycle in edge order; the last one
+	// has an edge back to the first
+	Cycle  []V
+	Weight float64 // total weight of the cycle's edges
+}
+
+func (e *NegativeCycleError[V]) Error() string {
+	names := make([]string, 0, len(e.Cycle)+1)
+	for _, v := range append(e.Cycle, e.Cycle[0]) {
+		names = append(names, fmt.Sprint(v))
+	}
+	return fmt.Sprintf("negative cycle %s (weight %g)", strings.Join(names, " -> "), e.Weight)
+}
+
+// newTree starts a PathTree at source, which must be a vertex of g
+func newTree[V comparable](g graph.Graph[V], source V) (*graph.PathTree[V], error) {
+	if !g.HasVertex(source) {
+		return nil, fmt.Errorf("%w: %v", ErrNoVertex, source)
+	}
+	return graph.NewPathTree(source), nil
+}
+
+// ShortestPaths runs the Bellman-Ford algorithm from source. It makes up
+// to V-1 passes over every edge, stopping early once a pass changes
+// nothing, so it takes O(V·E) time. If a pass after V-1 still shortens a
+// path, a negative cycle is reachable and the error is a
+// *NegativeCycleError[V]. In an undirected graph every edge can be walked
+// both ways, so any reachable negative edge is such a cycle.
+func ShortestPaths[V comparable](g graph.Graph[V], source V) (*graph.PathTree[V], error) {
+	t, err := newTree(g, source)
+	if err != nil {
+		return nil, err
+	}
+	vertices := g.Vertices()
+	for pass := 1; ; pass++ {
+		changed := false
+		for _, u := range vertices {
+			if !t.Reached(u) {
+				continue
+			}
+			for v, weight := range g.Adjacent(u) {
+				if !t.Relax(u, v, weight) {
+					continue
+				}
+				changed = true
+				if pass >= len(vertices) {
+					// After V-1 passes every simple path has been tried, so
+					// the predecessors of v must now run round a cycle
+					return nil, negativeCycle(g, t.Prev, v)
+				}
+			}
+		}
+		if !changed {
+			return t, nil
+		}
+	}
+}
+
+// SPFA (Shortest Path Faster Algorithm) computes the same result as
+// ShortestPaths, but only revisits the edges of vertices whose distance
+// changed, which it keeps in a FIFO queue. It is usually much faster than
+// Bellman-Ford, though its worst case is the same O(V·E). A vertex whose
+// best path has V or more edges reveals a negative cycle, reported as a
+// *NegativeCycleError[V].
+func SPFA[V comparable](g graph.Graph[V], source V) (*graph.PathTree[V], error) {
+	t, err := newTree(g, source)
+	if err != nil {
+		return nil, err
+	}
+	n := len(g.Vertices())
+	edges := map[V]int{source: 0} // number of edges on each best path
+	queued := map[V]bool{source: true}
+	queue := []V{source}
+	for len(queue) > 0 {
+		u := queue[0]
+		queue = queue[1:]
+		queued[u] = false
+		for v, weight := range g.Adjacent(u) {
+			if !t.Relax(u, v, weight) {
+				continue
+			}
+			edges[v] = edges[u] + 1
+			if edges[v] >= n {
+				// A path with V edges repeats a vertex, and it only got
+				// shorter by going round a negative cycle. The cycle is
+				// usually among the predecessors of v; if they have
+				// changed since, Bellman-Ford finds it.
+				if err := negativeCycle(g, t.Prev, v); err != nil {
+					return nil, err
+				}
+				return ShortestPaths(g, source)
+			}
+			if !queued[v] {
+				queued[v] = true
+				queue = append(queue, v)
+			}
+		}
+	}
+	return t, nil
+}
+
+// negativeCycle returns the error for the cycle found by following prev
+// back from v, or nil if the walk ends at the source instead. A cycle of
+// predecessors always has negative weight.
+func negativeCycle[V comparable](g graph.Graph[V], prev map[V]V, v V) error {
+	seen := make(map[V]bool)
+	for !seen[v] {
+		seen[v] = true
+		u, ok := prev[v]
+		if !ok {
+			return nil
+		}
+		v = u
+	}
+	// v is on the cycle; collect it going backwards
+	cycle := []V{v}
+	for u := prev[v]; u != v; u = prev[u] {
+		cycle = append(cycle, u)
+	}
+	slices.Reverse(cycle)
+
+	weight := 0.0
+	for i, u := range cycle {
+		next := cycle[(i+1)%len(cycle)]
+		for w, edge := range g.Adjacent(u) {
+			if w == next {
+				weight += edge
+				break
+			}
+		}
+	}
+	return &NegativeCycleError[V]{Cycle: cycle, Weight: weight}
+}
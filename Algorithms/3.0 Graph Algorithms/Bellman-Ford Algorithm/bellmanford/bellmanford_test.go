@@ -0,0 +1,157 @@
+package bellmanford
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/graphs/graph"
+	"go-mastery/graphs/graphtest"
+)
+
+var algorithms = []struct {
+	name string
+	run  func(graph.Graph[int], int) (*graph.PathTree[int], error)
+}{
+	{"BellmanFord", ShortestPaths[int]},
+	{"SPFA", SPFA[int]},
+}
+
+// floyd returns all-pairs distances; dist[v][v] < 0 means v is on a
+// negative cycle
+func floyd(g *graph.AdjacencyList[int], n int) [][]float64 {
+	dist := make([][]float64, n)
+	for i := range dist {
+		dist[i] = make([]float64, n)
+		for j := range dist[i] {
+			dist[i][j] = math.Inf(1)
+		}
+		dist[i][i] = 0
+	}
+	for _, e := range g.Edges() {
+		dist[e.From][e.To] = min(dist[e.From][e.To], e.Weight)
+		if !g.Directed() {
+			dist[e.To][e.From] = min(dist[e.To][e.From], e.Weight)
+		}
+	}
+	for k := range n {
+		for i := range n {
+			for j := range n {
+				dist[i][j] = min(dist[i][j], dist[i][k]+dist[k][j])
+			}
+		}
+	}
+	return dist
+}
+
+// randomGraph returns a directed graph with some negative weights. With
+// potentials the weights are reduced costs of a graph without negative
+// cycles; without them, negative cycles are likely.
+func randomGraph(rng *rand.Rand, n int, potentials bool) *graph.AdjacencyList[int] {
+	weight := graphtest.Weights(rng, -2, 8)
+	if potentials {
+		weight = graphtest.ReducedCosts(rng, n)
+	}
+	return graphtest.Random(rng, true, n, 2*n, weight)
+}
+
+func TestShortestPaths(t *testing.T) {
+	const n = 12
+	rng := graphtest.Rand(t)
+	for range 200 {
+		g := randomGraph(rng, n, true)
+		want := floyd(g, n)[0]
+		for _, alg := range algorithms {
+			tree, err := alg.run(g, 0)
+			if err != nil {
+				t.Fatalf("%s: %v", alg.name, err)
+			}
+			for v, d := range want {
+				if math.IsInf(d, 1) {
+					if tree.Reached(v) {
+						t.Fatalf("%s: unreachable %d has distance %v", alg.name, v, tree.Dist[v])
+					}
+					continue
+				}
+				if tree.Dist[v] != d {
+					t.Fatalf("%s: distance to %d = %v; want %v", alg.name, v, tree.Dist[v], d)
+				}
+				path, total := tree.PathTo(v), 0.0
+				for i := 1; i < len(path); i++ {
+					w, _ := g.Weight(path[i-1], path[i])
+					total += w
+				}
+				if path[0] != 0 || path[len(path)-1] != v || total != d {
+					t.Fatalf("%s: PathTo(%d) = %v weighs %v; want %v", alg.name, v, path, total, d)
+				}
+			}
+		}
+	}
+}
+
+func TestNegativeCycle(t *testing.T) {
+	const n = 8
+	found := 0
+	rng := graphtest.Rand(t)
+	for range 500 {
+		g := randomGraph(rng, n, false)
+		dist := floyd(g, n)
+		hasCycle := false
+		for v := range n {
+			if !math.IsInf(dist[0][v], 1) && dist[v][v] < 0 {
+				hasCycle = true
+			}
+		}
+		for _, alg := range algorithms {
+			_, err := alg.run(g, 0)
+			var cycleErr *NegativeCycleError[int]
+			if !hasCycle {
+				if err != nil {
+					t.Fatalf("%s: %v without a reachable negative cycle", alg.name, err)
+				}
+				continue
+			}
+			if !errors.As(err, &cycleErr) {
+				t.Fatalf("%s: err = %v; want a negative cycle", alg.name, err)
+			}
+			found++
+			cycle, total := cycleErr.Cycle, 0.0
+			for i, u := range cycle {
+				w, ok := g.Weight(u, cycle[(i+1)%len(cycle)])
+				if !ok {
+					t.Fatalf("%s: cycle %v uses a missing edge", alg.name, cycle)
+				}
+				total += w
+			}
+			sorted := slices.Clone(cycle)
+			slices.Sort(sorted)
+			if total >= 0 || total != cycleErr.Weight || len(slices.Compact(sorted)) != len(cycle) {
+				t.Fatalf("%s: cycle %v weighs %v (reported %v)", alg.name, cycle, total, cycleErr.Weight)
+			}
+			if math.IsInf(dist[0][cycle[0]], 1) {
+				t.Fatalf("%s: cycle %v cannot be reached from 0", alg.name, cycle)
+			}
+		}
+	}
+	if found == 0 {
+		t.Fatal("no graph had a negative cycle")
+	}
+}
+
+func TestErrors(t *testing.T) {
+	g := graph.NewUndirected[string]()
+	g.AddWeightedEdge("a", "b", 3)
+	g.AddWeightedEdge("b", "c", -1)
+	for _, run := range []func(graph.Graph[string], string) (*graph.PathTree[string], error){ShortestPaths[string], SPFA[string]} {
+		_, err := run(g, "a")
+		var cycleErr *NegativeCycleError[string]
+		if !errors.As(err, &cycleErr) || len(cycleErr.Cycle) != 2 || cycleErr.Weight != -2 {
+			t.Fatalf("undirected negative edge: err = %v; want a two-vertex cycle", err)
+		}
+		if _, err := run(g, "z"); !errors.Is(err, ErrNoVertex) {
+			t.Fatalf("missing source: err = %v; want ErrNoVertex", err)
+		}
+	}
+}
@@ -0,0 +1,90 @@
+// Package bfs implements breadth-first search over any graph.Graph.
+package bfs
+
+import (
+	"slices"
+
+	"go-mastery/graphs/graph"
+)
+
+// Visitor is called with each vertex as it is visited and its distance in
+// edges from the start. Returning false stops the search.
+type Visitor[V comparable] func(v V, dist int) bool
+
+// Result is what a breadth-first search found
+type Result[V comparable] struct {
+	// Order holds the visited vertices: the start, then the vertices one
+	// edge away, then two, and so on
+	Order []V
+	// Dist maps every vertex discovered so far to the number of edges on
+	// a shortest path from the start. If the search was stopped early it
+	// also holds vertices that were queued but not yet visited.
+	Dist map[V]int
+	// Parent maps every discovered vertex except the start to the vertex
+	// it was discovered from. Following it leads back to the start along a
+	// shortest path.
+	Parent map[V]V
+}
+
+// Reached reports whether v was discovered by the search
+func (r *Result[V]) Reached(v V) bool {
+	_, ok := r.Dist[v]
+	return ok
+}
+
+// PathTo returns a shortest path from the start to v, both included, or
+// nil if v was not discovered
+func (r *Result[V]) PathTo(v V) []V {
+	if !r.Reached(v) {
+		return nil
+	}
+	path := []V{v}
+	for {
+		parent, ok := r.Parent[v]
+		if !ok {
+			break
+		}
+		v = parent
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Search runs a breadth-first search from start, calling visit (if it is
+// not nil) with each vertex in visiting order. The search stops when every
+// reachable vertex has been visited or visit returns false. If start is not
+// in the graph, the result is empty.
+func Search[V comparable](g graph.Graph[V], start V, visit Visitor[V]) *Result[V] {
+	r := &Result[V]{Dist: make(map[V]int), Parent: make(map[V]V)}
+	if !g.HasVertex(start) {
+		return r
+	}
+	r.Dist[start] = 0
+	queue := []V{start}
+	// The queue is never shortened: the vertices before head have been
+	// visited and, in order, are the result
+	for head := 0; head < len(queue); head++ {
+		v := queue[head]
+		r.Order = queue[:head+1]
+		if visit != nil && !visit(v, r.Dist[v]) {
+			break
+		}
+		for next := range g.Adjacent(v) {
+			if !r.Reached(next) {
+				r.Dist[next] = r.Dist[v] + 1
+				r.Parent[next] = v
+				queue = append(queue, next)
+			}
+		}
+	}
+	r.Order = slices.Clip(r.Order)
+	return r
+}
+
+// Order returns the vertices reachable from start in the order a
+// breadth-first search visits them. It returns nil if start is not in the
+// graph.
+func Order[V comparable](g graph.Graph[V], start V) []V {
+	return Search(g, start, nil).Order
+}
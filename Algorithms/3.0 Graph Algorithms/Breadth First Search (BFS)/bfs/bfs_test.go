@@ -0,0 +1,127 @@
+package bfs
+
+import (
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+func TestOrder(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddEdge("A", "B")
+	g.AddEdge("A", "C")
+	g.AddEdge("B", "D")
+	g.AddEdge("C", "E")
+	g.AddEdge("D", "A") // a cycle back to the start
+	g.AddVertex("F")    // unreachable
+
+	if got, want := Order(g, "A"), []string{"A", "B", "C", "D", "E"}; !slices.Equal(got, want) {
+		t.Fatalf("Order(A) = %v; want %v", got, want)
+	}
+	if got := Order(g, "Z"); got != nil {
+		t.Fatalf("Order(Z) = %v; want nil", got)
+	}
+}
+
+// hops computes the number of edges on a shortest path from start to each
+// reachable vertex by relaxing every edge until nothing changes
+func hops(g *graph.AdjacencyList[int], start int) map[int]int {
+	dist := map[int]int{start: 0}
+	for changed := true; changed; {
+		changed = false
+		for _, v := range g.Vertices() {
+			d, ok := dist[v]
+			if !ok {
+				continue
+			}
+			for next := range g.Adjacent(v) {
+				if old, ok := dist[next]; !ok || d+1 < old {
+					dist[next] = d + 1
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+func TestSearch(t *testing.T) {
+	for range 100 {
+		g := graph.NewDirected[int]()
+		for v := range 20 {
+			g.AddVertex(v)
+		}
+		for range 30 {
+			g.AddEdge(rand.IntN(20), rand.IntN(20))
+		}
+		var visited []int
+		r := Search(g, 0, func(v, dist int) bool {
+			visited = append(visited, v)
+			return true
+		})
+		want := hops(g, 0)
+		if !slices.Equal(visited, r.Order) || len(r.Order) != len(want) {
+			t.Fatalf("visited %v, Order %v; want %d vertices", visited, r.Order, len(want))
+		}
+		for v, d := range want {
+			if r.Dist[v] != d {
+				t.Fatalf("Dist[%d] = %d; want %d", v, r.Dist[v], d)
+			}
+			path := r.PathTo(v)
+			if len(path) != d+1 || path[0] != 0 || path[d] != v {
+				t.Fatalf("PathTo(%d) = %v; want %d edges", v, path, d)
+			}
+			for i := 1; i < len(path); i++ {
+				if !g.HasEdge(path[i-1], path[i]) {
+					t.Fatalf("PathTo(%d) = %v uses a missing edge", v, path)
+				}
+			}
+		}
+		for i := 1; i < len(r.Order); i++ {
+			if r.Dist[r.Order[i-1]] > r.Dist[r.Order[i]] {
+				t.Fatalf("Order %v is not by distance", r.Order)
+			}
+		}
+	}
+}
+
+func TestSearchStop(t *testing.T) {
+	g := graph.NewUndirected[string]()
+	g.AddEdge("home", "shop")
+	g.AddEdge("home", "park")
+	g.AddEdge("shop", "bank")
+	g.AddEdge("bank", "school")
+
+	r := Search(g, "home", func(v string, dist int) bool { return v != "shop" })
+	if want := []string{"home", "shop"}; !slices.Equal(r.Order, want) {
+		t.Fatalf("Order = %v; want %v", r.Order, want)
+	}
+	if r.Reached("bank") {
+		t.Fatalf("the neighbours of the vertex that stopped the search were queued")
+	}
+	if !r.Reached("park") || r.PathTo("school") != nil {
+		t.Fatalf("Reached(park) = false or PathTo(school) = %v", r.PathTo("school"))
+	}
+	if r := Search(g, "nowhere", nil); len(r.Order) != 0 || r.Reached("nowhere") {
+		t.Fatalf("search from a missing vertex found %v", r.Order)
+	}
+}
+
+func TestOrderGrid(t *testing.T) {
+	g := graph.NewGrid([][]int{
+		{0, 0, 0},
+		{1, 1, 0},
+		{0, 0, 0},
+	})
+	got := Order(g, cell(2, 0))
+	want := []graph.Cell{cell(2, 0), cell(2, 1), cell(2, 2), cell(1, 2), cell(0, 2), cell(0, 1), cell(0, 0)}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Order = %v; want %v", got, want)
+	}
+}
+
+func cell(row, col int) graph.Cell {
+	return graph.Cell{Row: row, Col: col}
+}
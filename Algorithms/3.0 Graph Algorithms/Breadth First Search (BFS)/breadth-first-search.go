@@ -2,50 +2,45 @@ package main
 
 import (
 	"fmt"
-)
-
-// Graph represents an adjacency list graph.
-type Graph struct {
-	vertices map[string][]string
-}
-
-// NewGraph creates a new Graph.
-func NewGraph() *Graph {
-	return &Graph{vertices: make(map[string][]string)}
-}
-
-// AddEdge adds an edge to the graph.
-func (g *Graph) AddEdge(from, to string) {
-	g.vertices[from] = append(g.vertices[from], to)
-}
-
-// BFS performs a breadth-first search starting from the given vertex.
-func (g *Graph) BFS(start string) {
-	visited := make(map[string]bool)
-	queue := []string{start}
+	"strings"
 
-	for len(queue) > 0 {
-		vertex := queue[0]
-		queue = queue[1:]
-		if visited[vertex] {
-			continue
-		}
-		fmt.Println(vertex)
-		visited[vertex] = true
-		for _, neighbor := range g.vertices[vertex] {
-			if !visited[neighbor] {
-				queue = append(queue, neighbor)
-			}
-		}
-	}
-}
+	"go-mastery/breadth-first-search/bfs"
+	"go-mastery/graphs/graph"
+)
 
 func main() {
-	g := NewGraph()
+	g := graph.NewDirected[string]()
 	g.AddEdge("A", "B")
 	g.AddEdge("A", "C")
 	g.AddEdge("B", "D")
 	g.AddEdge("C", "E")
+	g.AddEdge("D", "F")
+	g.AddEdge("E", "F")
 
-	g.BFS("A")
+	result := bfs.Search(g, "A", nil)
+	for _, vertex := range result.Order {
+		fmt.Printf("%s (distance %d)\n", vertex, result.Dist[vertex])
+	}
+	// Output:
+	// A (distance 0)
+	// B (distance 1)
+	// C (distance 1)
+	// D (distance 2)
+	// E (distance 2)
+	// F (distance 3)
+
+	fmt.Println("Path to F:", strings.Join(result.PathTo("F"), " -> ")) // Output: Path to F: A -> B -> D -> F
+
+	// A visitor can stop the search as soon as it finds what it wants.
+	// Because BFS visits vertices by distance, the first match is a
+	// closest one.
+	var found string
+	bfs.Search(g, "A", func(v string, dist int) bool {
+		if v == "D" || v == "E" {
+			found = v
+			return false
+		}
+		return true
+	})
+	fmt.Println("Closest of D and E:", found) // Output: Closest of D and E: D
 }
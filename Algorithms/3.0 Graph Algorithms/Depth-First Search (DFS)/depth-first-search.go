@@ -1,41 +1,35 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"strings"
 
-// Graph represents an adjacency list graph.
-type Graph struct {
-	vertices map[string][]string
-}
-
-// NewGraph creates a new Graph.
-func NewGraph() *Graph {
-	return &Graph{vertices: make(map[string][]string)}
-}
-
-// AddEdge adds an edge to the graph.
-func (g *Graph) AddEdge(from, to string) {
-	g.vertices[from] = append(g.vertices[from], to)
-}
-
-// DFS performs a depth-first search starting from the given vertex.
-func (g *Graph) DFS(start string, visited map[string]bool) {
-	if visited[start] {
-		return
-	}
-	visited[start] = true
-	fmt.Println(start)
-	for _, neighbor := range g.vertices[start] {
-		g.DFS(neighbor, visited)
-	}
-}
+	"go-mastery/depth-first-search/dfs"
+	"go-mastery/graphs/graph"
+)
 
 func main() {
-	g := NewGraph()
+	g := graph.NewDirected[string]()
 	g.AddEdge("A", "B")
 	g.AddEdge("A", "C")
 	g.AddEdge("B", "D")
 	g.AddEdge("C", "E")
 
-	visited := make(map[string]bool)
-	g.DFS("A", visited)
+	result := dfs.Search(g, "A", nil)
+	fmt.Println("Visited: ", result.Order)    // Output: Visited:  [A B D C E]
+	fmt.Println("Finished:", result.Finished) // Output: Finished: [D B E C A]
+	fmt.Println("Path to E:", strings.Join(result.PathTo("E"), " -> "))
+	// Output: Path to E: A -> C -> E
+
+	// A visitor can stop the search early, here as soon as it finds a
+	// vertex that depends on D
+	var dependent string
+	dfs.Search(g, "A", func(v string) bool {
+		if g.HasEdge(v, "D") {
+			dependent = v
+			return false
+		}
+		return true
+	})
+	fmt.Println("Depends on D:", dependent) // Output: Depends on D: B
 }
@@ -0,0 +1,121 @@
+// Package dfs implements depth-first search over any graph.Graph.
+package dfs
+
+import (
+	"slices"
+
+	"go-mastery/graphs/graph"
+)
+
+// Visitor is called with each vertex when the search first reaches it.
+// Returning false stops the search.
+type Visitor[V comparable] func(v V) bool
+
+// Result is what a depth-first search found
+type Result[V comparable] struct {
+	// Order holds the visited vertices in the order they were first
+	// reached (preorder)
+	Order []V
+	// Finished holds the vertices whose neighbours have all been explored,
+	// in the order that happened (postorder). In a directed acyclic graph
+	// each vertex comes after every vertex it can reach.
+	Finished []V
+	// Parent maps every visited vertex except the start to the vertex it
+	// was reached from
+	Parent map[V]V
+}
+
+// Reached reports whether v was visited
+func (r *Result[V]) Reached(v V) bool {
+	_, ok := r.Parent[v]
+	return ok || len(r.Order) > 0 && r.Order[0] == v
+}
+
+// PathTo returns the path in the search tree from the start to v, both
+// included, or nil if v was not visited. Unlike a BFS path it need not be
+// a shortest one.
+func (r *Result[V]) PathTo(v V) []V {
+	if !r.Reached(v) {
+		return nil
+	}
+	path := []V{v}
+	for {
+		parent, ok := r.Parent[v]
+		if !ok {
+			break
+		}
+		v = parent
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// frame is a vertex on the current search path with the neighbours that
+// are still to be explored
+type frame[V comparable] struct {
+	vertex V
+	next   []V
+}
+
+// Search runs a depth-first search from start, calling visit (if it is not
+// nil) with each vertex as it is first reached. The search stops when every
+// reachable vertex has been visited or visit returns false. If start is not
+// in the graph, the result is empty.
+//
+// Neighbours are explored in the order of g.Adjacent, giving the same order
+// as the textbook recursive search. The search path is kept in a slice
+// rather than on the call stack, so very deep graphs such as long chains do
+// not exhaust the stack.
+func Search[V comparable](g graph.Graph[V], start V, visit Visitor[V]) *Result[V] {
+	r := &Result[V]{Parent: make(map[V]V)}
+	if !g.HasVertex(start) {
+		return r
+	}
+	visited := make(map[V]bool)
+	var path []frame[V]
+
+	// enter visits v and pushes it on the path, reporting whether to go on
+	enter := func(v V) bool {
+		visited[v] = true
+		r.Order = append(r.Order, v)
+		if visit != nil && !visit(v) {
+			return false
+		}
+		var next []V
+		for n := range g.Adjacent(v) {
+			next = append(next, n)
+		}
+		path = append(path, frame[V]{vertex: v, next: next})
+		return true
+	}
+
+	if !enter(start) {
+		return r
+	}
+	for len(path) > 0 {
+		top := &path[len(path)-1]
+		if len(top.next) == 0 {
+			r.Finished = append(r.Finished, top.vertex)
+			path = path[:len(path)-1]
+			continue
+		}
+		n := top.next[0]
+		top.next = top.next[1:]
+		if visited[n] {
+			continue
+		}
+		r.Parent[n] = top.vertex
+		if !enter(n) {
+			break
+		}
+	}
+	return r
+}
+
+// Order returns the vertices reachable from start in the order a
+// depth-first search visits them. It returns nil if start is not in the
+// graph.
+func Order[V comparable](g graph.Graph[V], start V) []V {
+	return Search(g, start, nil).Order
+}
@@ -0,0 +1,142 @@
+package dfs
+
+import (
+	"iter"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+func TestOrder(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddEdge("A", "B")
+	g.AddEdge("A", "C")
+	g.AddEdge("B", "D")
+	g.AddEdge("C", "E")
+	g.AddEdge("D", "A") // a cycle back to the start
+	g.AddVertex("F")    // unreachable
+
+	if got, want := Order(g, "A"), []string{"A", "B", "D", "C", "E"}; !slices.Equal(got, want) {
+		t.Fatalf("Order(A) = %v; want %v", got, want)
+	}
+	if got := Order(g, "Z"); got != nil {
+		t.Fatalf("Order(Z) = %v; want nil", got)
+	}
+}
+
+// recursive is the textbook recursive search, returning the preorder and
+// postorder
+func recursive(g graph.Graph[int], start int) (pre, post []int) {
+	visited := make(map[int]bool)
+	var visit func(v int)
+	visit = func(v int) {
+		visited[v] = true
+		pre = append(pre, v)
+		for next := range g.Adjacent(v) {
+			if !visited[next] {
+				visit(next)
+			}
+		}
+		post = append(post, v)
+	}
+	visit(start)
+	return pre, post
+}
+
+func TestSearch(t *testing.T) {
+	for range 100 {
+		g := graph.NewDirected[int]()
+		if rand.IntN(2) == 0 {
+			g = graph.NewUndirected[int]()
+		}
+		for v := range 20 {
+			g.AddVertex(v)
+		}
+		for range 30 {
+			g.AddEdge(rand.IntN(20), rand.IntN(20))
+		}
+		r := Search(g, 0, nil)
+		pre, post := recursive(g, 0)
+		if !slices.Equal(r.Order, pre) || !slices.Equal(r.Finished, post) {
+			t.Fatalf("Order %v, Finished %v; want %v, %v", r.Order, r.Finished, pre, post)
+		}
+		for _, v := range r.Order {
+			path := r.PathTo(v)
+			if path[0] != 0 || path[len(path)-1] != v {
+				t.Fatalf("PathTo(%d) = %v", v, path)
+			}
+			for i := 1; i < len(path); i++ {
+				if !g.HasEdge(path[i-1], path[i]) {
+					t.Fatalf("PathTo(%d) = %v uses a missing edge", v, path)
+				}
+			}
+		}
+	}
+}
+
+func TestSearchStop(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddEdge("A", "B")
+	g.AddEdge("B", "C")
+	g.AddEdge("A", "D")
+
+	var visited []string
+	r := Search(g, "A", func(v string) bool {
+		visited = append(visited, v)
+		return v != "C"
+	})
+	if want := []string{"A", "B", "C"}; !slices.Equal(r.Order, want) || !slices.Equal(visited, want) {
+		t.Fatalf("Order = %v, visited %v; want %v", r.Order, visited, want)
+	}
+	if r.Reached("D") || len(r.Finished) != 0 {
+		t.Fatalf("search went on after the visitor stopped it: %v", r.Finished)
+	}
+	if got := r.PathTo("C"); !slices.Equal(got, []string{"A", "B", "C"}) {
+		t.Fatalf("PathTo(C) = %v", got)
+	}
+	if r := Search(g, "Z", nil); len(r.Order) != 0 || r.Reached("Z") {
+		t.Fatalf("search from a missing vertex found %v", r.Order)
+	}
+}
+
+// chain is the graph 0 -> 1 -> ... -> n-1, computed rather than stored
+type chain int
+
+func (n chain) Directed() bool       { return true }
+func (n chain) Vertices() []int      { return nil }
+func (n chain) HasVertex(v int) bool { return v >= 0 && v < int(n) }
+func (n chain) Adjacent(v int) iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		if v+1 < int(n) {
+			yield(v+1, 1)
+		}
+	}
+}
+
+// TestSearchDeep walks a chain far longer than a recursive search would
+// comfortably handle
+func TestSearchDeep(t *testing.T) {
+	const n = 200_000
+	r := Search(chain(n), 0, nil)
+	if len(r.Order) != n || r.Order[n-1] != n-1 || r.Finished[0] != n-1 {
+		t.Fatalf("visited %d of %d vertices", len(r.Order), n)
+	}
+}
+
+func TestOrderGrid(t *testing.T) {
+	g := graph.NewGrid([][]int{
+		{0, 0},
+		{0, 0},
+	})
+	got := Order(g, cell(0, 0))
+	want := []graph.Cell{cell(0, 0), cell(1, 0), cell(1, 1), cell(0, 1)}
+	if !slices.Equal(got, want) {
+		t.Fatalf("Order = %v; want %v", got, want)
+	}
+}
+
+func cell(row, col int) graph.Cell {
+	return graph.Cell{Row: row, Col: col}
+}
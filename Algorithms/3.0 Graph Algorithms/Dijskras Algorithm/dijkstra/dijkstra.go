@@ -0,0 +1,89 @@
+// Package dijkstra finds shortest paths in graphs with non-negative edge
+// weights.
+package dijkstra
+
+import (
+	"errors"
+	"fmt"
+
+	"go-mastery/graphs/graph"
+	"go-mastery/heaps/pq"
+)
+
+var (
+	// ErrNegativeWeight is returned, wrapped with the edge, when the search
+	// meets an edge with a negative weight
+	ErrNegativeWeight = errors.New("negative edge weight")
+	// ErrNoVertex is returned, wrapped with the vertex, when the source or
+	// destination is not in the graph
+	ErrNoVertex = errors.New("vertex not in graph")
+	// ErrNoPath is returned by ShortestPath when the destination cannot be
+	// reached
+	ErrNoPath = errors.New("no path")
+)
+
+// ShortestPaths returns the shortest paths from source to every vertex it
+// can reach. It fails if source is not in the graph or a reachable edge
+// has a negative weight.
+func ShortestPaths[V comparable](g graph.Graph[V], source V) (*graph.PathTree[V], error) {
+	if !g.HasVertex(source) {
+		return nil, fmt.Errorf("%w: %v", ErrNoVertex, source)
+	}
+	return search(g, source, func(V) bool { return false })
+}
+
+// ShortestPath returns a shortest path from src to dst, both included, and
+// its length. The search stops as soon as dst is settled, so only the part
+// of the graph closer to src than dst is explored, and only those edges
+// are checked for negative weights.
+func ShortestPath[V comparable](g graph.Graph[V], src, dst V) ([]V, float64, error) {
+	for _, v := range []V{src, dst} {
+		if !g.HasVertex(v) {
+			return nil, 0, fmt.Errorf("%w: %v", ErrNoVertex, v)
+		}
+	}
+	tree, err := search(g, src, func(v V) bool { return v == dst })
+	if err != nil {
+		return nil, 0, err
+	}
+	if !tree.Reached(dst) {
+		return nil, 0, fmt.Errorf("%w from %v to %v", ErrNoPath, src, dst)
+	}
+	return tree.PathTo(dst), tree.Dist[dst], nil
+}
+
+// search settles vertices in order of distance from source until stop
+// returns true for one or every reachable vertex is settled. When it stops
+// early, Dist and Prev also hold the tentative entries of vertices that
+// were discovered but not settled; the caller only reads settled ones.
+func search[V comparable](g graph.Graph[V], source V, stop func(V) bool) (*graph.PathTree[V], error) {
+	tree := graph.NewPathTree(source)
+	settled := make(map[V]bool)
+
+	// The queue holds vertices keyed by their tentative distance. Instead
+	// of moving a vertex when its distance improves, it is pushed again;
+	// the old entries are stale and are skipped when popped.
+	queue := pq.NewMin[V, float64]()
+	queue.Push(source, 0)
+	for queue.Len() > 0 {
+		item, _ := queue.Pop()
+		current := item.Value
+		if settled[current] {
+			continue
+		}
+		// The first entry popped for a vertex has its final distance
+		settled[current] = true
+		if stop(current) {
+			break
+		}
+		for next, weight := range g.Adjacent(current) {
+			if weight < 0 {
+				return nil, fmt.Errorf("%w: %v -> %v (%g)", ErrNegativeWeight, current, next, weight)
+			}
+			if tree.Relax(current, next, weight) {
+				queue.Push(next, tree.Dist[next])
+			}
+		}
+	}
+	return tree, nil
+}
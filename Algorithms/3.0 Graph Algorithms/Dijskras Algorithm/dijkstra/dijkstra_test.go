@@ -0,0 +1,180 @@
+package dijkstra
+
+import (
+	"errors"
+	"iter"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+
+	"go-mastery/graphs/graph"
+	"go-mastery/graphs/graphtest"
+)
+
+// relax computes shortest distances by relaxing every edge until nothing
+// changes
+func relax(g *graph.AdjacencyList[int], source int) map[int]float64 {
+	dist := make(map[int]float64)
+	for _, v := range g.Vertices() {
+		dist[v] = math.Inf(1)
+	}
+	dist[source] = 0
+	for changed := true; changed; {
+		changed = false
+		for _, v := range g.Vertices() {
+			for next, w := range g.Adjacent(v) {
+				if dist[v]+w < dist[next] {
+					dist[next] = dist[v] + w
+					changed = true
+				}
+			}
+		}
+	}
+	return dist
+}
+
+// checkPath fails unless path runs from src to dst over edges of g with a
+// total weight of cost
+func checkPath(t *testing.T, g *graph.AdjacencyList[int], path []int, src, dst int, cost float64) {
+	t.Helper()
+	if len(path) == 0 || path[0] != src || path[len(path)-1] != dst {
+		t.Fatalf("path %v does not run from %d to %d", path, src, dst)
+	}
+	total := 0.0
+	for i := 1; i < len(path); i++ {
+		w, ok := g.Weight(path[i-1], path[i])
+		if !ok {
+			t.Fatalf("path %v uses a missing edge %d -> %d", path, path[i-1], path[i])
+		}
+		total += w
+	}
+	if total != cost {
+		t.Fatalf("path %v weighs %v; want %v", path, total, cost)
+	}
+}
+
+// randomGraph returns a directed or undirected graph with weights from 0
+// to 9
+func randomGraph(rng *rand.Rand) *graph.AdjacencyList[int] {
+	return graphtest.Random(rng, rng.IntN(2) == 0, 15, 40, graphtest.Weights(rng, 0, 10))
+}
+
+func TestShortestPaths(t *testing.T) {
+	rng := graphtest.Rand(t)
+	for range 100 {
+		g := randomGraph(rng)
+		tree, err := ShortestPaths(g, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for v, d := range relax(g, 0) {
+			if math.IsInf(d, 1) {
+				if tree.Reached(v) || tree.PathTo(v) != nil {
+					t.Fatalf("unreachable vertex %d has distance %v", v, tree.Dist[v])
+				}
+				continue
+			}
+			if tree.Dist[v] != d {
+				t.Fatalf("distance to %d = %v; want %v", v, tree.Dist[v], d)
+			}
+			checkPath(t, g, tree.PathTo(v), 0, v, d)
+		}
+	}
+}
+
+func TestShortestPath(t *testing.T) {
+	rng := graphtest.Rand(t)
+	for range 100 {
+		g := randomGraph(rng)
+		dst := rng.IntN(15)
+		want := relax(g, 0)[dst]
+		path, cost, err := ShortestPath(g, 0, dst)
+		if math.IsInf(want, 1) {
+			if !errors.Is(err, ErrNoPath) {
+				t.Fatalf("ShortestPath to unreachable %d: %v, %v", dst, path, err)
+			}
+			continue
+		}
+		if err != nil || cost != want {
+			t.Fatalf("ShortestPath(0, %d) = %v, %v; want cost %v", dst, cost, err, want)
+		}
+		checkPath(t, g, path, 0, dst, cost)
+	}
+}
+
+// counting wraps a graph and records which vertices were expanded
+type counting struct {
+	graph.Graph[int]
+	expanded []int
+}
+
+func (c *counting) Adjacent(v int) iter.Seq2[int, float64] {
+	c.expanded = append(c.expanded, v)
+	return c.Graph.Adjacent(v)
+}
+
+func TestShortestPathStopsEarly(t *testing.T) {
+	// A line 0 - 1 - 2 - ... - 9 searched from 0 to 3
+	g := graph.NewUndirected[int]()
+	for v := 1; v < 10; v++ {
+		g.AddWeightedEdge(v-1, v, 1)
+	}
+	// Stale entries: 4 is first reached over a heavy edge, then improved
+	g.AddWeightedEdge(0, 4, 100)
+	c := &counting{Graph: g}
+	path, cost, err := ShortestPath[int](c, 0, 3)
+	if err != nil || cost != 3 || !slices.Equal(path, []int{0, 1, 2, 3}) {
+		t.Fatalf("ShortestPath(0, 3) = %v, %v, %v", path, cost, err)
+	}
+	if want := []int{0, 1, 2}; !slices.Equal(c.expanded, want) {
+		t.Fatalf("expanded %v; want only %v", c.expanded, want)
+	}
+
+	c.expanded = nil
+	if _, err := ShortestPaths[int](c, 0); err != nil {
+		t.Fatal(err)
+	}
+	if len(c.expanded) != 10 {
+		t.Fatalf("expanded %v; want each vertex once", c.expanded)
+	}
+}
+
+func TestErrors(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddWeightedEdge("a", "b", 2)
+	g.AddWeightedEdge("b", "c", -1)
+	g.AddVertex("d")
+
+	if _, err := ShortestPaths(g, "a"); !errors.Is(err, ErrNegativeWeight) {
+		t.Fatalf("ShortestPaths with a negative edge: err = %v", err)
+	}
+	if _, _, err := ShortestPath(g, "a", "b"); err != nil {
+		t.Fatalf("ShortestPath(a, b) stops before the negative edge: err = %v", err)
+	}
+	if _, _, err := ShortestPath(g, "a", "d"); !errors.Is(err, ErrNegativeWeight) {
+		t.Fatalf("ShortestPath(a, d): err = %v; want ErrNegativeWeight", err)
+	}
+	if _, _, err := ShortestPath(g, "d", "a"); !errors.Is(err, ErrNoPath) {
+		t.Fatalf("ShortestPath(d, a): err = %v; want ErrNoPath", err)
+	}
+	if _, _, err := ShortestPath(g, "a", "z"); !errors.Is(err, ErrNoVertex) {
+		t.Fatalf("ShortestPath(a, z): err = %v; want ErrNoVertex", err)
+	}
+	if _, err := ShortestPaths(g, "z"); !errors.Is(err, ErrNoVertex) {
+		t.Fatalf("ShortestPaths(z): err = %v; want ErrNoVertex", err)
+	}
+}
+
+func TestShortestPathsGrid(t *testing.T) {
+	g := graph.NewGrid([][]int{
+		{0, 1, 0},
+		{0, 1, 0},
+		{0, 0, 0},
+	})
+	src, dst := graph.Cell{Row: 0, Col: 0}, graph.Cell{Row: 0, Col: 2}
+	path, cost, err := ShortestPath(g, src, dst)
+	if err != nil || cost != 6 || len(path) != 7 {
+		t.Fatalf("path around the wall = %v, %v, %v; want 6 moves", path, cost, err)
+	}
+}
@@ -1,120 +1,56 @@
 package main
 
 import (
-	"container/heap"
 	"fmt"
-	"math"
-)
-
-// Edge represents a weighted edge to a neighboring vertex.
-type Edge struct {
-	Target string
-	Weight float64
-}
-
-// Graph represents a weighted graph using an adjacency list.
-type Graph struct {
-	Vertices map[string][]Edge
-}
-
-// NewGraph initializes a new Graph.
-func NewGraph() *Graph {
-	return &Graph{Vertices: make(map[string][]Edge)}
-}
-
-// AddEdge adds a directed, weighted edge from one vertex to another.
-func (g *Graph) AddEdge(from, to string, weight float64) {
-	g.Vertices[from] = append(g.Vertices[from], Edge{Target: to, Weight: weight})
-}
-
-// Item represents a vertex and its current shortest distance.
-type Item struct {
-	Vertex   string
-	Distance float64
-	Priority float64
-	Index    int
-}
-
-// PriorityQueue implements a min-heap for Items.
-type PriorityQueue []*Item
-
-func (pq PriorityQueue) Len() int { return len(pq) }
-
-// Less prioritizes items with smaller distances.
-func (pq PriorityQueue) Less(i, j int) bool {
-	return pq[i].Distance < pq[j].Distance
-}
-
-func (pq PriorityQueue) Swap(i, j int) {
-	pq[i], pq[j] = pq[j], pq[i]
-	pq[i].Index = i
-	pq[j].Index = j
-}
-
-// Push adds an item to the priority queue.
-func (pq *PriorityQueue) Push(x interface{}) {
-	n := len(*pq)
-	item := x.(*Item)
-	item.Index = n
-	*pq = append(*pq, item)
-}
-
-// Pop removes and returns the item with the smallest distance.
-func (pq *PriorityQueue) Pop() interface{} {
-	old := *pq
-	n := len(old)
-	item := old[n-1]
-	item.Index = -1 // for safety
-	*pq = old[0 : n-1]
-	return item
-}
-
-// Dijkstra computes the shortest paths from the source vertex to all other vertices.
-func (g *Graph) Dijkstra(source string) map[string]float64 {
-	// Initialize distances with infinity.
-	distances := make(map[string]float64)
-	for vertex := range g.Vertices {
-		distances[vertex] = math.Inf(1)
-	}
-	distances[source] = 0
-
-	// Initialize the priority queue and push the source vertex.
-	pq := make(PriorityQueue, 0)
-	heap.Init(&pq)
-	heap.Push(&pq, &Item{Vertex: source, Distance: 0})
+	"log"
+	"strings"
 
-	for pq.Len() > 0 {
-		// Extract the vertex with the smallest distance.
-		current := heap.Pop(&pq).(*Item)
-		currentVertex := current.Vertex
-		currentDistance := current.Distance
-
-		// Update distances to neighboring vertices.
-		for _, edge := range g.Vertices[currentVertex] {
-			newDistance := currentDistance + edge.Weight
-			if newDistance < distances[edge.Target] {
-				distances[edge.Target] = newDistance
-				heap.Push(&pq, &Item{Vertex: edge.Target, Distance: newDistance})
-			}
-		}
-	}
-
-	return distances
-}
+	"go-mastery/dijkstras-algorithm/dijkstra"
+	"go-mastery/graphs/graph"
+)
 
 func main() {
-	g := NewGraph()
-	g.AddEdge("A", "B", 1)
-	g.AddEdge("A", "C", 4)
-	g.AddEdge("B", "C", 2)
-	g.AddEdge("B", "D", 5)
-	g.AddEdge("C", "D", 1)
+	g := graph.NewDirected[string]()
+	g.AddWeightedEdge("A", "B", 1)
+	g.AddWeightedEdge("A", "C", 4)
+	g.AddWeightedEdge("B", "C", 2)
+	g.AddWeightedEdge("B", "D", 5)
+	g.AddWeightedEdge("C", "D", 1)
 
 	source := "A"
-	distances := g.Dijkstra(source)
+	tree, err := dijkstra.ShortestPaths(g, source)
+	if err != nil {
+		log.Fatal(err)
+	}
 
-	fmt.Printf("Shortest distances from source vertex %s:\n", source)
-	for vertex, distance := range distances {
-		fmt.Printf("To %s: %f\n", vertex, distance)
+	fmt.Printf("Shortest paths from source vertex %s:\n", source)
+	for _, vertex := range g.Vertices() {
+		fmt.Printf("To %s: %g via %s\n", vertex, tree.Dist[vertex], strings.Join(tree.PathTo(vertex), " -> "))
 	}
+	// Output:
+	// Shortest paths from source vertex A:
+	// To A: 0 via A
+	// To B: 1 via A -> B
+	// To C: 3 via A -> B -> C
+	// To D: 4 via A -> B -> C -> D
+
+	// Routing between services, weighted by latency in milliseconds.
+	// ShortestPath stops as soon as the destination is settled.
+	routes := graph.NewUndirected[string]()
+	routes.AddWeightedEdge("gateway", "auth", 3)
+	routes.AddWeightedEdge("gateway", "search", 12)
+	routes.AddWeightedEdge("auth", "users", 4)
+	routes.AddWeightedEdge("users", "search", 2)
+	routes.AddWeightedEdge("search", "index", 7)
+
+	path, cost, err := dijkstra.ShortestPath(routes, "gateway", "search")
+	if err != nil {
+		log.Fatal(err)
+	}
+	fmt.Printf("%s (%gms)\n", strings.Join(path, " -> "), cost)
+	// Output: gateway -> auth -> users -> search (9ms)
+
+	routes.AddWeightedEdge("index", "users", -1)
+	_, _, err = dijkstra.ShortestPath(routes, "gateway", "index")
+	fmt.Println(err) // Output: negative edge weight: users -> index (-1)
 }
@@ -0,0 +1,13 @@
+module go-mastery/dijkstras-algorithm
+
+go 1.24.0
+
+require (
+	go-mastery/graphs v0.0.0
+	go-mastery/heaps v0.0.0
+)
+
+replace (
+	go-mastery/graphs => "../../../Data Structures/5.0 Graphs"
+	go-mastery/heaps => "../../../Data Structures/7.0 Heaps"
+)
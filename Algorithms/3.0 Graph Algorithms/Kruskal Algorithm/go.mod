@@ -0,0 +1,13 @@
+module go-mastery/kruskal-algorithm
+
+go 1.24.0
+
+require (
+	go-mastery/disjoint-union v0.0.0
+	go-mastery/graphs v0.0.0
+)
+
+replace (
+	go-mastery/disjoint-union => "../../../Data Structures/8.0 Disjoint Union"
+	go-mastery/graphs => "../../../Data Structures/5.0 Graphs"
+)
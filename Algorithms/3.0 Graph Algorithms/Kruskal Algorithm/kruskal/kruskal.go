@@ -0,0 +1,36 @@
+// Package kruskal finds minimum spanning forests with Kruskal's algorithm.
+package kruskal
+
+import (
+	"cmp"
+	"slices"
+
+	"go-mastery/disjoint-union/disjointset"
+	"go-mastery/graphs/graph"
+)
+
+// MST returns the edges of a minimum spanning forest of g: for each
+// connected component, the lightest set of edges that connects all of its
+// vertices. Edge directions are ignored. Edges of equal weight are
+// considered in the order of graph.Edges, so the result is the same on
+// every run.
+func MST[V comparable](g graph.Graph[V]) []graph.Edge[V] {
+	edges := graph.Edges(g)
+	slices.SortStableFunc(edges, func(a, b graph.Edge[V]) int {
+		return cmp.Compare(a.Weight, b.Weight)
+	})
+
+	// Each set of the forest holds the vertices joined by the edges so far
+	forest := disjointset.New[V]()
+	for _, v := range g.Vertices() {
+		forest.MakeSet(v)
+	}
+	var mst []graph.Edge[V]
+	for _, e := range edges {
+		// Union fails to merge when the edge would close a cycle
+		if merged, _ := forest.Union(e.From, e.To); merged {
+			mst = append(mst, e)
+		}
+	}
+	return mst
+}
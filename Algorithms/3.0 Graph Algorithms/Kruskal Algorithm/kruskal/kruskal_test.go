@@ -0,0 +1,85 @@
+package kruskal
+
+import (
+	"math"
+	"math/rand/v2"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+// primWeight returns the total weight of a minimum spanning forest using
+// Prim's algorithm with a linear scan for the closest vertex
+func primWeight(g *graph.AdjacencyList[int]) float64 {
+	inTree := make(map[int]bool)
+	total := 0.0
+	for _, root := range g.Vertices() {
+		if inTree[root] {
+			continue
+		}
+		best := map[int]float64{root: 0}
+		for len(best) > 0 {
+			next, nextCost := 0, math.Inf(1)
+			for v, c := range best {
+				if c < nextCost || c == nextCost && v < next {
+					next, nextCost = v, c
+				}
+			}
+			delete(best, next)
+			inTree[next] = true
+			total += nextCost
+			for to, w := range g.Adjacent(next) {
+				if c, ok := best[to]; !inTree[to] && (!ok || w < c) {
+					best[to] = w
+				}
+			}
+		}
+	}
+	return total
+}
+
+func TestMST(t *testing.T) {
+	for range 100 {
+		g := graph.NewUndirected[int]()
+		for v := range 12 {
+			g.AddVertex(v)
+		}
+		for range 20 {
+			u, v := rand.IntN(12), rand.IntN(12)
+			if u != v {
+				g.AddWeightedEdge(u, v, float64(rand.IntN(20)))
+			}
+		}
+		mst := MST(g)
+		total := 0.0
+		for _, e := range mst {
+			total += e.Weight
+		}
+		if want := primWeight(g); total != want {
+			t.Fatalf("MST weight = %v; want %v", total, want)
+		}
+		// A spanning forest has one edge fewer than vertices per component
+		components := 0
+		seen := make(map[int]bool)
+		for _, v := range g.Vertices() {
+			if !seen[v] {
+				components++
+				stack := []int{v}
+				seen[v] = true
+				for len(stack) > 0 {
+					u := stack[len(stack)-1]
+					stack = stack[:len(stack)-1]
+					for to := range g.Adjacent(u) {
+						if !seen[to] {
+							seen[to] = true
+							stack = append(stack, to)
+						}
+					}
+				}
+			}
+		}
+		if len(mst) != g.Order()-components {
+			t.Fatalf("MST has %d edges; want %d", len(mst), g.Order()-components)
+		}
+	}
+}
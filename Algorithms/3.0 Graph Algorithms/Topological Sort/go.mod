@@ -0,0 +1,7 @@
+module go-mastery/topological-sort
+
+go 1.24.0
+
+require go-mastery/graphs v0.0.0
+
+replace go-mastery/graphs => "../../../Data Structures/5.0 Graphs"
@@ -0,0 +1,61 @@
+// Package toposort orders the vertices of a directed acyclic graph so that
+// every edge points forward.
+package toposort
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+
+	"go-mastery/graphs/graph"
+)
+
+var (
+	// ErrCycle is returned, wrapped with a vertex on the cycle, when the
+	// graph has no topological order
+	ErrCycle = errors.New("cycle detected")
+	// ErrUndirected is returned for undirected graphs, whose edges have no
+	// direction to respect
+	ErrUndirected = errors.New("graph is undirected")
+)
+
+// Sort returns the vertices of g such that for every edge u -> v, u comes
+// before v. It runs a depth-first search from each unvisited vertex in the
+// order of g.Vertices, so the result is the same on every run.
+func Sort[V comparable](g graph.Graph[V]) ([]V, error) {
+	if !g.Directed() {
+		return nil, ErrUndirected
+	}
+	visited := make(map[V]bool)
+	onPath := make(map[V]bool) // vertices on the current DFS path
+	var finished []V           // vertices in the order their DFS finished
+
+	var visit func(v V) error
+	visit = func(v V) error {
+		if onPath[v] {
+			return fmt.Errorf("%w: %v", ErrCycle, v)
+		}
+		if visited[v] {
+			return nil
+		}
+		visited[v] = true
+		onPath[v] = true
+		for next := range g.Adjacent(v) {
+			if err := visit(next); err != nil {
+				return err
+			}
+		}
+		onPath[v] = false
+		finished = append(finished, v)
+		return nil
+	}
+
+	for _, v := range g.Vertices() {
+		if err := visit(v); err != nil {
+			return nil, err
+		}
+	}
+	// A vertex finishes after everything it points to
+	slices.Reverse(finished)
+	return finished, nil
+}
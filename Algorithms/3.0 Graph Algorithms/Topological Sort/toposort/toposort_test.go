@@ -0,0 +1,55 @@
+package toposort
+
+import (
+	"errors"
+	"math/rand/v2"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+func TestSort(t *testing.T) {
+	for range 100 {
+		// Random DAG: edges only go from lower to higher numbers, but the
+		// vertices are added in a shuffled order
+		g := graph.NewDirected[int]()
+		for _, v := range rand.Perm(20) {
+			g.AddVertex(v)
+		}
+		for range 40 {
+			u, v := rand.IntN(20), rand.IntN(20)
+			if u < v {
+				g.AddEdge(u, v)
+			}
+		}
+		order, err := Sort(g)
+		if err != nil {
+			t.Fatalf("Sort: %v", err)
+		}
+		if len(order) != g.Order() {
+			t.Fatalf("Sort returned %d vertices; want %d", len(order), g.Order())
+		}
+		position := make(map[int]int)
+		for i, v := range order {
+			position[v] = i
+		}
+		for _, e := range g.Edges() {
+			if position[e.From] > position[e.To] {
+				t.Fatalf("edge %d -> %d points backwards in %v", e.From, e.To, order)
+			}
+		}
+	}
+}
+
+func TestSortErrors(t *testing.T) {
+	g := graph.NewDirected[string]()
+	g.AddEdge("A", "B")
+	g.AddEdge("B", "C")
+	g.AddEdge("C", "A")
+	if _, err := Sort(g); !errors.Is(err, ErrCycle) {
+		t.Fatalf("Sort of a cycle: err = %v; want ErrCycle", err)
+	}
+	if _, err := Sort(graph.NewUndirected[string]()); err != ErrUndirected {
+		t.Fatalf("Sort of an undirected graph: err = %v; want ErrUndirected", err)
+	}
+}
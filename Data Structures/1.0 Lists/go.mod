@@ -0,0 +1,3 @@
+module go-mastery/lists
+
+go 1.23.4
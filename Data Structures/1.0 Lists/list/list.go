@@ -0,0 +1,201 @@
+// Package list implements a generic doubly linked list.
+package list
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// Element is a node in the linked list. It doubles as a handle that can be
+// passed back to InsertBefore, InsertAfter and Remove.
+type Element[T any] struct {
+	Value T
+	next  *Element[T]
+	prev  *Element[T]
+	list  *List[T]
+}
+
+// Next returns the element after e, or nil if e is the last element
+func (e *Element[T]) Next() *Element[T] {
+	return e.next
+}
+
+// Prev returns the element before e, or nil if e is the first element
+func (e *Element[T]) Prev() *Element[T] {
+	return e.prev
+}
+
+// List represents a doubly linked list with head and tail pointers.
+// The zero value is an empty list ready to use.
+type List[T any] struct {
+	head *Element[T]
+	tail *Element[T]
+	len  int
+}
+
+// New creates an empty list
+func New[T any]() *List[T] {
+	return &List[T]{}
+}
+
+// Len returns the number of elements in the list
+func (l *List[T]) Len() int {
+	return l.len
+}
+
+// Front returns the first element of the list, or nil if the list is empty
+func (l *List[T]) Front() *Element[T] {
+	return l.head
+}
+
+// Back returns the last element of the list, or nil if the list is empty
+func (l *List[T]) Back() *Element[T] {
+	return l.tail
+}
+
+// Append adds a new element with the given value at the end of the list in O(1)
+func (l *List[T]) Append(value T) *Element[T] {
+	return l.insert(value, l.tail, nil)
+}
+
+// Prepend adds a new element with the given value at the beginning of the list
+func (l *List[T]) Prepend(value T) *Element[T] {
+	return l.insert(value, nil, l.head)
+}
+
+// InsertBefore adds a new element with the given value right before mark.
+// It returns nil if mark does not belong to the list.
+func (l *List[T]) InsertBefore(value T, mark *Element[T]) *Element[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+	return l.insert(value, mark.prev, mark)
+}
+
+// InsertAfter adds a new element with the given value right after mark.
+// It returns nil if mark does not belong to the list.
+func (l *List[T]) InsertAfter(value T, mark *Element[T]) *Element[T] {
+	if mark == nil || mark.list != l {
+		return nil
+	}
+	return l.insert(value, mark, mark.next)
+}
+
+// insert links a new element between prev and next, either of which may be nil
+func (l *List[T]) insert(value T, prev, next *Element[T]) *Element[T] {
+	e := &Element[T]{Value: value, prev: prev, next: next, list: l}
+	if prev == nil {
+		l.head = e
+	} else {
+		prev.next = e
+	}
+	if next == nil {
+		l.tail = e
+	} else {
+		next.prev = e
+	}
+	l.len++
+	return e
+}
+
+// Remove unlinks e from the list and returns its value.
+// Elements that do not belong to the list are left untouched,
+// and a nil element gives the zero value.
+func (l *List[T]) Remove(e *Element[T]) T {
+	if e == nil {
+		var zero T
+		return zero
+	}
+	if e.list != l {
+		return e.Value
+	}
+	l.unlink(e)
+	e.next, e.prev, e.list = nil, nil, nil // avoid memory leaks
+	l.len--
+	return e.Value
+}
+
+// unlink detaches e from its neighbours without clearing its own links
+func (l *List[T]) unlink(e *Element[T]) {
+	if e.prev == nil {
+		l.head = e.next
+	} else {
+		e.prev.next = e.next
+	}
+	if e.next == nil {
+		l.tail = e.prev
+	} else {
+		e.next.prev = e.prev
+	}
+}
+
+// MoveToFront moves e to the front of the list in O(1).
+// Elements that do not belong to the list are left untouched.
+func (l *List[T]) MoveToFront(e *Element[T]) {
+	if e == nil || e.list != l || l.head == e {
+		return
+	}
+	l.unlink(e)
+	e.prev, e.next = nil, l.head
+	l.head.prev = e
+	l.head = e
+}
+
+// MoveToBack moves e to the back of the list in O(1).
+// Elements that do not belong to the list are left untouched.
+func (l *List[T]) MoveToBack(e *Element[T]) {
+	if e == nil || e.list != l || l.tail == e {
+		return
+	}
+	l.unlink(e)
+	e.prev, e.next = l.tail, nil
+	l.tail.next = e
+	l.tail = e
+}
+
+// Reverse reverses the order of the elements in place
+func (l *List[T]) Reverse() {
+	for current := l.head; current != nil; current = current.prev {
+		current.next, current.prev = current.prev, current.next
+	}
+	l.head, l.tail = l.tail, l.head
+}
+
+// All returns an iterator over the values from front to back.
+// The element being visited may be removed during iteration.
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l.head; current != nil; {
+			next := current.next
+			if !yield(current.Value) {
+				return
+			}
+			current = next
+		}
+	}
+}
+
+// Backward returns an iterator over the values from back to front.
+// The element being visited may be removed during iteration.
+func (l *List[T]) Backward() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l.tail; current != nil; {
+			prev := current.prev
+			if !yield(current.Value) {
+				return
+			}
+			current = prev
+		}
+	}
+}
+
+// String formats the list as "a -> b -> c -> nil"
+func (l *List[T]) String() string {
+	var sb strings.Builder
+	for value := range l.All() {
+		fmt.Fprintf(&sb, "%v -> ", value)
+	}
+	sb.WriteString("nil")
+	return sb.String()
+}
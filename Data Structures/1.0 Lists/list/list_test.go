@@ -0,0 +1,174 @@
+package list
+
+import (
+	"slices"
+	"testing"
+)
+
+// checkList verifies the links in both directions and the cached length.
+func checkList[T comparable](t *testing.T, l *List[T], want []T) {
+	t.Helper()
+	if l.Len() != len(want) {
+		t.Fatalf("Len() = %d; want %d", l.Len(), len(want))
+	}
+	if got := slices.Collect(l.All()); !slices.Equal(got, want) {
+		t.Fatalf("All() = %v; want %v", got, want)
+	}
+	reversed := slices.Clone(want)
+	slices.Reverse(reversed)
+	if got := slices.Collect(l.Backward()); !slices.Equal(got, reversed) {
+		t.Fatalf("Backward() = %v; want %v", got, reversed)
+	}
+	if len(want) == 0 {
+		if l.Front() != nil || l.Back() != nil {
+			t.Fatalf("empty list has non-nil Front or Back")
+		}
+		return
+	}
+	if l.Front().Prev() != nil || l.Back().Next() != nil {
+		t.Fatalf("list ends are not terminated")
+	}
+}
+
+func TestAppendPrepend(t *testing.T) {
+	var l List[int]
+	checkList(t, &l, nil)
+
+	l.Append(10)
+	l.Append(20)
+	l.Prepend(5)
+	checkList(t, &l, []int{5, 10, 20})
+
+	if got := l.String(); got != "5 -> 10 -> 20 -> nil" {
+		t.Errorf("String() = %q", got)
+	}
+}
+
+func TestInsertAndRemove(t *testing.T) {
+	l := New[string]()
+	b := l.Append("b")
+	l.InsertBefore("a", b)
+	d := l.InsertAfter("d", b)
+	l.InsertBefore("c", d)
+	checkList(t, l, []string{"a", "b", "c", "d"})
+
+	if got := l.Remove(b); got != "b" {
+		t.Errorf("Remove() = %q; want %q", got, "b")
+	}
+	checkList(t, l, []string{"a", "c", "d"})
+
+	// Removing twice or from another list must not corrupt either list.
+	l.Remove(b)
+	other := New[string]()
+	other.Append("x")
+	l.Remove(other.Front())
+	checkList(t, l, []string{"a", "c", "d"})
+	checkList(t, other, []string{"x"})
+
+	// A nil element is treated like one from another list
+	if got := l.Remove(nil); got != "" {
+		t.Errorf("Remove(nil) = %q; want the zero value", got)
+	}
+	if l.InsertBefore("z", nil) != nil || l.InsertAfter("z", nil) != nil {
+		t.Errorf("inserting next to a nil mark should return nil")
+	}
+	checkList(t, l, []string{"a", "c", "d"})
+
+	if l.InsertAfter("e", b) != nil {
+		t.Errorf("InsertAfter with a removed mark should return nil")
+	}
+
+	l.Remove(l.Front())
+	l.Remove(l.Back())
+	checkList(t, l, []string{"c"})
+	l.Remove(l.Front())
+	checkList(t, l, nil)
+}
+
+func TestMove(t *testing.T) {
+	l := New[int]()
+	one := l.Append(1)
+	two := l.Append(2)
+	three := l.Append(3)
+
+	l.MoveToFront(three)
+	checkList(t, l, []int{3, 1, 2})
+	l.MoveToFront(three)
+	checkList(t, l, []int{3, 1, 2})
+	l.MoveToBack(one)
+	checkList(t, l, []int{3, 2, 1})
+	l.MoveToFront(two)
+	checkList(t, l, []int{2, 3, 1})
+	l.MoveToBack(one)
+	checkList(t, l, []int{2, 3, 1})
+
+	// Elements of other lists and nil elements are ignored
+	other := New[int]()
+	l.MoveToFront(other.Append(9))
+	l.MoveToFront(nil)
+	l.MoveToBack(nil)
+	checkList(t, l, []int{2, 3, 1})
+
+	single := New[int]()
+	e := single.Append(7)
+	single.MoveToFront(e)
+	single.MoveToBack(e)
+	checkList(t, single, []int{7})
+}
+
+func TestReverse(t *testing.T) {
+	l := New[int]()
+	l.Reverse()
+	checkList(t, l, nil)
+
+	for i := range 5 {
+		l.Append(i)
+	}
+	l.Reverse()
+	checkList(t, l, []int{4, 3, 2, 1, 0})
+
+	l.Append(-1)
+	checkList(t, l, []int{4, 3, 2, 1, 0, -1})
+}
+
+func TestRemoveDuringIteration(t *testing.T) {
+	l := New[int]()
+	for i := range 6 {
+		l.Append(i)
+	}
+	e := l.Front()
+	for v := range l.All() {
+		next := e.Next()
+		if v%2 == 0 {
+			l.Remove(e)
+		}
+		e = next
+	}
+	checkList(t, l, []int{1, 3, 5})
+}
+
+func TestEarlyBreak(t *testing.T) {
+	l := New[int]()
+	for i := range 10 {
+		l.Append(i)
+	}
+	var seen []int
+	for v := range l.Backward() {
+		if v < 7 {
+			break
+		}
+		seen = append(seen, v)
+	}
+	if !slices.Equal(seen, []int{9, 8, 7}) {
+		t.Errorf("Backward() with break = %v", seen)
+	}
+}
+
+func BenchmarkAppend(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		l := New[int]()
+		for j := 0; j < 1000; j++ {
+			l.Append(j)
+		}
+	}
+}
@@ -1,73 +1,39 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
 
-// Node represents an element in the linked list
-type Node struct {
-	value int
-	next  *Node
-}
-
-// LinkedList represents the linked list itself
-type LinkedList struct {
-	head *Node
-}
-
-// Append adds a new node with the given value at the end of the list
-func (list *LinkedList) Append(value int) {
-	newNode := &Node{value: value}
-	if list.head == nil {
-		list.head = newNode
-		return
-	}
-	current := list.head
-	for current.next != nil {
-		current = current.next
-	}
-	current.next = newNode
-}
-
-// Prepend adds a new node with the given value at the beginning of the list
-func (list *LinkedList) Prepend(value int) {
-	newNode := &Node{value: value, next: list.head}
-	list.head = newNode
-}
+	"go-mastery/lists/list"
+)
 
-// Delete removes the first occurrence of the node with the given value
-func (list *LinkedList) Delete(value int) {
-	if list.head == nil {
-		return
-	}
-	if list.head.value == value {
-		list.head = list.head.next
-		return
-	}
-	current := list.head
-	for current.next != nil && current.next.value != value {
-		current = current.next
-	}
-	if current.next != nil {
-		current.next = current.next.next
+func main() {
+	l := list.New[int]()
+	l.Append(10)
+	twenty := l.Append(20)
+	l.Prepend(5)
+	fmt.Println(l) // Output: 5 -> 10 -> 20 -> nil
+
+	// Elements returned by inserts are handles for O(1) edits in place
+	l.InsertBefore(15, twenty)
+	l.InsertAfter(25, twenty)
+	fmt.Println(l) // Output: 5 -> 10 -> 15 -> 20 -> 25 -> nil
+
+	l.Remove(twenty)
+	fmt.Println(l, "len:", l.Len()) // Output: 5 -> 10 -> 15 -> 25 -> nil len: 4
+
+	l.Reverse()
+	fmt.Println(l) // Output: 25 -> 15 -> 10 -> 5 -> nil
+
+	// Range over the list in either direction
+	for value := range l.Backward() {
+		fmt.Printf("%d ", value)
 	}
-}
+	fmt.Println() // Output: 5 10 15 25
 
-// Display prints out the values in the list
-func (list *LinkedList) Display() {
-	current := list.head
-	for current != nil {
-		fmt.Printf("%d -> ", current.value)
-		current = current.next
+	// The same list works for any value type
+	words := list.New[string]()
+	for _, w := range []string{"go", "is", "fun"} {
+		words.Append(w)
 	}
-	fmt.Println("nil")
-}
-
-func main() {
-	list := &LinkedList{}
-	list.Append(10)
-	list.Append(20)
-	list.Prepend(5)
-	list.Display() // Output: 5 -> 10 -> 20 -> nil
-
-	list.Delete(10)
-	list.Display() // Output: 5 -> 20 -> nil
+	fmt.Println(words) // Output: go -> is -> fun -> nil
 }
@@ -0,0 +1,3 @@
+module go-mastery/tries
+
+go 1.23.4
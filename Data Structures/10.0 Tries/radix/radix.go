@@ -0,0 +1,357 @@
+// Package radix implements a compressed radix tree (also called a Patricia
+// trie) for prefix lookups and weighted autocomplete.
+package radix
+
+import (
+	"container/heap"
+	"iter"
+	"math"
+	"slices"
+	"strings"
+)
+
+// node is a node of the radix tree. The path from the root to a node spells
+// the concatenation of the prefixes along it. A node either holds an entry
+// or has at least two children, except for the root.
+type node[V any] struct {
+	prefix   string     // edge label from the parent
+	children []*node[V] // sorted by the first byte of their prefix
+	leaf     bool       // whether the node holds an entry
+	key      string     // full key of the entry
+	value    V
+	weight   float64
+	// maxWeight is the largest weight of any entry in the subtree, which
+	// lets Autocomplete skip subtrees that cannot beat its current best
+	maxWeight float64
+}
+
+// update recomputes maxWeight from the node's entry and its children
+func (n *node[V]) update() {
+	n.maxWeight = math.Inf(-1)
+	if n.leaf {
+		n.maxWeight = n.weight
+	}
+	for _, c := range n.children {
+		n.maxWeight = max(n.maxWeight, c.maxWeight)
+	}
+}
+
+// child returns the index of the child whose prefix starts with b, or where
+// such a child would be inserted
+func (n *node[V]) child(b byte) (int, bool) {
+	return slices.BinarySearchFunc(n.children, b, func(c *node[V], b byte) int {
+		return int(c.prefix[0]) - int(b)
+	})
+}
+
+// Tree is a radix tree that maps keys to values. Keys are compared byte by
+// byte, so the tree works for both string and []byte keys. Chains of nodes
+// with a single child are merged into one edge, so the tree has at most
+// 2n nodes for n keys and a lookup takes O(len(key)) time regardless of how
+// many keys are stored. The zero value is not ready to use; call New.
+type Tree[K ~string | ~[]byte, V any] struct {
+	root *node[V]
+	len  int
+}
+
+// Match is an entry returned by Autocomplete
+type Match[K ~string | ~[]byte, V any] struct {
+	Key    K
+	Value  V
+	Weight float64
+}
+
+// New creates an empty radix tree
+func New[K ~string | ~[]byte, V any]() *Tree[K, V] {
+	root := &node[V]{}
+	root.update()
+	return &Tree[K, V]{root: root}
+}
+
+// Len returns the number of keys in the tree
+func (t *Tree[K, V]) Len() int {
+	return t.len
+}
+
+// Insert sets the value for a key with weight 0 and reports whether the key
+// is new. An existing key keeps its weight.
+func (t *Tree[K, V]) Insert(key K, value V) bool {
+	return t.insert(string(key), value, nil)
+}
+
+// InsertWeighted sets the value and the autocomplete weight for a key and
+// reports whether the key is new
+func (t *Tree[K, V]) InsertWeighted(key K, value V, weight float64) bool {
+	return t.insert(string(key), value, &weight)
+}
+
+func (t *Tree[K, V]) insert(key string, value V, weight *float64) bool {
+	added := insert(t.root, key, key, value, weight)
+	if added {
+		t.len++
+	}
+	return added
+}
+
+// insert stores the entry below n, where rest is the part of key that
+// follows n's path
+func insert[V any](n *node[V], key, rest string, value V, weight *float64) bool {
+	defer n.update()
+	if rest == "" {
+		added := !n.leaf
+		if added {
+			n.weight = 0
+		}
+		if weight != nil {
+			n.weight = *weight
+		}
+		n.leaf, n.key, n.value = true, key, value
+		return added
+	}
+
+	i, found := n.child(rest[0])
+	if !found {
+		leaf := &node[V]{prefix: rest, leaf: true, key: key, value: value}
+		if weight != nil {
+			leaf.weight = *weight
+		}
+		leaf.update()
+		n.children = slices.Insert(n.children, i, leaf)
+		return true
+	}
+
+	c := n.children[i]
+	common := commonPrefixLen(c.prefix, rest)
+	if common < len(c.prefix) {
+		// The key leaves the edge part way: split it at that point
+		mid := &node[V]{prefix: c.prefix[:common], children: []*node[V]{c}}
+		c.prefix = c.prefix[common:]
+		n.children[i] = mid
+		c = mid
+	}
+	return insert(c, key, rest[common:], value, weight)
+}
+
+func commonPrefixLen(a, b string) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+// find returns the node whose path is exactly key, if any
+func (t *Tree[K, V]) find(key string) *node[V] {
+	n := t.root
+	for key != "" {
+		i, found := n.child(key[0])
+		if !found || !strings.HasPrefix(key, n.children[i].prefix) {
+			return nil
+		}
+		n = n.children[i]
+		key = key[len(n.prefix):]
+	}
+	return n
+}
+
+// Get returns the value stored for a key
+func (t *Tree[K, V]) Get(key K) (V, bool) {
+	if n := t.find(string(key)); n != nil && n.leaf {
+		return n.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Weight returns the autocomplete weight of a key
+func (t *Tree[K, V]) Weight(key K) (float64, bool) {
+	if n := t.find(string(key)); n != nil && n.leaf {
+		return n.weight, true
+	}
+	return 0, false
+}
+
+// Delete removes a key and reports whether it was present
+func (t *Tree[K, V]) Delete(key K) bool {
+	removed := remove(t.root, string(key))
+	if removed {
+		t.len--
+	}
+	return removed
+}
+
+// remove deletes the entry whose key continues n's path with rest, then
+// removes or merges children that no longer need a node of their own
+func remove[V any](n *node[V], rest string) bool {
+	if rest == "" {
+		if !n.leaf {
+			return false
+		}
+		var zero V
+		n.leaf, n.key, n.value = false, "", zero
+		n.update()
+		return true
+	}
+
+	i, found := n.child(rest[0])
+	if !found || !strings.HasPrefix(rest, n.children[i].prefix) {
+		return false
+	}
+	c := n.children[i]
+	if !remove(c, rest[len(c.prefix):]) {
+		return false
+	}
+	if !c.leaf {
+		switch len(c.children) {
+		case 0:
+			n.children = slices.Delete(n.children, i, i+1)
+		case 1:
+			// Merge the only grandchild into this edge
+			grandchild := c.children[0]
+			grandchild.prefix = c.prefix + grandchild.prefix
+			n.children[i] = grandchild
+		}
+	}
+	n.update()
+	return true
+}
+
+// LongestPrefix returns the longest stored key that is a prefix of key,
+// for example the most specific route for a path
+func (t *Tree[K, V]) LongestPrefix(key K) (K, V, bool) {
+	rest := string(key)
+	var best *node[V]
+	for n := t.root; n != nil; {
+		if n.leaf {
+			best = n
+		}
+		if rest == "" {
+			break
+		}
+		i, found := n.child(rest[0])
+		if !found || !strings.HasPrefix(rest, n.children[i].prefix) {
+			break
+		}
+		n = n.children[i]
+		rest = rest[len(n.prefix):]
+	}
+	if best == nil {
+		var zero V
+		return K(""), zero, false
+	}
+	return K(best.key), best.value, true
+}
+
+// prefixNode returns the highest node whose path starts with prefix,
+// together with that path
+func (t *Tree[K, V]) prefixNode(prefix string) (*node[V], string) {
+	n, path := t.root, ""
+	for rest := prefix; rest != ""; {
+		i, found := n.child(rest[0])
+		if !found {
+			return nil, ""
+		}
+		c := n.children[i]
+		switch {
+		case strings.HasPrefix(rest, c.prefix):
+			rest = rest[len(c.prefix):]
+		case strings.HasPrefix(c.prefix, rest):
+			// The prefix ends inside this edge
+			rest = ""
+		default:
+			return nil, ""
+		}
+		n, path = c, path+c.prefix
+	}
+	return n, path
+}
+
+// WalkPrefix returns an iterator over the keys that start with prefix and
+// their values, in ascending byte order. The tree must not be modified
+// during iteration.
+func (t *Tree[K, V]) WalkPrefix(prefix K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if n, _ := t.prefixNode(string(prefix)); n != nil {
+			walk(n, yield)
+		}
+	}
+}
+
+// All returns an iterator over all keys and values in ascending byte order.
+// The tree must not be modified during iteration.
+func (t *Tree[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		walk(t.root, yield)
+	}
+}
+
+func walk[K ~string | ~[]byte, V any](n *node[V], yield func(K, V) bool) bool {
+	if n.leaf && !yield(K(n.key), n.value) {
+		return false
+	}
+	for _, c := range n.children {
+		if !walk(c, yield) {
+			return false
+		}
+	}
+	return true
+}
+
+// Autocomplete returns up to k keys that start with prefix, highest weight
+// first and in ascending byte order among equal weights. It explores the
+// subtree best first, ordered by each subtree's largest weight, so it stops
+// as soon as k entries are found instead of visiting every completion.
+func (t *Tree[K, V]) Autocomplete(prefix K, k int) []Match[K, V] {
+	start, path := t.prefixNode(string(prefix))
+	if start == nil || k <= 0 {
+		return nil
+	}
+	var matches []Match[K, V]
+	frontier := &candidates[V]{{n: start, weight: start.maxWeight, key: path}}
+	for frontier.Len() > 0 && len(matches) < k {
+		c := heap.Pop(frontier).(candidate[V])
+		if c.entry {
+			matches = append(matches, Match[K, V]{Key: K(c.n.key), Value: c.n.value, Weight: c.n.weight})
+			continue
+		}
+		if c.n.leaf {
+			heap.Push(frontier, candidate[V]{n: c.n, weight: c.n.weight, key: c.n.key, entry: true})
+		}
+		for _, child := range c.n.children {
+			heap.Push(frontier, candidate[V]{n: child, weight: child.maxWeight, key: c.key + child.prefix})
+		}
+	}
+	return matches
+}
+
+// candidate is an entry, or a subtree whose best entry has the given weight
+type candidate[V any] struct {
+	n      *node[V]
+	weight float64
+	key    string // the entry's key, or the subtree's path, a prefix of all its keys
+	entry  bool
+}
+
+// candidates is a max-heap ordered by weight, then by key. Among equal
+// weights a subtree pops before any entry that sorts after its path, so
+// ties come out in ascending key order.
+type candidates[V any] []candidate[V]
+
+func (h candidates[V]) Len() int { return len(h) }
+func (h candidates[V]) Less(i, j int) bool {
+	if h[i].weight != h[j].weight {
+		return h[i].weight > h[j].weight
+	}
+	if h[i].key != h[j].key {
+		return h[i].key < h[j].key
+	}
+	return !h[i].entry && h[j].entry
+}
+func (h candidates[V]) Swap(i, j int) { h[i], h[j] = h[j], h[i] }
+func (h *candidates[V]) Push(x any)   { *h = append(*h, x.(candidate[V])) }
+func (h *candidates[V]) Pop() any {
+	old := *h
+	c := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return c
+}
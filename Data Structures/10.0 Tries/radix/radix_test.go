@@ -0,0 +1,195 @@
+package radix
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// checkInvariants verifies that the tree is compressed, that children are
+// sorted by distinct first bytes, that stored keys match their paths and
+// that every maxWeight is correct.
+func checkInvariants[K ~string | ~[]byte, V any](t *testing.T, tr *Tree[K, V]) {
+	t.Helper()
+	count := 0
+	var walk func(n *node[V], path string) float64
+	walk = func(n *node[V], path string) float64 {
+		if n != tr.root {
+			if n.prefix == "" {
+				t.Fatalf("node below %q has an empty edge", path)
+			}
+			if !n.leaf && len(n.children) < 2 {
+				t.Fatalf("node %q has no entry and %d children", path, len(n.children))
+			}
+		}
+		best := math.Inf(-1)
+		if n.leaf {
+			count++
+			if n.key != path {
+				t.Fatalf("node %q stores key %q", path, n.key)
+			}
+			best = n.weight
+		}
+		for i, c := range n.children {
+			if i > 0 && n.children[i-1].prefix[0] >= c.prefix[0] {
+				t.Fatalf("children of %q are not sorted by distinct first bytes", path)
+			}
+			best = max(best, walk(c, path+c.prefix))
+		}
+		if n.maxWeight != best {
+			t.Fatalf("node %q caches maxWeight %v; want %v", path, n.maxWeight, best)
+		}
+		return best
+	}
+	walk(tr.root, "")
+	if count != tr.Len() {
+		t.Fatalf("tree holds %d entries; Len() = %d", count, tr.Len())
+	}
+}
+
+// randomKey returns a short key over a small alphabet, so keys share
+// prefixes and often are prefixes of each other
+func randomKey(rng *rand.Rand) string {
+	b := make([]byte, rng.Intn(6))
+	for i := range b {
+		b[i] = "abc"[rng.Intn(3)]
+	}
+	return string(b)
+}
+
+type entry struct {
+	value  int
+	weight float64
+}
+
+// TestAgainstMap applies random inserts and deletes to a Tree and a map and
+// compares every query with a brute-force answer.
+func TestAgainstMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	tr := New[string, int]()
+	want := map[string]entry{}
+
+	for i := range 5000 {
+		key := randomKey(rng)
+		switch rng.Intn(3) {
+		case 0:
+			_, wantOK := want[key]
+			if got := tr.Delete(key); got != wantOK {
+				t.Fatalf("Delete(%q) = %t; want %t", key, got, wantOK)
+			}
+			delete(want, key)
+		default:
+			_, exists := want[key]
+			weight := float64(rng.Intn(5))
+			if got := tr.InsertWeighted(key, i, weight); got == exists {
+				t.Fatalf("InsertWeighted(%q) = %t; key existed: %t", key, got, exists)
+			}
+			want[key] = entry{i, weight}
+		}
+		if tr.Len() != len(want) {
+			t.Fatalf("Len() = %d; want %d", tr.Len(), len(want))
+		}
+		if i%100 == 0 {
+			checkInvariants(t, tr)
+		}
+
+		query := randomKey(rng) + randomKey(rng)
+		wantEntry, wantOK := want[query]
+		if got, ok := tr.Get(query); ok != wantOK || ok && got != wantEntry.value {
+			t.Fatalf("Get(%q) = %d, %t; want %d, %t", query, got, ok, wantEntry.value, wantOK)
+		}
+		checkLongestPrefix(t, tr, want, query)
+		checkWalkPrefix(t, tr, want, query[:len(query)/2])
+		checkAutocomplete(t, tr, want, query[:len(query)/2], 1+rng.Intn(5))
+	}
+}
+
+func checkLongestPrefix(t *testing.T, tr *Tree[string, int], want map[string]entry, query string) {
+	t.Helper()
+	wantKey, wantOK := "", false
+	for key := range want {
+		if strings.HasPrefix(query, key) && (!wantOK || len(key) > len(wantKey)) {
+			wantKey, wantOK = key, true
+		}
+	}
+	key, value, ok := tr.LongestPrefix(query)
+	if ok != wantOK || key != wantKey || ok && value != want[key].value {
+		t.Fatalf("LongestPrefix(%q) = %q, %t; want %q, %t", query, key, ok, wantKey, wantOK)
+	}
+}
+
+func checkWalkPrefix(t *testing.T, tr *Tree[string, int], want map[string]entry, prefix string) {
+	t.Helper()
+	var wantKeys []string
+	for key := range want {
+		if strings.HasPrefix(key, prefix) {
+			wantKeys = append(wantKeys, key)
+		}
+	}
+	slices.Sort(wantKeys)
+	var got []string
+	for key, value := range tr.WalkPrefix(prefix) {
+		if value != want[key].value {
+			t.Fatalf("WalkPrefix(%q) yielded %q=%d; want %d", prefix, key, value, want[key].value)
+		}
+		got = append(got, key)
+	}
+	if !slices.Equal(got, wantKeys) {
+		t.Fatalf("WalkPrefix(%q) = %q; want %q", prefix, got, wantKeys)
+	}
+}
+
+func checkAutocomplete(t *testing.T, tr *Tree[string, int], want map[string]entry, prefix string, k int) {
+	t.Helper()
+	var wantMatches []Match[string, int]
+	for key, e := range want {
+		if strings.HasPrefix(key, prefix) {
+			wantMatches = append(wantMatches, Match[string, int]{key, e.value, e.weight})
+		}
+	}
+	slices.SortFunc(wantMatches, func(a, b Match[string, int]) int {
+		return cmp.Or(cmp.Compare(b.Weight, a.Weight), strings.Compare(a.Key, b.Key))
+	})
+	wantMatches = wantMatches[:min(k, len(wantMatches))]
+	if got := tr.Autocomplete(prefix, k); !slices.Equal(got, wantMatches) {
+		t.Fatalf("Autocomplete(%q, %d) = %v; want %v", prefix, k, got, wantMatches)
+	}
+}
+
+func TestByteKeys(t *testing.T) {
+	tr := New[[]byte, string]()
+	tr.Insert([]byte{0x00, 0xff}, "a")
+	tr.Insert([]byte{0x00}, "b")
+	tr.Insert([]byte{}, "empty")
+	key, value, ok := tr.LongestPrefix([]byte{0x00, 0xfe})
+	if !ok || string(key) != "\x00" || value != "b" {
+		t.Fatalf("LongestPrefix = %v, %q, %t", key, value, ok)
+	}
+	var got [][]byte
+	for key := range tr.All() {
+		got = append(got, key)
+	}
+	want := [][]byte{{}, {0x00}, {0x00, 0xff}}
+	if !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("All() = %v; want %v", got, want)
+	}
+	checkInvariants(t, tr)
+}
+
+func TestInsertKeepsWeight(t *testing.T) {
+	tr := New[string, int]()
+	tr.InsertWeighted("go", 1, 10)
+	tr.Insert("go", 2)
+	if w, _ := tr.Weight("go"); w != 10 {
+		t.Fatalf("Insert reset the weight to %v", w)
+	}
+	if got := tr.Autocomplete("g", 1); len(got) != 1 || got[0].Value != 2 {
+		t.Fatalf("Autocomplete = %v", got)
+	}
+	if got := tr.Autocomplete("x", 3); got != nil {
+		t.Fatalf("Autocomplete(x) = %v; want nil", got)
+	}
+}
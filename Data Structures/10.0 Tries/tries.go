@@ -0,0 +1,46 @@
+package main
+
+import (
+	"fmt"
+
+	"go-mastery/tries/radix"
+)
+
+func main() {
+	// Route table: the most specific prefix wins
+	routes := radix.New[string, string]()
+	routes.Insert("/", "home")
+	routes.Insert("/api/", "api")
+	routes.Insert("/api/users/", "users")
+	routes.Insert("/static/", "files")
+
+	for _, path := range []string{"/api/users/42", "/api/orders", "/about"} {
+		prefix, handler, _ := routes.LongestPrefix(path)
+		fmt.Printf("%s -> %s (%s)\n", path, handler, prefix)
+	}
+	// Output:
+	// /api/users/42 -> users (/api/users/)
+	// /api/orders -> api (/api/)
+	// /about -> home (/)
+
+	// Search as you type: completions ranked by how often they were used
+	commands := radix.New[string, struct{}]()
+	for cmd, uses := range map[string]float64{
+		"git status": 120, "git stash": 15, "git switch": 40,
+		"git commit": 90, "go test": 200, "go build": 80,
+	} {
+		commands.InsertWeighted(cmd, struct{}{}, uses)
+	}
+	for _, m := range commands.Autocomplete("git s", 2) {
+		fmt.Printf("%s (%.0f) ", m.Key, m.Weight)
+	}
+	fmt.Println() // Output: git status (120) git switch (40)
+
+	for cmd := range commands.WalkPrefix("go ") {
+		fmt.Printf("%s, ", cmd)
+	}
+	fmt.Println() // Output: go build, go test,
+
+	commands.Delete("go build")
+	fmt.Println("Commands:", commands.Len()) // Output: Commands: 5
+}
@@ -0,0 +1,70 @@
+// Package cache implements bounded in-memory caches with LRU, LFU and TTL
+// eviction, built on the doubly linked list from go-mastery/lists and the
+// hash table from go-mastery/hash-tables.
+package cache
+
+import (
+	"go-mastery/hash-tables/hashtable"
+)
+
+// Cache is the API shared by the caches in this package. Implementations
+// other than Sharded are not safe for concurrent use.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored for a key and records a hit or a miss
+	Get(key K) (V, bool)
+	// Put stores a value, evicting an entry first if the cache is full
+	Put(key K, value V)
+	// Delete removes a key without calling the eviction callback
+	Delete(key K) bool
+	Len() int
+	Stats() Stats
+}
+
+// Options configure a cache
+type Options[K comparable, V any] struct {
+	// Capacity is the maximum number of entries. It must be positive.
+	Capacity int
+	// OnEvict, if set, is called with every entry that the cache drops on
+	// its own, to make room or because it expired. It is not called for
+	// Delete or for values replaced by Put.
+	OnEvict func(key K, value V)
+}
+
+// Stats counts cache activity
+type Stats struct {
+	Hits      uint64
+	Misses    uint64
+	Evictions uint64 // entries dropped to make room
+	Expired   uint64 // entries dropped because their TTL passed
+}
+
+// HitRate returns the fraction of lookups that were hits, or 0 before the first lookup
+func (s Stats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// add returns the sum of two Stats
+func (s Stats) add(other Stats) Stats {
+	return Stats{
+		Hits:      s.Hits + other.Hits,
+		Misses:    s.Misses + other.Misses,
+		Evictions: s.Evictions + other.Evictions,
+		Expired:   s.Expired + other.Expired,
+	}
+}
+
+// validCapacity panics unless the capacity is positive
+func validCapacity(capacity int) {
+	if capacity <= 0 {
+		panic("cache: capacity must be positive")
+	}
+}
+
+// newIndex creates a hash table with enough buckets that it never grows
+// while holding capacity entries
+func newIndex[K comparable, V any](capacity int) *hashtable.HashTable[K, V] {
+	return hashtable.New[K, V](int(float64(capacity)/hashtable.DefaultMaxLoadFactor) + 1)
+}
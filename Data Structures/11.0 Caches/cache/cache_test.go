@@ -0,0 +1,279 @@
+package cache
+
+import (
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+// model is a brute-force cache that scans all entries to pick a victim.
+// The victim is the entry with the smallest (uses, lastUse) for LFU and the
+// smallest lastUse for LRU.
+type model struct {
+	capacity int
+	lfu      bool
+	clock    int
+	entries  map[int]*modelEntry
+}
+
+type modelEntry struct {
+	value, uses, lastUse int
+}
+
+func (m *model) get(key int) (int, bool) {
+	e, ok := m.entries[key]
+	if ok {
+		m.clock++
+		e.uses++
+		e.lastUse = m.clock
+		return e.value, true
+	}
+	return 0, false
+}
+
+// put returns the evicted key, or -1
+func (m *model) put(key, value int) int {
+	m.clock++
+	if e, ok := m.entries[key]; ok {
+		e.value, e.lastUse = value, m.clock
+		e.uses++
+		return -1
+	}
+	evicted := -1
+	if len(m.entries) == m.capacity {
+		var victim *modelEntry
+		for k, e := range m.entries {
+			if victim == nil || m.older(e, victim) {
+				victim, evicted = e, k
+			}
+		}
+		delete(m.entries, evicted)
+	}
+	m.entries[key] = &modelEntry{value: value, uses: 1, lastUse: m.clock}
+	return evicted
+}
+
+func (m *model) older(a, b *modelEntry) bool {
+	if m.lfu && a.uses != b.uses {
+		return a.uses < b.uses
+	}
+	return a.lastUse < b.lastUse
+}
+
+func TestAgainstModel(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		lfu  bool
+		new  func(Options[int, int]) Cache[int, int]
+	}{
+		{"LRU", false, func(o Options[int, int]) Cache[int, int] { return NewLRU(o) }},
+		{"LFU", true, func(o Options[int, int]) Cache[int, int] { return NewLFU(o) }},
+		{"TTL", false, func(o Options[int, int]) Cache[int, int] { return NewTTL(o, time.Hour) }},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			const capacity = 16
+			want := &model{capacity: capacity, lfu: tc.lfu, entries: map[int]*modelEntry{}}
+			evicted := -1
+			c := tc.new(Options[int, int]{
+				Capacity: capacity,
+				OnEvict: func(key, value int) {
+					if value != want.entries[key].value {
+						t.Fatalf("OnEvict(%d, %d) got a stale value", key, value)
+					}
+					evicted = key
+				},
+			})
+
+			var hits, misses, evictions uint64
+			for i := range 20000 {
+				key := rng.Intn(40)
+				switch rng.Intn(5) {
+				case 0, 1:
+					got, ok := c.Get(key)
+					wantValue, wantOK := want.get(key)
+					if got != wantValue || ok != wantOK {
+						t.Fatalf("Get(%d) = %d, %t; want %d, %t", key, got, ok, wantValue, wantOK)
+					}
+					if ok {
+						hits++
+					} else {
+						misses++
+					}
+				case 2:
+					_, wantOK := want.entries[key]
+					if got := c.Delete(key); got != wantOK {
+						t.Fatalf("Delete(%d) = %t; want %t", key, got, wantOK)
+					}
+					delete(want.entries, key)
+				default:
+					evicted = -1
+					c.Put(key, i) // OnEvict still finds the victim in the model
+					wantEvicted := want.put(key, i)
+					if evicted != wantEvicted {
+						t.Fatalf("Put(%d) evicted %d; want %d", key, evicted, wantEvicted)
+					}
+					if evicted != -1 {
+						evictions++
+					}
+				}
+				if c.Len() != len(want.entries) {
+					t.Fatalf("Len() = %d; want %d", c.Len(), len(want.entries))
+				}
+			}
+			stats := c.Stats()
+			if stats.Hits != hits || stats.Misses != misses || stats.Evictions != evictions {
+				t.Fatalf("Stats() = %+v; want %d hits, %d misses, %d evictions", stats, hits, misses, evictions)
+			}
+		})
+	}
+}
+
+func TestTTLExpiry(t *testing.T) {
+	now := time.Unix(0, 0)
+	var expired []string
+	c := NewTTL(Options[string, int]{
+		Capacity: 3,
+		OnEvict:  func(key string, _ int) { expired = append(expired, key) },
+	}, time.Minute)
+	c.now = func() time.Time { return now }
+
+	c.Put("a", 1)
+	c.PutWithTTL("b", 2, 10*time.Second)
+	c.PutWithTTL("c", 3, time.Hour)
+
+	now = now.Add(30 * time.Second)
+	if _, ok := c.Get("b"); ok {
+		t.Fatalf("b did not expire")
+	}
+	if v, ok := c.Get("a"); !ok || v != 1 {
+		t.Fatalf("Get(a) = %d, %t", v, ok)
+	}
+
+	// A full cache drops expired entries before evicting live ones
+	c.Put("d", 4)
+	now = now.Add(time.Minute)
+	c.Put("e", 5) // a and d have expired
+	if !slices.Equal(expired, []string{"b", "d", "a"}) {
+		t.Fatalf("expired %v", expired)
+	}
+	if c.Len() != 2 {
+		t.Fatalf("Len() = %d; want 2", c.Len())
+	}
+	c.Put("f", 6)
+	c.Put("g", 7) // nothing expired: evict the least recently used, c
+	if _, ok := c.Get("c"); ok {
+		t.Fatalf("c was not evicted")
+	}
+	stats := c.Stats()
+	if stats.Expired != 3 || stats.Evictions != 1 {
+		t.Fatalf("Stats() = %+v", stats)
+	}
+
+	now = now.Add(2 * time.Hour)
+	if n := c.DeleteExpired(); n != 3 || c.Len() != 0 {
+		t.Fatalf("DeleteExpired() = %d, Len() = %d", n, c.Len())
+	}
+}
+
+// TestTTLShortEntryAfterScan stores a short-lived entry right after a scan
+// for expired entries, which must still find it later.
+func TestTTLShortEntryAfterScan(t *testing.T) {
+	now := time.Unix(0, 0)
+	c := NewTTL(Options[int, int]{Capacity: 2}, time.Hour)
+	c.now = func() time.Time { return now }
+	c.PutWithTTL(1, 1, time.Second)
+	c.Put(2, 2)
+	now = now.Add(time.Minute)
+	c.PutWithTTL(3, 3, time.Second) // scans, drops 1, then adds 3
+	now = now.Add(time.Minute)
+	if n := c.DeleteExpired(); n != 1 || c.Len() != 1 {
+		t.Fatalf("DeleteExpired() = %d, Len() = %d; want 1, 1", n, c.Len())
+	}
+}
+
+func TestLFUPrefersFrequentKeys(t *testing.T) {
+	c := NewLFU(Options[string, int]{Capacity: 2})
+	c.Put("hot", 1)
+	for range 5 {
+		c.Get("hot")
+	}
+	// A scan of one-off keys must not push out the hot key
+	for _, key := range []string{"a", "b", "c", "d"} {
+		c.Put(key, 0)
+	}
+	if _, ok := c.Get("hot"); !ok {
+		t.Fatalf("hot key was evicted")
+	}
+	if got := c.Stats().HitRate(); got != 6.0/6 {
+		t.Fatalf("HitRate() = %v", got)
+	}
+}
+
+// TestSharded runs goroutines against a sharded LRU. Run with -race.
+func TestSharded(t *testing.T) {
+	c := NewSharded(8, func() Cache[int, int] {
+		return NewLRU(Options[int, int]{Capacity: 64})
+	})
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(g)))
+			for range 5000 {
+				key := rng.Intn(1000)
+				if v, ok := c.Get(key); ok && v != key*2 {
+					t.Errorf("Get(%d) = %d", key, v)
+					return
+				}
+				c.Put(key, key*2)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() > 8*64 {
+		t.Fatalf("Len() = %d exceeds the total capacity", c.Len())
+	}
+	stats := c.Stats()
+	if stats.Hits+stats.Misses != 8*5000 {
+		t.Fatalf("Stats() = %+v; want %d lookups", stats, 8*5000)
+	}
+}
+
+func TestInvalidCapacity(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("NewLRU with capacity 0 did not panic")
+		}
+	}()
+	NewLRU(Options[int, int]{})
+}
+
+func BenchmarkCaches(b *testing.B) {
+	const capacity, keys = 1 << 10, 1 << 12
+	for _, tc := range []struct {
+		name string
+		new  func() Cache[int, int]
+	}{
+		{"LRU", func() Cache[int, int] { return NewLRU(Options[int, int]{Capacity: capacity}) }},
+		{"LFU", func() Cache[int, int] { return NewLFU(Options[int, int]{Capacity: capacity}) }},
+		{"TTL", func() Cache[int, int] { return NewTTL(Options[int, int]{Capacity: capacity}, time.Hour) }},
+	} {
+		b.Run(tc.name, func(b *testing.B) {
+			c := tc.new()
+			// Zipf-like keys: a few keys are requested far more often than the rest
+			zipf := rand.NewZipf(rand.New(rand.NewSource(1)), 1.1, 1, keys-1)
+			for i := 0; i < b.N; i++ {
+				key := int(zipf.Uint64())
+				if _, ok := c.Get(key); !ok {
+					c.Put(key, key)
+				}
+			}
+			b.ReportMetric(c.Stats().HitRate(), "hit-rate")
+		})
+	}
+}
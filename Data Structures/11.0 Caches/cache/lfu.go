@@ -0,0 +1,131 @@
+package cache
+
+import (
+	"go-mastery/hash-tables/hashtable"
+	"go-mastery/lists/list"
+)
+
+// lfuEntry is a cached key-value pair together with its frequency bucket
+type lfuEntry[K comparable, V any] struct {
+	key    K
+	value  V
+	bucket *list.Element[*frequency[K, V]]
+}
+
+// frequency holds the entries that were used count times, least recently
+// used first
+type frequency[K comparable, V any] struct {
+	count   int
+	entries list.List[*lfuEntry[K, V]]
+}
+
+// LFU is a cache that evicts the least frequently used entry, and among
+// those the least recently used one. It uses the O(1) scheme of Shah,
+// Mitra and Matani: a linked list of frequency buckets in ascending order,
+// each holding a linked list of its entries. A hit moves the entry to the
+// bucket for the next count, which is either the next bucket or a new one
+// inserted after the current bucket, and eviction takes the first entry of
+// the first bucket.
+type LFU[K comparable, V any] struct {
+	opts    Options[K, V]
+	buckets list.List[*frequency[K, V]]
+	index   *hashtable.HashTable[K, *list.Element[*lfuEntry[K, V]]]
+	stats   Stats
+}
+
+var _ Cache[string, int] = (*LFU[string, int])(nil)
+
+// NewLFU creates an empty LFU cache. It panics if opts.Capacity is not positive.
+func NewLFU[K comparable, V any](opts Options[K, V]) *LFU[K, V] {
+	validCapacity(opts.Capacity)
+	return &LFU[K, V]{
+		opts:  opts,
+		index: newIndex[K, *list.Element[*lfuEntry[K, V]]](opts.Capacity),
+	}
+}
+
+// Get returns the value stored for a key and increments its use count
+func (c *LFU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.index.Get(key)
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.touch(e)
+	return e.Value.value, true
+}
+
+// touch moves an entry to the bucket for its next use count
+func (c *LFU[K, V]) touch(e *list.Element[*lfuEntry[K, V]]) {
+	entry := e.Value
+	current := entry.bucket
+	next := current.Next()
+	if next == nil || next.Value.count != current.Value.count+1 {
+		next = c.buckets.InsertAfter(&frequency[K, V]{count: current.Value.count + 1}, current)
+	}
+	entry.bucket = next
+	c.index.Insert(entry.key, next.Value.entries.Append(entry))
+	c.detach(current, e)
+}
+
+// detach removes an entry from a bucket and drops the bucket once it is empty
+func (c *LFU[K, V]) detach(bucket *list.Element[*frequency[K, V]], e *list.Element[*lfuEntry[K, V]]) {
+	bucket.Value.entries.Remove(e)
+	if bucket.Value.entries.Len() == 0 {
+		c.buckets.Remove(bucket)
+	}
+}
+
+// Put stores a value. A new key starts with a use count of one, after
+// evicting the least frequently used entry if the cache is full; an
+// existing key counts the Put as a use.
+func (c *LFU[K, V]) Put(key K, value V) {
+	if e, ok := c.index.Get(key); ok {
+		e.Value.value = value
+		c.touch(e)
+		return
+	}
+	if c.index.Len() == c.opts.Capacity {
+		c.evict()
+	}
+	first := c.buckets.Front()
+	if first == nil || first.Value.count != 1 {
+		first = c.buckets.Prepend(&frequency[K, V]{count: 1})
+	}
+	entry := &lfuEntry[K, V]{key: key, value: value, bucket: first}
+	c.index.Insert(key, first.Value.entries.Append(entry))
+}
+
+// evict removes the least recently used entry of the lowest use count
+func (c *LFU[K, V]) evict() {
+	bucket := c.buckets.Front()
+	e := bucket.Value.entries.Front()
+	c.detach(bucket, e)
+	c.index.Delete(e.Value.key)
+	c.stats.Evictions++
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(e.Value.key, e.Value.value)
+	}
+}
+
+// Delete removes a key and reports whether it was present
+func (c *LFU[K, V]) Delete(key K) bool {
+	e, ok := c.index.Get(key)
+	if ok {
+		c.detach(e.Value.bucket, e)
+		c.index.Delete(key)
+	}
+	return ok
+}
+
+// Len returns the number of entries in the cache
+func (c *LFU[K, V]) Len() int {
+	return c.index.Len()
+}
+
+// Stats returns the hit, miss and eviction counts
+func (c *LFU[K, V]) Stats() Stats {
+	return c.stats
+}
@@ -0,0 +1,91 @@
+package cache
+
+import (
+	"go-mastery/hash-tables/hashtable"
+	"go-mastery/lists/list"
+)
+
+// entry is a cached key-value pair stored in a list element
+type entry[K comparable, V any] struct {
+	key   K
+	value V
+}
+
+// LRU is a cache that evicts the least recently used entry. Entries are kept
+// in a doubly linked list from most to least recently used, and a hash table
+// maps each key to its list element, so Get and Put take O(1) time: a hit
+// moves the element to the front, and eviction removes the back.
+type LRU[K comparable, V any] struct {
+	opts  Options[K, V]
+	order *list.List[entry[K, V]]
+	index *hashtable.HashTable[K, *list.Element[entry[K, V]]]
+	stats Stats
+}
+
+var _ Cache[string, int] = (*LRU[string, int])(nil)
+
+// NewLRU creates an empty LRU cache. It panics if opts.Capacity is not positive.
+func NewLRU[K comparable, V any](opts Options[K, V]) *LRU[K, V] {
+	validCapacity(opts.Capacity)
+	return &LRU[K, V]{
+		opts:  opts,
+		order: list.New[entry[K, V]](),
+		index: newIndex[K, *list.Element[entry[K, V]]](opts.Capacity),
+	}
+}
+
+// Get returns the value stored for a key and marks it as most recently used
+func (c *LRU[K, V]) Get(key K) (V, bool) {
+	e, ok := c.index.Get(key)
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(e)
+	return e.Value.value, true
+}
+
+// Put stores a value as the most recently used entry, evicting the least
+// recently used entry if the cache is full
+func (c *LRU[K, V]) Put(key K, value V) {
+	if e, ok := c.index.Get(key); ok {
+		e.Value.value = value
+		c.order.MoveToFront(e)
+		return
+	}
+	if c.order.Len() == c.opts.Capacity {
+		c.evict(c.order.Back())
+	}
+	c.index.Insert(key, c.order.Prepend(entry[K, V]{key, value}))
+}
+
+func (c *LRU[K, V]) evict(e *list.Element[entry[K, V]]) {
+	c.order.Remove(e)
+	c.index.Delete(e.Value.key)
+	c.stats.Evictions++
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(e.Value.key, e.Value.value)
+	}
+}
+
+// Delete removes a key and reports whether it was present
+func (c *LRU[K, V]) Delete(key K) bool {
+	e, ok := c.index.Get(key)
+	if ok {
+		c.order.Remove(e)
+		c.index.Delete(key)
+	}
+	return ok
+}
+
+// Len returns the number of entries in the cache
+func (c *LRU[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Stats returns the hit, miss and eviction counts
+func (c *LRU[K, V]) Stats() Stats {
+	return c.stats
+}
@@ -0,0 +1,92 @@
+package cache
+
+import (
+	"hash/maphash"
+	"sync"
+)
+
+// shard is one cache of a Sharded cache and the lock that guards it
+type shard[K comparable, V any] struct {
+	mu    sync.Mutex
+	cache Cache[K, V]
+}
+
+// Sharded makes any Cache safe for concurrent use. It splits the keys over
+// several independent caches by hash, each behind its own mutex, so
+// goroutines working on keys in different shards do not wait for each
+// other. Eviction happens per shard: each shard evicts within its own
+// capacity, so the policy is only approximately global. Eviction callbacks
+// run while the shard is locked and must not call back into the cache.
+type Sharded[K comparable, V any] struct {
+	shards []shard[K, V]
+	seed   maphash.Seed
+}
+
+var _ Cache[string, int] = (*Sharded[string, int])(nil)
+
+// NewSharded creates a cache of n shards, each created by newShard. The
+// total capacity is the sum of the shard capacities. It panics if n is not
+// positive.
+func NewSharded[K comparable, V any](n int, newShard func() Cache[K, V]) *Sharded[K, V] {
+	if n <= 0 {
+		panic("cache: number of shards must be positive")
+	}
+	s := &Sharded[K, V]{shards: make([]shard[K, V], n), seed: maphash.MakeSeed()}
+	for i := range s.shards {
+		s.shards[i].cache = newShard()
+	}
+	return s
+}
+
+// shardFor returns the shard that owns a key
+func (s *Sharded[K, V]) shardFor(key K) *shard[K, V] {
+	return &s.shards[maphash.Comparable(s.seed, key)%uint64(len(s.shards))]
+}
+
+// Get returns the value stored for a key
+func (s *Sharded[K, V]) Get(key K) (V, bool) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.cache.Get(key)
+}
+
+// Put stores a value in the shard that owns the key
+func (s *Sharded[K, V]) Put(key K, value V) {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	sh.cache.Put(key, value)
+}
+
+// Delete removes a key and reports whether it was present
+func (s *Sharded[K, V]) Delete(key K) bool {
+	sh := s.shardFor(key)
+	sh.mu.Lock()
+	defer sh.mu.Unlock()
+	return sh.cache.Delete(key)
+}
+
+// Len returns the number of entries in all shards
+func (s *Sharded[K, V]) Len() int {
+	n := 0
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		n += sh.cache.Len()
+		sh.mu.Unlock()
+	}
+	return n
+}
+
+// Stats returns the counts summed over all shards
+func (s *Sharded[K, V]) Stats() Stats {
+	var total Stats
+	for i := range s.shards {
+		sh := &s.shards[i]
+		sh.mu.Lock()
+		total = total.add(sh.cache.Stats())
+		sh.mu.Unlock()
+	}
+	return total
+}
@@ -0,0 +1,154 @@
+package cache
+
+import (
+	"time"
+
+	"go-mastery/hash-tables/hashtable"
+	"go-mastery/lists/list"
+)
+
+// ttlEntry is a cached key-value pair with its expiry time
+type ttlEntry[K comparable, V any] struct {
+	key     K
+	value   V
+	expires time.Time
+}
+
+// TTL is an LRU cache in which every entry also expires after a time to
+// live. Expired entries are dropped when Get finds them, when Put needs room
+// (before any live entry is evicted), and by DeleteExpired. Until then they
+// still count towards Len.
+type TTL[K comparable, V any] struct {
+	opts  Options[K, V]
+	ttl   time.Duration
+	order *list.List[ttlEntry[K, V]]
+	index *hashtable.HashTable[K, *list.Element[ttlEntry[K, V]]]
+	stats Stats
+	now   func() time.Time // replaced in tests
+	// soonest is no later than the earliest expiry of any entry, so
+	// DeleteExpired can skip its scan while nothing can have expired
+	soonest time.Time
+}
+
+var _ Cache[string, int] = (*TTL[string, int])(nil)
+
+// NewTTL creates an empty cache whose entries live for ttl unless they are
+// stored with PutWithTTL. It panics if opts.Capacity or ttl is not positive.
+func NewTTL[K comparable, V any](opts Options[K, V], ttl time.Duration) *TTL[K, V] {
+	validCapacity(opts.Capacity)
+	if ttl <= 0 {
+		panic("cache: ttl must be positive")
+	}
+	return &TTL[K, V]{
+		opts:  opts,
+		ttl:   ttl,
+		order: list.New[ttlEntry[K, V]](),
+		index: newIndex[K, *list.Element[ttlEntry[K, V]]](opts.Capacity),
+		now:   time.Now,
+	}
+}
+
+// Get returns the value stored for a key if it has not expired, and marks
+// it as most recently used
+func (c *TTL[K, V]) Get(key K) (V, bool) {
+	e, ok := c.index.Get(key)
+	if ok && !c.now().Before(e.Value.expires) {
+		c.expire(e)
+		ok = false
+	}
+	if !ok {
+		c.stats.Misses++
+		var zero V
+		return zero, false
+	}
+	c.stats.Hits++
+	c.order.MoveToFront(e)
+	return e.Value.value, true
+}
+
+// Put stores a value that lives for the cache's default TTL
+func (c *TTL[K, V]) Put(key K, value V) {
+	c.PutWithTTL(key, value, c.ttl)
+}
+
+// PutWithTTL stores a value that lives for ttl as the most recently used
+// entry. If the cache is full, it drops the expired entries, or the least
+// recently used entry if none have expired.
+func (c *TTL[K, V]) PutWithTTL(key K, value V, ttl time.Duration) {
+	expires := c.now().Add(ttl)
+	if e, ok := c.index.Get(key); ok {
+		e.Value.value, e.Value.expires = value, expires
+		c.order.MoveToFront(e)
+	} else {
+		if c.order.Len() == c.opts.Capacity && c.DeleteExpired() == 0 {
+			back := c.order.Back()
+			c.remove(back)
+			c.stats.Evictions++
+			c.notify(back)
+		}
+		c.index.Insert(key, c.order.Prepend(ttlEntry[K, V]{key, value, expires}))
+	}
+	if c.soonest.IsZero() || expires.Before(c.soonest) {
+		c.soonest = expires
+	}
+}
+
+// DeleteExpired drops every expired entry and returns how many there were.
+// It scans all entries in O(n) time, but only once the earliest expiry
+// time has passed.
+func (c *TTL[K, V]) DeleteExpired() int {
+	now := c.now()
+	if c.soonest.IsZero() || now.Before(c.soonest) {
+		return 0
+	}
+	count := 0
+	c.soonest = time.Time{}
+	for e := c.order.Front(); e != nil; {
+		next := e.Next()
+		if !now.Before(e.Value.expires) {
+			c.expire(e)
+			count++
+		} else if c.soonest.IsZero() || e.Value.expires.Before(c.soonest) {
+			c.soonest = e.Value.expires
+		}
+		e = next
+	}
+	return count
+}
+
+func (c *TTL[K, V]) expire(e *list.Element[ttlEntry[K, V]]) {
+	c.remove(e)
+	c.stats.Expired++
+	c.notify(e)
+}
+
+func (c *TTL[K, V]) remove(e *list.Element[ttlEntry[K, V]]) {
+	c.order.Remove(e)
+	c.index.Delete(e.Value.key)
+}
+
+func (c *TTL[K, V]) notify(e *list.Element[ttlEntry[K, V]]) {
+	if c.opts.OnEvict != nil {
+		c.opts.OnEvict(e.Value.key, e.Value.value)
+	}
+}
+
+// Delete removes a key and reports whether it was present, expired or not
+func (c *TTL[K, V]) Delete(key K) bool {
+	e, ok := c.index.Get(key)
+	if ok {
+		c.remove(e)
+	}
+	return ok
+}
+
+// Len returns the number of entries in the cache, including expired
+// entries that have not been dropped yet
+func (c *TTL[K, V]) Len() int {
+	return c.order.Len()
+}
+
+// Stats returns the hit, miss, eviction and expiry counts
+func (c *TTL[K, V]) Stats() Stats {
+	return c.stats
+}
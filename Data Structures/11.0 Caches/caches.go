@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"go-mastery/caches/cache"
+)
+
+// fibonacci memoizes results in a bounded cache instead of an unbounded map
+func fibonacci(n int, memo cache.Cache[int, int]) int {
+	if n <= 1 {
+		return n
+	}
+	if val, ok := memo.Get(n); ok {
+		return val
+	}
+	result := fibonacci(n-1, memo) + fibonacci(n-2, memo)
+	memo.Put(n, result)
+	return result
+}
+
+func main() {
+	memo := cache.NewLRU(cache.Options[int, int]{Capacity: 8})
+	fmt.Println("fib(40) =", fibonacci(40, memo)) // Output: fib(40) = 102334155
+	fmt.Println("Entries:", memo.Len())           // Output: Entries: 8
+
+	// LRU forgets the key that was used longest ago
+	lru := cache.NewLRU(cache.Options[string, int]{
+		Capacity: 2,
+		OnEvict: func(key string, value int) {
+			fmt.Printf("evicted %s=%d\n", key, value)
+		},
+	})
+	lru.Put("a", 1)
+	lru.Put("b", 2)
+	lru.Get("a")
+	lru.Put("c", 3) // Output: evicted b=2
+
+	// LFU keeps the key that is used most often
+	lfu := cache.NewLFU(cache.Options[string, int]{Capacity: 2})
+	lfu.Put("home", 1)
+	lfu.Get("home")
+	lfu.Get("home")
+	for _, page := range []string{"x", "y", "z"} {
+		lfu.Put(page, 0)
+	}
+	_, ok := lfu.Get("home")
+	fmt.Println("home still cached:", ok) // Output: home still cached: true
+
+	// TTL entries expire after their time to live
+	sessions := cache.NewTTL(cache.Options[string, string]{Capacity: 100}, time.Hour)
+	sessions.PutWithTTL("token", "alice", 10*time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	_, ok = sessions.Get("token")
+	fmt.Println("token valid:", ok) // Output: token valid: false
+
+	// Sharded makes any cache safe for concurrent use
+	shared := cache.NewSharded(16, func() cache.Cache[int, string] {
+		return cache.NewLRU(cache.Options[int, string]{Capacity: 1024})
+	})
+	shared.Put(42, "answer")
+	shared.Get(42)
+	shared.Get(7)
+	fmt.Printf("%+v hit rate %.2f\n", shared.Stats(), shared.Stats().HitRate())
+	// Output: {Hits:1 Misses:1 Evictions:0 Expired:0} hit rate 0.50
+}
@@ -0,0 +1,13 @@
+module go-mastery/caches
+
+go 1.24.0
+
+require (
+	go-mastery/hash-tables v0.0.0
+	go-mastery/lists v0.0.0
+)
+
+replace (
+	go-mastery/hash-tables => "../4.0 Hash Tables"
+	go-mastery/lists => "../1.0 Lists"
+)
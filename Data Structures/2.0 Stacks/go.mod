@@ -0,0 +1,3 @@
+module go-mastery/stacks
+
+go 1.23.4
@@ -0,0 +1,152 @@
+// Package stack provides stacks that are safe for concurrent use by
+// multiple goroutines.
+package stack
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+)
+
+// ErrEmpty is returned by Pop and Peek when the stack has no items
+var ErrEmpty = errors.New("stack is empty")
+
+// ConcurrentStack is a LIFO stack that may be shared between goroutines
+type ConcurrentStack[T any] interface {
+	// Push adds an item to the top of the stack
+	Push(item T)
+	// Pop removes and returns the top item from the stack
+	Pop() (T, error)
+	// Peek returns the top item without removing it
+	Peek() (T, error)
+	// IsEmpty checks if the stack is empty
+	IsEmpty() bool
+	// Size returns the number of items in the stack
+	Size() int
+}
+
+// MutexStack is a slice-backed stack guarded by a sync.Mutex
+type MutexStack[T any] struct {
+	mu    sync.Mutex
+	items []T
+}
+
+// NewMutexStack creates an empty mutex-backed stack
+func NewMutexStack[T any]() *MutexStack[T] {
+	return &MutexStack[T]{}
+}
+
+// Push adds an item to the top of the stack
+func (s *MutexStack[T]) Push(item T) {
+	s.mu.Lock()
+	s.items = append(s.items, item)
+	s.mu.Unlock()
+}
+
+// Pop removes and returns the top item from the stack
+func (s *MutexStack[T]) Pop() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.items) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	topIndex := len(s.items) - 1
+	item := s.items[topIndex]
+	var zero T
+	s.items[topIndex] = zero // let the garbage collector reclaim the item
+	s.items = s.items[:topIndex]
+	return item, nil
+}
+
+// Peek returns the top item without removing it
+func (s *MutexStack[T]) Peek() (T, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.items) == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return s.items[len(s.items)-1], nil
+}
+
+// IsEmpty checks if the stack is empty
+func (s *MutexStack[T]) IsEmpty() bool {
+	return s.Size() == 0
+}
+
+// Size returns the number of items in the stack
+func (s *MutexStack[T]) Size() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.items)
+}
+
+// node is a single immutable link in a LockFreeStack
+type node[T any] struct {
+	value T
+	next  *node[T]
+}
+
+// LockFreeStack is a Treiber stack: a singly linked list whose head is
+// swapped with compare-and-swap instead of being guarded by a lock.
+// Nodes are never reused, so the garbage collector rules out the ABA problem.
+type LockFreeStack[T any] struct {
+	head atomic.Pointer[node[T]]
+	size atomic.Int64
+}
+
+// NewLockFreeStack creates an empty lock-free stack
+func NewLockFreeStack[T any]() *LockFreeStack[T] {
+	return &LockFreeStack[T]{}
+}
+
+// Push adds an item to the top of the stack
+func (s *LockFreeStack[T]) Push(item T) {
+	n := &node[T]{value: item}
+	for {
+		n.next = s.head.Load()
+		if s.head.CompareAndSwap(n.next, n) {
+			s.size.Add(1)
+			return
+		}
+	}
+}
+
+// Pop removes and returns the top item from the stack
+func (s *LockFreeStack[T]) Pop() (T, error) {
+	for {
+		top := s.head.Load()
+		if top == nil {
+			var zero T
+			return zero, ErrEmpty
+		}
+		if s.head.CompareAndSwap(top, top.next) {
+			s.size.Add(-1)
+			return top.value, nil
+		}
+	}
+}
+
+// Peek returns the top item without removing it
+func (s *LockFreeStack[T]) Peek() (T, error) {
+	top := s.head.Load()
+	if top == nil {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return top.value, nil
+}
+
+// IsEmpty checks if the stack is empty
+func (s *LockFreeStack[T]) IsEmpty() bool {
+	return s.head.Load() == nil
+}
+
+// Size returns the number of items in the stack. While other goroutines
+// are pushing or popping the result is only a snapshot.
+func (s *LockFreeStack[T]) Size() int {
+	// A Pop may count its item before the matching Push has, so the
+	// counter can dip below zero for a moment.
+	return max(int(s.size.Load()), 0)
+}
@@ -0,0 +1,143 @@
+package stack
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+// implementations lists every ConcurrentStack so each test runs against all of them.
+var implementations = []struct {
+	name string
+	new  func() ConcurrentStack[int]
+}{
+	{"Mutex", func() ConcurrentStack[int] { return NewMutexStack[int]() }},
+	{"LockFree", func() ConcurrentStack[int] { return NewLockFreeStack[int]() }},
+}
+
+func TestLIFO(t *testing.T) {
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			s := impl.new()
+			if _, err := s.Pop(); !errors.Is(err, ErrEmpty) {
+				t.Fatalf("Pop() on empty stack: err = %v; want ErrEmpty", err)
+			}
+			if _, err := s.Peek(); !errors.Is(err, ErrEmpty) {
+				t.Fatalf("Peek() on empty stack: err = %v; want ErrEmpty", err)
+			}
+
+			for i := 1; i <= 3; i++ {
+				s.Push(i * 10)
+			}
+			if s.Size() != 3 || s.IsEmpty() {
+				t.Fatalf("Size() = %d, IsEmpty() = %t; want 3, false", s.Size(), s.IsEmpty())
+			}
+			if top, _ := s.Peek(); top != 30 {
+				t.Fatalf("Peek() = %d; want 30", top)
+			}
+			for _, want := range []int{30, 20, 10} {
+				if got, err := s.Pop(); err != nil || got != want {
+					t.Fatalf("Pop() = %d, %v; want %d, nil", got, err, want)
+				}
+			}
+			if !s.IsEmpty() {
+				t.Fatalf("stack should be empty")
+			}
+		})
+	}
+}
+
+// TestConcurrentPushPop checks that under contention every pushed value is
+// popped exactly once. Run it with -race.
+func TestConcurrentPushPop(t *testing.T) {
+	const goroutines, perGoroutine = 8, 2000
+
+	for _, impl := range implementations {
+		t.Run(impl.name, func(t *testing.T) {
+			s := impl.new()
+			popped := make([][]int, goroutines)
+
+			var wg sync.WaitGroup
+			for g := range goroutines {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range perGoroutine {
+						s.Push(g*perGoroutine + i)
+						if i%2 == 1 {
+							for range 2 {
+								if v, err := s.Pop(); err == nil {
+									popped[g] = append(popped[g], v)
+								}
+							}
+						}
+					}
+				}()
+			}
+			wg.Wait()
+
+			seen := make(map[int]bool)
+			for _, values := range popped {
+				for _, v := range values {
+					if seen[v] {
+						t.Fatalf("value %d popped twice", v)
+					}
+					seen[v] = true
+				}
+			}
+			for !s.IsEmpty() {
+				v, err := s.Pop()
+				if err != nil {
+					t.Fatalf("Pop() on non-empty stack: %v", err)
+				}
+				if seen[v] {
+					t.Fatalf("value %d popped twice", v)
+				}
+				seen[v] = true
+			}
+			if len(seen) != goroutines*perGoroutine {
+				t.Fatalf("popped %d distinct values; want %d", len(seen), goroutines*perGoroutine)
+			}
+			if s.Size() != 0 {
+				t.Fatalf("Size() = %d after draining; want 0", s.Size())
+			}
+		})
+	}
+}
+
+// BenchmarkContention compares the stacks when every goroutine pushes and
+// pops the same stack. Use -cpu=1,4,8 to vary the amount of contention.
+func BenchmarkContention(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			s := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					s.Push(i)
+					s.Pop()
+					i++
+				}
+			})
+		})
+	}
+}
+
+// BenchmarkPushHeavy measures a workload where the stack keeps growing.
+func BenchmarkPushHeavy(b *testing.B) {
+	for _, impl := range implementations {
+		b.Run(impl.name, func(b *testing.B) {
+			s := impl.new()
+			b.RunParallel(func(pb *testing.PB) {
+				i := 0
+				for pb.Next() {
+					s.Push(i)
+					if i%4 == 0 {
+						s.Pop()
+					}
+					i++
+				}
+			})
+		})
+	}
+}
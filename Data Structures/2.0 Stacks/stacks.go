@@ -3,6 +3,9 @@ package main
 import (
 	"errors"
 	"fmt"
+	"sync"
+
+	"go-mastery/stacks/stack"
 )
 
 // Stack represents a stack data structure
@@ -44,20 +47,46 @@ func (s *Stack) Size() int {
 	return len(s.items)
 }
 
+// concurrentStacks fills each ConcurrentStack from several goroutines at once.
+// Stack is not safe to share between goroutines; these stacks are.
+func concurrentStacks() {
+	for _, shared := range []stack.ConcurrentStack[int]{
+		stack.NewMutexStack[int](),
+		stack.NewLockFreeStack[int](),
+	} {
+		var wg sync.WaitGroup
+		for worker := 0; worker < 4; worker++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				for i := 0; i < 100; i++ {
+					shared.Push(i)
+				}
+			}()
+		}
+		wg.Wait()
+		fmt.Printf("%T size: %d\n", shared, shared.Size())
+	}
+	// Output: *stack.MutexStack[int] size: 400
+	// Output: *stack.LockFreeStack[int] size: 400
+}
+
 func main() {
-	stack := &Stack{}
+	s := &Stack{}
 
-	stack.Push(10)
-	stack.Push(20)
-	stack.Push(30)
+	s.Push(10)
+	s.Push(20)
+	s.Push(30)
 
-	fmt.Println("Stack size:", stack.Size()) // Output: Stack size: 3
+	fmt.Println("Stack size:", s.Size()) // Output: Stack size: 3
 
-	topItem, _ := stack.Peek()
+	topItem, _ := s.Peek()
 	fmt.Println("Top item:", topItem) // Output: Top item: 30
 
-	poppedItem, _ := stack.Pop()
+	poppedItem, _ := s.Pop()
 	fmt.Println("Popped item:", poppedItem) // Output: Popped item: 30
 
-	fmt.Println("Stack size after pop:", stack.Size()) // Output: Stack size after pop: 2
+	fmt.Println("Stack size after pop:", s.Size()) // Output: Stack size after pop: 2
+
+	concurrentStacks()
 }
@@ -0,0 +1,3 @@
+module go-mastery/queues
+
+go 1.23.4
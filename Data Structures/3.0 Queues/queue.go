@@ -1,19 +1,51 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"time"
+
+	"go-mastery/queues/queue"
 )
 
 func main() {
-	queue := make(chan string, 3)
-
-	queue <- "task1"
-	queue <- "task2"
-	queue <- "task3"
+	// Queue grows as needed and can be inspected without draining it
+	tasks := queue.New[string]()
+	tasks.Enqueue("task1")
+	tasks.Enqueue("task2")
+	tasks.Enqueue("task3")
 
-	close(queue)
+	next, _ := tasks.Peek()
+	fmt.Println("Next:", next, "of", tasks.Len()) // Output: Next: task1 of 3
 
-	for item := range queue {
+	for !tasks.IsEmpty() {
+		item, _ := tasks.Dequeue()
 		fmt.Println("Dequeued:", item)
 	}
+
+	// Deque supports pushing and popping at both ends
+	d := queue.NewDeque[int]()
+	d.PushBack(2)
+	d.PushBack(3)
+	d.PushFront(1)
+	front, _ := d.PopFront()
+	back, _ := d.PopBack()
+	fmt.Println("Front:", front, "Back:", back) // Output: Front: 1 Back: 3
+
+	// BlockingQueue waits for a producer or gives up when the context ends
+	jobs := queue.NewBlockingQueue[string]()
+	go func() {
+		time.Sleep(50 * time.Millisecond)
+		jobs.Enqueue("build")
+	}()
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	job, err := jobs.Dequeue(ctx)
+	fmt.Println("Job:", job, err) // Output: Job: build <nil>
+
+	ctx, cancel = context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = jobs.Dequeue(ctx)
+	fmt.Println("Error:", err) // Output: Error: context deadline exceeded
 }
@@ -0,0 +1,156 @@
+// Package queue provides FIFO queues and double-ended queues backed by a
+// growable ring buffer, plus a blocking queue for producer/consumer code.
+package queue
+
+import (
+	"errors"
+	"iter"
+)
+
+// ErrEmpty is returned when removing or peeking at an item of an empty queue
+var ErrEmpty = errors.New("queue is empty")
+
+// minCapacity is the smallest ring buffer a non-empty Deque allocates
+const minCapacity = 8
+
+// Deque is a double-ended queue backed by a ring buffer.
+// Items can be added and removed at both ends in amortized O(1).
+// The zero value is an empty deque ready to use.
+type Deque[T any] struct {
+	buf  []T // len(buf) is always zero or a power of two
+	head int // index of the front item
+	n    int // number of items
+}
+
+// NewDeque creates an empty deque
+func NewDeque[T any]() *Deque[T] {
+	return &Deque[T]{}
+}
+
+// Len returns the number of items in the deque
+func (d *Deque[T]) Len() int {
+	return d.n
+}
+
+// IsEmpty checks if the deque is empty
+func (d *Deque[T]) IsEmpty() bool {
+	return d.n == 0
+}
+
+// index maps the i-th item from the front to its slot in buf
+func (d *Deque[T]) index(i int) int {
+	return (d.head + i) & (len(d.buf) - 1)
+}
+
+// PushBack adds an item at the back of the deque
+func (d *Deque[T]) PushBack(item T) {
+	d.grow()
+	d.buf[d.index(d.n)] = item
+	d.n++
+}
+
+// PushFront adds an item at the front of the deque
+func (d *Deque[T]) PushFront(item T) {
+	d.grow()
+	d.head = d.index(len(d.buf) - 1)
+	d.buf[d.head] = item
+	d.n++
+}
+
+// PopFront removes and returns the item at the front of the deque
+func (d *Deque[T]) PopFront() (T, error) {
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	item := d.buf[d.head]
+	d.buf[d.head] = zero // let the garbage collector reclaim the item
+	d.head = d.index(1)
+	d.n--
+	d.shrink()
+	return item, nil
+}
+
+// PopBack removes and returns the item at the back of the deque
+func (d *Deque[T]) PopBack() (T, error) {
+	var zero T
+	if d.n == 0 {
+		return zero, ErrEmpty
+	}
+	tail := d.index(d.n - 1)
+	item := d.buf[tail]
+	d.buf[tail] = zero
+	d.n--
+	d.shrink()
+	return item, nil
+}
+
+// Front returns the item at the front without removing it
+func (d *Deque[T]) Front() (T, error) {
+	if d.n == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return d.buf[d.head], nil
+}
+
+// Back returns the item at the back without removing it
+func (d *Deque[T]) Back() (T, error) {
+	if d.n == 0 {
+		var zero T
+		return zero, ErrEmpty
+	}
+	return d.buf[d.index(d.n-1)], nil
+}
+
+// At returns the i-th item counting from the front. It panics if i is out of range.
+func (d *Deque[T]) At(i int) T {
+	if i < 0 || i >= d.n {
+		panic("queue: index out of range")
+	}
+	return d.buf[d.index(i)]
+}
+
+// All returns an iterator over the items from front to back without removing them
+func (d *Deque[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.n; i++ {
+			if !yield(d.buf[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// Clear removes all items from the deque
+func (d *Deque[T]) Clear() {
+	*d = Deque[T]{}
+}
+
+// grow doubles the ring buffer when it is full
+func (d *Deque[T]) grow() {
+	if d.n < len(d.buf) {
+		return
+	}
+	d.resize(max(2*len(d.buf), minCapacity))
+}
+
+// shrink halves the ring buffer once it is at most a quarter full
+func (d *Deque[T]) shrink() {
+	if len(d.buf) > minCapacity && d.n <= len(d.buf)/4 {
+		d.resize(len(d.buf) / 2)
+	}
+}
+
+// resize copies the items into a new buffer of the given capacity, front first
+func (d *Deque[T]) resize(capacity int) {
+	buf := make([]T, capacity)
+	if d.head+d.n <= len(d.buf) {
+		copy(buf, d.buf[d.head:d.head+d.n])
+	} else {
+		k := copy(buf, d.buf[d.head:])
+		copy(buf[k:], d.buf[:d.n-k])
+	}
+	d.buf = buf
+	d.head = 0
+}
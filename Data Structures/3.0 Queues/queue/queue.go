@@ -0,0 +1,123 @@
+package queue
+
+import (
+	"context"
+	"iter"
+	"sync"
+)
+
+// Queue is a FIFO queue backed by a growable ring buffer.
+// The zero value is an empty queue ready to use.
+type Queue[T any] struct {
+	items Deque[T]
+}
+
+// New creates an empty queue
+func New[T any]() *Queue[T] {
+	return &Queue[T]{}
+}
+
+// Enqueue adds an item to the end of the queue
+func (q *Queue[T]) Enqueue(item T) {
+	q.items.PushBack(item)
+}
+
+// Dequeue removes and returns the item at the front of the queue
+func (q *Queue[T]) Dequeue() (T, error) {
+	return q.items.PopFront()
+}
+
+// Peek returns the item at the front of the queue without removing it
+func (q *Queue[T]) Peek() (T, error) {
+	return q.items.Front()
+}
+
+// Len returns the number of items in the queue
+func (q *Queue[T]) Len() int {
+	return q.items.Len()
+}
+
+// IsEmpty checks if the queue is empty
+func (q *Queue[T]) IsEmpty() bool {
+	return q.items.IsEmpty()
+}
+
+// All returns an iterator over the items in dequeue order without removing them
+func (q *Queue[T]) All() iter.Seq[T] {
+	return q.items.All()
+}
+
+// BlockingQueue is an unbounded FIFO queue that is safe for concurrent use.
+// Dequeue waits for an item instead of failing when the queue is empty.
+// The zero value is an empty queue ready to use.
+type BlockingQueue[T any] struct {
+	mu    sync.Mutex
+	items Queue[T]
+	// ready is created by the first Dequeue that has to wait, and closed
+	// and cleared by the next Enqueue, waking up every waiting goroutine.
+	ready chan struct{}
+}
+
+// NewBlockingQueue creates an empty blocking queue
+func NewBlockingQueue[T any]() *BlockingQueue[T] {
+	return &BlockingQueue[T]{}
+}
+
+// Enqueue adds an item to the end of the queue and wakes up waiting consumers
+func (q *BlockingQueue[T]) Enqueue(item T) {
+	q.mu.Lock()
+	q.items.Enqueue(item)
+	if q.ready != nil {
+		close(q.ready)
+		q.ready = nil
+	}
+	q.mu.Unlock()
+}
+
+// Dequeue removes and returns the item at the front of the queue.
+// If the queue is empty it waits until an item arrives or ctx is done,
+// in which case it returns ctx.Err().
+func (q *BlockingQueue[T]) Dequeue(ctx context.Context) (T, error) {
+	for {
+		q.mu.Lock()
+		if item, err := q.items.Dequeue(); err == nil {
+			q.mu.Unlock()
+			return item, nil
+		}
+		if q.ready == nil {
+			q.ready = make(chan struct{})
+		}
+		ready := q.ready
+		q.mu.Unlock()
+
+		select {
+		case <-ready:
+			// Another consumer may win the race for the item, so check again.
+		case <-ctx.Done():
+			var zero T
+			return zero, ctx.Err()
+		}
+	}
+}
+
+// TryDequeue removes and returns the item at the front of the queue
+// without waiting. It returns ErrEmpty if the queue is empty.
+func (q *BlockingQueue[T]) TryDequeue() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Dequeue()
+}
+
+// Peek returns the item at the front of the queue without removing it
+func (q *BlockingQueue[T]) Peek() (T, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Peek()
+}
+
+// Len returns the number of items in the queue
+func (q *BlockingQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.items.Len()
+}
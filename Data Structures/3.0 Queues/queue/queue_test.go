@@ -0,0 +1,181 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestQueueFIFO(t *testing.T) {
+	var q Queue[int]
+	if _, err := q.Dequeue(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Dequeue() on empty queue: err = %v; want ErrEmpty", err)
+	}
+	// Interleave enqueues and dequeues so the ring wraps around and grows.
+	next := 0
+	for i := range 100 {
+		q.Enqueue(i)
+		if i%3 == 0 {
+			got, err := q.Dequeue()
+			if err != nil || got != next {
+				t.Fatalf("Dequeue() = %d, %v; want %d, nil", got, err, next)
+			}
+			next++
+		}
+	}
+	if front, _ := q.Peek(); front != next {
+		t.Fatalf("Peek() = %d; want %d", front, next)
+	}
+	if got := slices.Collect(q.All()); len(got) != q.Len() || got[0] != next {
+		t.Fatalf("All() = %v with Len() = %d", got, q.Len())
+	}
+	for !q.IsEmpty() {
+		got, _ := q.Dequeue()
+		if got != next {
+			t.Fatalf("Dequeue() = %d; want %d", got, next)
+		}
+		next++
+	}
+	if next != 100 {
+		t.Fatalf("dequeued %d items; want 100", next)
+	}
+}
+
+// TestDequeAgainstSlice replays a fixed sequence of operations on a Deque and
+// on a plain slice and compares the contents after every step.
+func TestDequeAgainstSlice(t *testing.T) {
+	var d Deque[int]
+	var want []int
+	for i := range 1000 {
+		switch i % 7 {
+		case 0, 2, 5:
+			d.PushBack(i)
+			want = append(want, i)
+		case 1, 4:
+			d.PushFront(i)
+			want = slices.Insert(want, 0, i)
+		case 3:
+			got, err := d.PopFront()
+			if len(want) == 0 {
+				if !errors.Is(err, ErrEmpty) {
+					t.Fatalf("PopFront() on empty deque: err = %v", err)
+				}
+				continue
+			}
+			if got != want[0] {
+				t.Fatalf("PopFront() = %d; want %d", got, want[0])
+			}
+			want = want[1:]
+		case 6:
+			if i%2 == 0 {
+				// Drain half of the items from the back to exercise shrinking.
+				for range len(want) / 2 {
+					got, _ := d.PopBack()
+					if got != want[len(want)-1] {
+						t.Fatalf("PopBack() = %d; want %d", got, want[len(want)-1])
+					}
+					want = want[:len(want)-1]
+				}
+			}
+		}
+		if got := slices.Collect(d.All()); !slices.Equal(got, want) {
+			t.Fatalf("step %d: All() = %v; want %v", i, got, want)
+		}
+		if d.Len() > 0 {
+			front, _ := d.Front()
+			back, _ := d.Back()
+			if front != want[0] || back != want[len(want)-1] || d.At(d.Len()/2) != want[len(want)/2] {
+				t.Fatalf("step %d: Front/Back/At disagree with %v", i, want)
+			}
+		}
+	}
+}
+
+func TestBlockingQueueWaitsForItem(t *testing.T) {
+	q := NewBlockingQueue[string]()
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		q.Enqueue("job")
+	}()
+	got, err := q.Dequeue(context.Background())
+	if err != nil || got != "job" {
+		t.Fatalf("Dequeue() = %q, %v; want %q, nil", got, err, "job")
+	}
+}
+
+func TestBlockingQueueZeroValue(t *testing.T) {
+	var q BlockingQueue[int]
+	q.Enqueue(1)
+	if got, err := q.Dequeue(context.Background()); err != nil || got != 1 {
+		t.Fatalf("Dequeue() = %d, %v; want 1, nil", got, err)
+	}
+
+	var waiting BlockingQueue[int]
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		waiting.Enqueue(2)
+	}()
+	if got, err := waiting.Dequeue(context.Background()); err != nil || got != 2 {
+		t.Fatalf("Dequeue() on an empty zero value = %d, %v; want 2, nil", got, err)
+	}
+}
+
+func TestBlockingQueueCancel(t *testing.T) {
+	q := NewBlockingQueue[int]()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := q.Dequeue(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Dequeue() err = %v; want context.DeadlineExceeded", err)
+	}
+	if _, err := q.TryDequeue(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("TryDequeue() err = %v; want ErrEmpty", err)
+	}
+}
+
+// TestBlockingQueueProducersConsumers checks that every item is delivered
+// exactly once when several producers and consumers share the queue.
+func TestBlockingQueueProducersConsumers(t *testing.T) {
+	const producers, consumers, perProducer = 4, 4, 500
+	q := NewBlockingQueue[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results := make(chan int)
+	var consumerWG sync.WaitGroup
+	for range consumers {
+		consumerWG.Add(1)
+		go func() {
+			defer consumerWG.Done()
+			for {
+				item, err := q.Dequeue(ctx)
+				if err != nil {
+					return
+				}
+				results <- item
+			}
+		}()
+	}
+	for p := range producers {
+		go func() {
+			for i := range perProducer {
+				q.Enqueue(p*perProducer + i)
+			}
+		}()
+	}
+
+	seen := make(map[int]bool)
+	for range producers * perProducer {
+		item := <-results
+		if seen[item] {
+			t.Fatalf("item %d delivered twice", item)
+		}
+		seen[item] = true
+	}
+	cancel()
+	consumerWG.Wait()
+	if q.Len() != 0 {
+		t.Fatalf("Len() = %d after all items were consumed", q.Len())
+	}
+}
@@ -0,0 +1,152 @@
+package filter
+
+import (
+	"encoding/binary"
+	"iter"
+	"math"
+	"math/bits"
+)
+
+// OptimalSize returns the number of bits and hash functions a Bloom filter
+// needs to hold n keys with a false positive rate of about p. The number of
+// bits is rounded up to a multiple of 64. It panics if n is not positive or
+// p is not between 0 and 1.
+func OptimalSize(n int, p float64) (m, k int) {
+	if n <= 0 {
+		panic("filter: expected number of keys must be positive")
+	}
+	if !(p > 0 && p < 1) {
+		panic("filter: false positive rate must be between 0 and 1")
+	}
+	bitsNeeded := math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2))
+	m = (int(bitsNeeded) + 63) / 64 * 64
+	k = max(1, int(math.Round(float64(m)/float64(n)*math.Ln2)))
+	return m, k
+}
+
+// Bloom is a Bloom filter: a bit array in which every key sets k bits
+// chosen by hashing. Contains reports true only if all of a key's bits are
+// set, so it never misses a key that was added but may report a key that
+// was not (a false positive). Keys cannot be removed; use CountingBloom for
+// that.
+type Bloom[K ~string | ~[]byte] struct {
+	bits  []uint64
+	k     int
+	count int
+}
+
+var _ Filter[string] = (*Bloom[string])(nil)
+
+// NewBloom creates an empty Bloom filter sized by OptimalSize for n keys
+// and a false positive rate of p
+func NewBloom[K ~string | ~[]byte](n int, p float64) *Bloom[K] {
+	m, k := OptimalSize(n, p)
+	return &Bloom[K]{bits: make([]uint64, m/64), k: k}
+}
+
+// locations returns the k positions of a key in a filter of m slots, using
+// double hashing: the i-th position is h1 + i*h2 reduced to the filter size
+func locations[K ~string | ~[]byte](key K, k int, m uint64) iter.Seq[uint64] {
+	return func(yield func(uint64) bool) {
+		h1, h2 := hash(key)
+		for range k {
+			if !yield(reduce(h1, m)) {
+				return
+			}
+			h1 += h2
+		}
+	}
+}
+
+// Add inserts a key
+func (b *Bloom[K]) Add(key K) {
+	for i := range locations(key, b.k, b.size()) {
+		b.bits[i/64] |= 1 << (i % 64)
+	}
+	b.count++
+}
+
+// Contains reports whether a key may have been added. False means the key
+// was definitely never added.
+func (b *Bloom[K]) Contains(key K) bool {
+	for i := range locations(key, b.k, b.size()) {
+		if b.bits[i/64]&(1<<(i%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the number of keys added, counting duplicates and including
+// the keys of merged filters
+func (b *Bloom[K]) Len() int {
+	return b.count
+}
+
+// size returns the number of bits in the filter
+func (b *Bloom[K]) size() uint64 {
+	return uint64(len(b.bits)) * 64
+}
+
+// Hashes returns the number of bits each key sets
+func (b *Bloom[K]) Hashes() int {
+	return b.k
+}
+
+// Bits returns the number of bits in the filter
+func (b *Bloom[K]) Bits() int {
+	return int(b.size())
+}
+
+// FalsePositiveRate estimates the current false positive rate from the
+// fraction of bits that are set
+func (b *Bloom[K]) FalsePositiveRate() float64 {
+	set := 0
+	for _, w := range b.bits {
+		set += bits.OnesCount64(w)
+	}
+	return math.Pow(float64(set)/float64(b.size()), float64(b.k))
+}
+
+// Merge adds every key of other to b, as if they had been added to b
+// directly. Both filters must have the same number of bits and hashes.
+func (b *Bloom[K]) Merge(other *Bloom[K]) error {
+	if len(b.bits) != len(other.bits) || b.k != other.k {
+		return ErrIncompatible
+	}
+	for i, w := range other.bits {
+		b.bits[i] |= w
+	}
+	b.count += other.count
+	return nil
+}
+
+const bloomMagic = "BLM1"
+
+// MarshalBinary encodes the filter as the magic string "BLM1", the number
+// of hashes, the number of keys and the bit array as little-endian 64-bit
+// words
+func (b *Bloom[K]) MarshalBinary() ([]byte, error) {
+	data := header(bloomMagic, uint64(b.k), uint64(b.count))
+	for _, w := range b.bits {
+		data = binary.LittleEndian.AppendUint64(data, w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary
+func (b *Bloom[K]) UnmarshalBinary(data []byte) error {
+	params, data, err := parseHeader(data, bloomMagic, 2)
+	if err != nil {
+		return err
+	}
+	if params[0] == 0 || params[0] > math.MaxInt32 || len(data) == 0 || len(data)%8 != 0 {
+		return ErrCorrupt
+	}
+	words := make([]uint64, len(data)/8)
+	for i := range words {
+		words[i] = binary.LittleEndian.Uint64(data[8*i:])
+	}
+	b.bits, b.k, b.count = words, int(params[0]), int(params[1])
+	return nil
+}
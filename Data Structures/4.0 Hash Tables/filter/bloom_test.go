@@ -0,0 +1,237 @@
+package filter
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+func keys(prefix string, n int) []string {
+	keys := make([]string, n)
+	for i := range keys {
+		keys[i] = fmt.Sprintf("%s-%d", prefix, i)
+	}
+	return keys
+}
+
+// falsePositives returns the fraction of 100000 keys that were never added
+// but that f reports as present
+func falsePositives(f Filter[string]) float64 {
+	count := 0
+	for _, key := range keys("absent", 100000) {
+		if f.Contains(key) {
+			count++
+		}
+	}
+	return float64(count) / 100000
+}
+
+func TestOptimalSize(t *testing.T) {
+	for _, tc := range []struct {
+		n    int
+		p    float64
+		m, k int
+	}{
+		{1000, 0.01, 9600, 7},
+		{1000, 0.001, 14400, 10},
+		{1, 0.5, 64, 44},
+	} {
+		if m, k := OptimalSize(tc.n, tc.p); m != tc.m || k != tc.k {
+			t.Errorf("OptimalSize(%d, %v) = %d, %d; want %d, %d", tc.n, tc.p, m, k, tc.m, tc.k)
+		}
+	}
+}
+
+func TestBloom(t *testing.T) {
+	const n, p = 10000, 0.01
+	b := NewBloom[string](n, p)
+	added := keys("key", n)
+	for _, key := range added {
+		b.Add(key)
+	}
+	for _, key := range added {
+		if !b.Contains(key) {
+			t.Fatalf("Contains(%q) = false after Add", key)
+		}
+	}
+	if rate := falsePositives(b); rate > 1.5*p {
+		t.Fatalf("false positive rate %v; want about %v", rate, p)
+	}
+	if est := b.FalsePositiveRate(); est < p/2 || est > 1.5*p {
+		t.Fatalf("FalsePositiveRate() = %v; want about %v", est, p)
+	}
+	if b.Len() != n {
+		t.Fatalf("Len() = %d; want %d", b.Len(), n)
+	}
+
+	// Filters also take []byte keys
+	raw := NewBloom[[]byte](n, p)
+	raw.Add([]byte("key-1"))
+	if !raw.Contains([]byte("key-1")) || raw.Contains([]byte("key-2")) {
+		t.Fatalf("[]byte filter gives wrong answers")
+	}
+}
+
+func TestBloomMergeAndMarshal(t *testing.T) {
+	a, b := NewBloom[string](1000, 0.01), NewBloom[string](1000, 0.01)
+	for _, key := range keys("a", 500) {
+		a.Add(key)
+	}
+	for _, key := range keys("b", 500) {
+		b.Add(key)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	data, _ := a.MarshalBinary()
+	var restored Bloom[string]
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	for _, key := range append(keys("a", 500), keys("b", 500)...) {
+		if !restored.Contains(key) {
+			t.Fatalf("merged and restored filter misses %q", key)
+		}
+	}
+	if restored.Len() != 1000 || restored.Hashes() != a.Hashes() || restored.Bits() != a.Bits() {
+		t.Fatalf("restored filter has Len %d, %d hashes, %d bits", restored.Len(), restored.Hashes(), restored.Bits())
+	}
+
+	if err := a.Merge(NewBloom[string](1000, 0.001)); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Merge of different sizes = %v; want ErrIncompatible", err)
+	}
+	for _, bad := range [][]byte{nil, data[:10], data[:len(data)-1], append([]byte("XXXX"), data[4:]...)} {
+		if err := restored.UnmarshalBinary(bad); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("UnmarshalBinary of %d bytes = %v; want ErrCorrupt", len(bad), err)
+		}
+	}
+}
+
+// TestCountingBloom adds and removes random keys, only removing keys that
+// are present, and checks that the filter never misses a present key
+func TestCountingBloom(t *testing.T) {
+	const n, p = 2000, 0.01
+	c := NewCountingBloom[string](n, p)
+	present := map[string]int{}
+	rng := rand.New(rand.NewSource(1))
+	for range 20000 {
+		key := fmt.Sprintf("key-%d", rng.Intn(n))
+		if rng.Intn(2) == 0 {
+			c.Add(key)
+			present[key]++
+		} else if present[key] > 0 {
+			if !c.Remove(key) {
+				t.Fatalf("Remove(%q) = false for a present key", key)
+			}
+			present[key]--
+		}
+		if present[key] > 0 && !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false for a present key", key)
+		}
+	}
+	total := 0
+	for key, count := range present {
+		if count > 0 && !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false for a present key", key)
+		}
+		total += count
+	}
+	if c.Len() != total {
+		t.Fatalf("Len() = %d; want %d", c.Len(), total)
+	}
+
+	// Removing everything leaves an empty filter
+	for key, count := range present {
+		for range count {
+			c.Remove(key)
+		}
+	}
+	if rate := falsePositives(c); rate != 0 {
+		t.Fatalf("false positive rate %v after removing every key", rate)
+	}
+}
+
+// TestCountingBloomSaturation adds a key more often than a counter can
+// count, which must not make the filter miss it after one removal
+func TestCountingBloomSaturation(t *testing.T) {
+	c := NewCountingBloom[string](100, 0.01)
+	for range 20 {
+		c.Add("hot")
+	}
+	for range 10 {
+		c.Remove("hot")
+	}
+	if !c.Contains("hot") {
+		t.Fatalf("saturated key was lost")
+	}
+	if c.Remove("cold") {
+		t.Fatalf("Remove of an absent key = true")
+	}
+}
+
+func TestCountingBloomMergeAndMarshal(t *testing.T) {
+	a, b := NewCountingBloom[string](1000, 0.01), NewCountingBloom[string](1000, 0.01)
+	a.Add("shared")
+	b.Add("shared")
+	b.Add("only-b")
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	data, _ := a.MarshalBinary()
+	var restored CountingBloom[string]
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	// shared was added twice, so it survives one removal
+	if !restored.Remove("shared") || !restored.Contains("shared") || !restored.Contains("only-b") {
+		t.Fatalf("merged and restored filter lost keys")
+	}
+	if restored.Len() != 2 {
+		t.Fatalf("Len() = %d; want 2", restored.Len())
+	}
+	if err := a.Merge(NewCountingBloom[string](10, 0.01)); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Merge of different sizes = %v; want ErrIncompatible", err)
+	}
+	if err := restored.UnmarshalBinary(data[:20]); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("UnmarshalBinary of truncated data = %v; want ErrCorrupt", err)
+	}
+}
+
+func BenchmarkContains(b *testing.B) {
+	const n = 1 << 16
+	added := keys("key", n)
+	for _, tc := range []struct {
+		name  string
+		build func() Filter[string]
+	}{
+		{"Bloom", func() Filter[string] {
+			f := NewBloom[string](n, 0.01)
+			for _, key := range added {
+				f.Add(key)
+			}
+			return f
+		}},
+		{"CountingBloom", func() Filter[string] {
+			f := NewCountingBloom[string](n, 0.01)
+			for _, key := range added {
+				f.Add(key)
+			}
+			return f
+		}},
+		{"Cuckoo", func() Filter[string] {
+			f := NewCuckoo[string](n)
+			for _, key := range added {
+				f.Add(key)
+			}
+			return f
+		}},
+	} {
+		f := tc.build()
+		b.Run(tc.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				f.Contains(added[i%n])
+			}
+		})
+	}
+}
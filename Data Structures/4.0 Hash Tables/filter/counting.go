@@ -0,0 +1,132 @@
+package filter
+
+import "math"
+
+// maxCount is the largest value of a 4-bit counter
+const maxCount = 15
+
+// CountingBloom is a Bloom filter that replaces each bit with a 4-bit
+// counter, so keys can be removed as well as added. It uses four times the
+// memory of a Bloom filter with the same false positive rate. A counter
+// that reaches 15 sticks there, because after an overflow it is no longer
+// known how many keys share it, and decrementing it could make the filter
+// miss a key that is still present.
+type CountingBloom[K ~string | ~[]byte] struct {
+	counters []byte // two counters per byte, the even one in the low nibble
+	k        int
+	count    int
+}
+
+var _ Filter[string] = (*CountingBloom[string])(nil)
+
+// NewCountingBloom creates an empty counting Bloom filter sized by
+// OptimalSize for n keys and a false positive rate of p
+func NewCountingBloom[K ~string | ~[]byte](n int, p float64) *CountingBloom[K] {
+	m, k := OptimalSize(n, p)
+	return &CountingBloom[K]{counters: make([]byte, m/2), k: k}
+}
+
+// size returns the number of counters in the filter
+func (c *CountingBloom[K]) size() uint64 {
+	return uint64(len(c.counters)) * 2
+}
+
+// get returns the counter at index i
+func (c *CountingBloom[K]) get(i uint64) byte {
+	return c.counters[i/2] >> (4 * (i % 2)) & 0xf
+}
+
+// set stores v in the counter at index i
+func (c *CountingBloom[K]) set(i uint64, v byte) {
+	shift := 4 * (i % 2)
+	c.counters[i/2] = c.counters[i/2]&^(0xf<<shift) | v<<shift
+}
+
+// Add inserts a key
+func (c *CountingBloom[K]) Add(key K) {
+	for i := range locations(key, c.k, c.size()) {
+		if v := c.get(i); v < maxCount {
+			c.set(i, v+1)
+		}
+	}
+	c.count++
+}
+
+// Contains reports whether a key may be present. False means the key is
+// definitely not present.
+func (c *CountingBloom[K]) Contains(key K) bool {
+	for i := range locations(key, c.k, c.size()) {
+		if c.get(i) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// Remove deletes one copy of a key and reports whether the key may have
+// been present. Removing a key that was never added, but that the filter
+// reports as present, corrupts the filter and can make it miss other keys,
+// so only remove keys that are known to have been added.
+func (c *CountingBloom[K]) Remove(key K) bool {
+	if !c.Contains(key) {
+		return false
+	}
+	for i := range locations(key, c.k, c.size()) {
+		if v := c.get(i); v < maxCount {
+			c.set(i, v-1)
+		}
+	}
+	c.count--
+	return true
+}
+
+// Len returns the number of keys added minus the number removed, including
+// the keys of merged filters
+func (c *CountingBloom[K]) Len() int {
+	return c.count
+}
+
+// Hashes returns the number of counters each key increments
+func (c *CountingBloom[K]) Hashes() int {
+	return c.k
+}
+
+// Counters returns the number of counters in the filter
+func (c *CountingBloom[K]) Counters() int {
+	return int(c.size())
+}
+
+// Merge adds every key of other to c by adding the counters, which stick
+// at 15 on overflow. Both filters must have the same number of counters and
+// hashes.
+func (c *CountingBloom[K]) Merge(other *CountingBloom[K]) error {
+	if len(c.counters) != len(other.counters) || c.k != other.k {
+		return ErrIncompatible
+	}
+	for i := range c.size() {
+		c.set(i, min(c.get(i)+other.get(i), maxCount))
+	}
+	c.count += other.count
+	return nil
+}
+
+const countingMagic = "CBF1"
+
+// MarshalBinary encodes the filter as the magic string "CBF1", the number
+// of hashes, the number of keys and the packed counters
+func (c *CountingBloom[K]) MarshalBinary() ([]byte, error) {
+	return append(header(countingMagic, uint64(c.k), uint64(c.count)), c.counters...), nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary
+func (c *CountingBloom[K]) UnmarshalBinary(data []byte) error {
+	params, data, err := parseHeader(data, countingMagic, 2)
+	if err != nil {
+		return err
+	}
+	if params[0] == 0 || params[0] > math.MaxInt32 || len(data) == 0 {
+		return ErrCorrupt
+	}
+	c.counters, c.k, c.count = append([]byte(nil), data...), int(params[0]), int(params[1])
+	return nil
+}
@@ -0,0 +1,260 @@
+package filter
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+const (
+	// bucketSize is the number of fingerprints in a cuckoo filter bucket
+	bucketSize = 4
+	// maxKicks is how many fingerprints Add relocates before it gives up
+	maxKicks = 500
+	// maxCuckooLoad is the load factor NewCuckoo sizes the filter for
+	maxCuckooLoad = 0.95
+)
+
+// bucket holds up to bucketSize fingerprints; zero marks an empty slot
+type bucket [bucketSize]uint16
+
+// Cuckoo is a cuckoo filter (Fan et al., 2014). It stores a 16-bit
+// fingerprint of each key in one of two buckets of four slots. The second
+// bucket is the first one XORed with a hash of the fingerprint, so a
+// fingerprint can be moved to its other bucket without knowing the key.
+// When both buckets are full, Add evicts a random fingerprint to its other
+// bucket, which may evict another, as in cuckoo hashing. Unlike a Bloom
+// filter it supports deletion, and with these parameters its false
+// positive rate is about 0.01%.
+type Cuckoo[K ~string | ~[]byte] struct {
+	buckets []bucket
+	count   int
+	// victim holds a fingerprint that could not be placed after maxKicks
+	// relocations, so no key is lost; while it is set the filter is full
+	victim      uint16
+	victimIndex uint64
+	rng         uint64 // xorshift state for choosing which slot to evict
+}
+
+var _ Filter[string] = (*Cuckoo[string])(nil)
+
+// NewCuckoo creates an empty cuckoo filter with room for at least n keys.
+// The number of buckets is a power of two. It panics if n is not positive.
+func NewCuckoo[K ~string | ~[]byte](n int) *Cuckoo[K] {
+	if n <= 0 {
+		panic("filter: expected number of keys must be positive")
+	}
+	buckets := uint64(float64(n)/(bucketSize*maxCuckooLoad)) + 1
+	buckets = 1 << bits.Len64(buckets-1)
+	return &Cuckoo[K]{buckets: make([]bucket, buckets), rng: 1}
+}
+
+// fingerprint returns a key's nonzero fingerprint and its first bucket
+func (c *Cuckoo[K]) fingerprint(key K) (uint16, uint64) {
+	h1, h2 := hash(key)
+	fp := uint16(h2)
+	if fp == 0 {
+		fp = 1
+	}
+	return fp, h1 & c.mask()
+}
+
+func (c *Cuckoo[K]) mask() uint64 {
+	return uint64(len(c.buckets)) - 1
+}
+
+// alternate returns the other bucket of a fingerprint stored in bucket i.
+// Applying it twice gives back i.
+func (c *Cuckoo[K]) alternate(i uint64, fp uint16) uint64 {
+	return (i ^ mix(uint64(fp))) & c.mask()
+}
+
+// insert stores a fingerprint in bucket i if it has an empty slot
+func (c *Cuckoo[K]) insert(i uint64, fp uint16) bool {
+	b := &c.buckets[i]
+	for j, slot := range b {
+		if slot == 0 {
+			b[j] = fp
+			return true
+		}
+	}
+	return false
+}
+
+// place stores a fingerprint in bucket i or its alternate, relocating
+// other fingerprints if both are full. A fingerprint that is still left
+// over becomes the victim.
+func (c *Cuckoo[K]) place(i uint64, fp uint16) {
+	if c.insert(i, fp) || c.insert(c.alternate(i, fp), fp) {
+		return
+	}
+	if c.random()%2 == 0 {
+		i = c.alternate(i, fp)
+	}
+	for range maxKicks {
+		j := c.random() % bucketSize
+		fp, c.buckets[i][j] = c.buckets[i][j], fp
+		i = c.alternate(i, fp)
+		if c.insert(i, fp) {
+			return
+		}
+	}
+	c.victim, c.victimIndex = fp, i
+}
+
+// random returns the next value of a xorshift64 generator
+func (c *Cuckoo[K]) random() uint64 {
+	c.rng ^= c.rng << 13
+	c.rng ^= c.rng >> 7
+	c.rng ^= c.rng << 17
+	return c.rng
+}
+
+// Add inserts a key. It returns ErrFull if the filter had no room; the
+// key can still be added after other keys are deleted.
+func (c *Cuckoo[K]) Add(key K) error {
+	if c.victim != 0 {
+		return ErrFull
+	}
+	fp, i := c.fingerprint(key)
+	c.place(i, fp)
+	c.count++
+	return nil
+}
+
+// Contains reports whether a key may be present. False means the key is
+// definitely not present.
+func (c *Cuckoo[K]) Contains(key K) bool {
+	fp, i1 := c.fingerprint(key)
+	i2 := c.alternate(i1, fp)
+	if c.victim == fp && (c.victimIndex == i1 || c.victimIndex == i2) {
+		return true
+	}
+	for _, i := range [2]uint64{i1, i2} {
+		for _, slot := range c.buckets[i] {
+			if slot == fp {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Delete removes one copy of a key's fingerprint and reports whether it
+// was found. As with CountingBloom, deleting a key that was never added
+// may remove the fingerprint of another key.
+func (c *Cuckoo[K]) Delete(key K) bool {
+	fp, i1 := c.fingerprint(key)
+	i2 := c.alternate(i1, fp)
+	if c.victim == fp && (c.victimIndex == i1 || c.victimIndex == i2) {
+		c.victim = 0
+		c.count--
+		return true
+	}
+	for _, i := range [2]uint64{i1, i2} {
+		for j, slot := range c.buckets[i] {
+			if slot == fp {
+				c.buckets[i][j] = 0
+				c.count--
+				c.replaceVictim()
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// replaceVictim tries to place the victim now that a slot has been freed
+func (c *Cuckoo[K]) replaceVictim() {
+	if c.victim != 0 {
+		fp, i := c.victim, c.victimIndex
+		c.victim = 0
+		c.place(i, fp)
+	}
+}
+
+// Len returns the number of keys in the filter
+func (c *Cuckoo[K]) Len() int {
+	return c.count
+}
+
+// Capacity returns the number of fingerprint slots
+func (c *Cuckoo[K]) Capacity() int {
+	return len(c.buckets) * bucketSize
+}
+
+// LoadFactor returns the fraction of slots in use
+func (c *Cuckoo[K]) LoadFactor() float64 {
+	return float64(c.count) / float64(c.Capacity())
+}
+
+// Merge adds every key of other to c by moving its fingerprints into c.
+// Both filters must have the same number of buckets. If c fills up, Merge
+// returns ErrFull and c holds only part of other.
+func (c *Cuckoo[K]) Merge(other *Cuckoo[K]) error {
+	if len(c.buckets) != len(other.buckets) {
+		return ErrIncompatible
+	}
+	add := func(i uint64, fp uint16) error {
+		if c.victim != 0 {
+			return ErrFull
+		}
+		c.place(i, fp)
+		c.count++
+		return nil
+	}
+	for i, b := range other.buckets {
+		for _, fp := range b {
+			if fp == 0 {
+				continue
+			}
+			if err := add(uint64(i), fp); err != nil {
+				return err
+			}
+		}
+	}
+	if other.victim != 0 {
+		return add(other.victimIndex, other.victim)
+	}
+	return nil
+}
+
+const cuckooMagic = "CKF1"
+
+// MarshalBinary encodes the filter as the magic string "CKF1", the number
+// of keys, the victim and its bucket, and the fingerprints as little-endian
+// 16-bit values
+func (c *Cuckoo[K]) MarshalBinary() ([]byte, error) {
+	data := header(cuckooMagic, uint64(c.count), uint64(c.victim), c.victimIndex)
+	for _, b := range c.buckets {
+		for _, fp := range b {
+			data = binary.LittleEndian.AppendUint16(data, fp)
+		}
+	}
+	return data, nil
+}
+
+// UnmarshalBinary replaces the filter with one encoded by MarshalBinary
+func (c *Cuckoo[K]) UnmarshalBinary(data []byte) error {
+	params, data, err := parseHeader(data, cuckooMagic, 3)
+	if err != nil {
+		return err
+	}
+	n := uint64(len(data) / (2 * bucketSize))
+	if n == 0 || n&(n-1) != 0 || uint64(len(data)) != n*2*bucketSize ||
+		params[1] > 0xffff || params[2] >= n {
+		return ErrCorrupt
+	}
+	buckets := make([]bucket, n)
+	for i := range buckets {
+		for j := range bucketSize {
+			buckets[i][j] = binary.LittleEndian.Uint16(data)
+			data = data[2:]
+		}
+	}
+	c.buckets, c.count = buckets, int(params[0])
+	c.victim, c.victimIndex = uint16(params[1]), params[2]
+	if c.rng == 0 {
+		c.rng = 1
+	}
+	return nil
+}
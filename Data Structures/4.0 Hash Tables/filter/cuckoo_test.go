@@ -0,0 +1,156 @@
+package filter
+
+import (
+	"errors"
+	"testing"
+)
+
+// checkCuckoo verifies that every stored fingerprint can reach its other
+// bucket and that Len matches the number of stored fingerprints
+func checkCuckoo[K ~string | ~[]byte](t *testing.T, c *Cuckoo[K]) {
+	t.Helper()
+	count := 0
+	for i, b := range c.buckets {
+		for _, fp := range b {
+			if fp == 0 {
+				continue
+			}
+			count++
+			if back := c.alternate(c.alternate(uint64(i), fp), fp); back != uint64(i) {
+				t.Fatalf("fingerprint %#x in bucket %d has alternate path back to %d", fp, i, back)
+			}
+		}
+	}
+	if c.victim != 0 {
+		count++
+	}
+	if count != c.Len() {
+		t.Fatalf("found %d fingerprints; Len() = %d", count, c.Len())
+	}
+}
+
+func TestCuckoo(t *testing.T) {
+	const n = 10000
+	c := NewCuckoo[string](n)
+	if c.Capacity() < n || c.Capacity()&(c.Capacity()-1) != 0 {
+		t.Fatalf("Capacity() = %d for %d keys", c.Capacity(), n)
+	}
+	added := keys("key", n)
+	for _, key := range added {
+		if err := c.Add(key); err != nil {
+			t.Fatalf("Add(%q): %v at load factor %v", key, err, c.LoadFactor())
+		}
+	}
+	checkCuckoo(t, c)
+	for _, key := range added {
+		if !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false after Add", key)
+		}
+	}
+	if rate := falsePositives(c); rate > 0.001 {
+		t.Fatalf("false positive rate %v", rate)
+	}
+
+	for _, key := range added[:n/2] {
+		if !c.Delete(key) {
+			t.Fatalf("Delete(%q) = false", key)
+		}
+	}
+	checkCuckoo(t, c)
+	for _, key := range added[n/2:] {
+		if !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false after deleting other keys", key)
+		}
+	}
+	if c.Len() != n/2 {
+		t.Fatalf("Len() = %d; want %d", c.Len(), n/2)
+	}
+}
+
+// TestCuckooFull adds keys until the filter is full, which must not lose
+// any key that was already added
+func TestCuckooFull(t *testing.T) {
+	c := NewCuckoo[string](100)
+	var added []string
+	for _, key := range keys("key", 10*c.Capacity()) {
+		if err := c.Add(key); err != nil {
+			if !errors.Is(err, ErrFull) {
+				t.Fatalf("Add: %v", err)
+			}
+			break
+		}
+		added = append(added, key)
+	}
+	if c.LoadFactor() < 0.9 {
+		t.Fatalf("filter was full at load factor %v", c.LoadFactor())
+	}
+	checkCuckoo(t, c)
+	for _, key := range added {
+		if !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false in a full filter", key)
+		}
+	}
+
+	// Deleting keys makes room again
+	for _, key := range added[:10] {
+		c.Delete(key)
+	}
+	if err := c.Add("new"); err != nil {
+		t.Fatalf("Add after Delete: %v", err)
+	}
+	checkCuckoo(t, c)
+	for _, key := range append(added[10:], "new") {
+		if !c.Contains(key) {
+			t.Fatalf("Contains(%q) = false after deleting other keys", key)
+		}
+	}
+}
+
+func TestCuckooMergeAndMarshal(t *testing.T) {
+	a, b := NewCuckoo[string](1000), NewCuckoo[string](1000)
+	for _, key := range keys("a", 400) {
+		a.Add(key)
+	}
+	for _, key := range keys("b", 400) {
+		b.Add(key)
+	}
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	data, _ := a.MarshalBinary()
+	var restored Cuckoo[string]
+	if err := restored.UnmarshalBinary(data); err != nil {
+		t.Fatalf("UnmarshalBinary: %v", err)
+	}
+	checkCuckoo(t, &restored)
+	for _, key := range append(keys("a", 400), keys("b", 400)...) {
+		if !restored.Contains(key) {
+			t.Fatalf("merged and restored filter misses %q", key)
+		}
+	}
+	if restored.Len() != 800 {
+		t.Fatalf("Len() = %d; want 800", restored.Len())
+	}
+	if err := restored.Add("more"); err != nil || !restored.Contains("more") {
+		t.Fatalf("restored filter does not accept new keys: %v", err)
+	}
+
+	if err := a.Merge(NewCuckoo[string](100)); !errors.Is(err, ErrIncompatible) {
+		t.Fatalf("Merge of different sizes = %v; want ErrIncompatible", err)
+	}
+	// A full filter stops merging with ErrFull
+	full := NewCuckoo[string](1000)
+	for _, key := range keys("full", 10000) {
+		if full.Add(key) != nil {
+			break
+		}
+	}
+	if err := full.Merge(b); !errors.Is(err, ErrFull) {
+		t.Fatalf("Merge into a full filter = %v; want ErrFull", err)
+	}
+	for _, bad := range [][]byte{data[:28], data[:len(data)-2], append([]byte("BLM1"), data[4:]...)} {
+		if err := restored.UnmarshalBinary(bad); !errors.Is(err, ErrCorrupt) {
+			t.Fatalf("UnmarshalBinary of %d bytes = %v; want ErrCorrupt", len(bad), err)
+		}
+	}
+}
@@ -0,0 +1,82 @@
+// Package filter implements approximate membership filters: a Bloom filter,
+// a counting Bloom filter that supports deletion, and a cuckoo filter. A
+// filter answers "definitely not present" or "probably present" using a
+// small fraction of the memory of a hash table, which makes it a cheap
+// check before an expensive lookup.
+package filter
+
+import (
+	"encoding/binary"
+	"errors"
+	"math/bits"
+)
+
+var (
+	// ErrIncompatible is returned when merging filters of different sizes
+	ErrIncompatible = errors.New("filter: filters have different parameters")
+	// ErrCorrupt is returned when unmarshaling data that is not a valid filter
+	ErrCorrupt = errors.New("filter: corrupt data")
+	// ErrFull is returned when a cuckoo filter has no room for another key
+	ErrFull = errors.New("filter: cuckoo filter is full")
+)
+
+// Filter is the API shared by the filters in this package
+type Filter[K ~string | ~[]byte] interface {
+	Contains(key K) bool
+	Len() int
+	MarshalBinary() ([]byte, error)
+}
+
+// hash returns two 64-bit hashes of a key: FNV-1a followed by two different
+// splitmix64 finalizers. The hash is fixed rather than randomly seeded, so
+// serialized filters stay valid in other processes and filters built
+// separately can be merged.
+func hash[K ~string | ~[]byte](key K) (uint64, uint64) {
+	h := uint64(14695981039346656037)
+	for i := 0; i < len(key); i++ {
+		h ^= uint64(key[i])
+		h *= 1099511628211
+	}
+	return mix(h), mix(h ^ 0x9e3779b97f4a7c15)
+}
+
+// mix is the splitmix64 finalizer, which spreads every input bit over the
+// whole output
+func mix(h uint64) uint64 {
+	h ^= h >> 30
+	h *= 0xbf58476d1ce4e5b9
+	h ^= h >> 27
+	h *= 0x94d049bb133111eb
+	return h ^ h>>31
+}
+
+// reduce maps a hash onto [0, n) without a division
+func reduce(h, n uint64) uint64 {
+	hi, _ := bits.Mul64(h, n)
+	return hi
+}
+
+// header is the start of every serialized filter: a four-byte magic string
+// followed by little-endian parameters
+func header(magic string, params ...uint64) []byte {
+	b := []byte(magic)
+	for _, p := range params {
+		b = binary.LittleEndian.AppendUint64(b, p)
+	}
+	return b
+}
+
+// parseHeader checks the magic string and reads n parameters, returning
+// them with the rest of the data
+func parseHeader(data []byte, magic string, n int) ([]uint64, []byte, error) {
+	if len(data) < len(magic)+8*n || string(data[:len(magic)]) != magic {
+		return nil, nil, ErrCorrupt
+	}
+	data = data[len(magic):]
+	params := make([]uint64, n)
+	for i := range params {
+		params[i] = binary.LittleEndian.Uint64(data)
+		data = data[8:]
+	}
+	return params, data, nil
+}
@@ -0,0 +1,3 @@
+module go-mastery/hash-tables
+
+go 1.24.0
@@ -1,83 +1,14 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
 
-// KeyValuePair represents a key-value pair stored in the hash table
-type KeyValuePair struct {
-	Key   string
-	Value interface{}
-}
-
-// HashTable represents the hash table with an array of buckets
-type HashTable struct {
-	buckets [][]KeyValuePair
-	size    int
-}
-
-// NewHashTable creates a new hash table with the given number of buckets
-func NewHashTable(size int) *HashTable {
-	buckets := make([][]KeyValuePair, size)
-	return &HashTable{
-		buckets: buckets,
-		size:    size,
-	}
-}
-
-// hashFunction computes an index for a given key
-func (h *HashTable) hashFunction(key string) int {
-	hash := 0
-	for _, char := range key {
-		hash += int(char)
-	}
-	return hash % h.size
-}
-
-// Insert adds a key-value pair to the hash table
-func (h *HashTable) Insert(key string, value interface{}) {
-	index := h.hashFunction(key)
-	bucket := h.buckets[index]
-
-	// Check if the key already exists and update its value
-	for i, kv := range bucket {
-		if kv.Key == key {
-			h.buckets[index][i].Value = value
-			return
-		}
-	}
-
-	// If the key doesn't exist, append the new key-value pair
-	h.buckets[index] = append(bucket, KeyValuePair{Key: key, Value: value})
-}
-
-// Get retrieves the value associated with the given key
-func (h *HashTable) Get(key string) (interface{}, bool) {
-	index := h.hashFunction(key)
-	bucket := h.buckets[index]
-
-	for _, kv := range bucket {
-		if kv.Key == key {
-			return kv.Value, true
-		}
-	}
-	return nil, false
-}
-
-// Delete removes the key-value pair associated with the given key
-func (h *HashTable) Delete(key string) bool {
-	index := h.hashFunction(key)
-	bucket := h.buckets[index]
-
-	for i, kv := range bucket {
-		if kv.Key == key {
-			h.buckets[index] = append(bucket[:i], bucket[i+1:]...)
-			return true
-		}
-	}
-	return false
-}
+	"go-mastery/hash-tables/filter"
+	"go-mastery/hash-tables/hashtable"
+)
 
 func main() {
-	ht := NewHashTable(10)
+	ht := hashtable.New[string, string](10)
 
 	ht.Insert("apple", "fruit")
 	ht.Insert("carrot", "vegetable")
@@ -97,4 +28,58 @@ func main() {
 	} else {
 		fmt.Println("Not found")
 	}
+
+	// Keys and values are typed, and the table grows as it fills up
+	counts := hashtable.New[int, int](4)
+	for i := 0; i < 1000; i++ {
+		counts.Insert(i%100, i)
+	}
+	stats := counts.Stats()
+	fmt.Printf("%d entries in %d buckets, longest chain %d\n",
+		stats.Entries, stats.Buckets, stats.LongestChain) // Output: 100 entries in 256 buckets, longest chain ...
+
+	sum := 0
+	for key := range counts.Keys() {
+		sum += key
+	}
+	fmt.Println("Sum of keys:", sum) // Output: Sum of keys: 4950
+
+	// RobinHoodTable offers the same API with open addressing
+	var rh hashtable.Map[string, int] = hashtable.NewRobinHood[string, int](0)
+	rh.Insert("one", 1)
+	rh.Insert("two", 2)
+	rh.Delete("one")
+	two, _ := rh.Get("two")
+	fmt.Println("Robin Hood:", two, "len:", rh.Len()) // Output: Robin Hood: 2 len: 1
+
+	// A Bloom filter answers "definitely not present" without the lookup,
+	// so only keys that may exist reach the slow store
+	store := hashtable.New[string, int](0)
+	users := filter.NewBloom[string](1000, 0.01)
+	for i := range 1000 {
+		key := fmt.Sprintf("user-%d", i)
+		store.Insert(key, i)
+		users.Add(key)
+	}
+	lookups := 0
+	for i := range 2000 {
+		if key := fmt.Sprintf("user-%d", i); users.Contains(key) {
+			lookups++
+			store.Get(key)
+		}
+	}
+	fmt.Println("Lookups for 2000 keys:", lookups) // Output: Lookups for 2000 keys: 1006
+
+	// Filters serialize to bytes, so they can be saved and merged later
+	data, _ := users.MarshalBinary()
+	var restored filter.Bloom[string]
+	restored.UnmarshalBinary(data)
+	fmt.Println("Restored:", len(data), "bytes,", restored.Contains("user-7")) // Output: Restored: 1220 bytes, true
+
+	// A cuckoo filter also supports deletion
+	sessions := filter.NewCuckoo[string](100)
+	sessions.Add("alice")
+	sessions.Add("bob")
+	sessions.Delete("alice")
+	fmt.Println("Sessions:", sessions.Contains("alice"), sessions.Contains("bob")) // Output: Sessions: false true
 }
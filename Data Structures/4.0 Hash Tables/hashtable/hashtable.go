@@ -0,0 +1,188 @@
+// Package hashtable implements a generic hash table that resolves collisions
+// by separate chaining and grows as entries are added.
+package hashtable
+
+import (
+	"hash/maphash"
+	"iter"
+	"slices"
+)
+
+// DefaultMaxLoadFactor is the load factor above which New tables double their buckets
+const DefaultMaxLoadFactor = 0.75
+
+// Map is the API shared by the hash tables in this package
+type Map[K comparable, V any] interface {
+	Insert(key K, value V)
+	Get(key K) (V, bool)
+	Delete(key K) bool
+	Len() int
+}
+
+// KeyValuePair represents a key-value pair stored in the hash table
+type KeyValuePair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+// HashTable represents the hash table with a slice of buckets
+type HashTable[K comparable, V any] struct {
+	buckets       [][]KeyValuePair[K, V]
+	count         int
+	maxLoadFactor float64
+	seed          maphash.Seed
+}
+
+// New creates a hash table with the given initial number of buckets
+// that doubles once the load factor passes DefaultMaxLoadFactor
+func New[K comparable, V any](size int) *HashTable[K, V] {
+	return NewWithLoadFactor[K, V](size, DefaultMaxLoadFactor)
+}
+
+// NewWithLoadFactor creates a hash table with the given initial number of
+// buckets that doubles once the load factor passes maxLoadFactor.
+// It panics if maxLoadFactor is not positive.
+func NewWithLoadFactor[K comparable, V any](size int, maxLoadFactor float64) *HashTable[K, V] {
+	if !(maxLoadFactor > 0) {
+		panic("hashtable: max load factor must be positive")
+	}
+	return &HashTable[K, V]{
+		buckets:       make([][]KeyValuePair[K, V], max(size, 1)),
+		maxLoadFactor: maxLoadFactor,
+		seed:          maphash.MakeSeed(),
+	}
+}
+
+// hashFunction computes the bucket index for a given key
+func (h *HashTable[K, V]) hashFunction(key K) int {
+	return int(maphash.Comparable(h.seed, key) % uint64(len(h.buckets)))
+}
+
+// Insert adds a key-value pair to the hash table, replacing the value of an existing key
+func (h *HashTable[K, V]) Insert(key K, value V) {
+	index := h.hashFunction(key)
+	bucket := h.buckets[index]
+
+	// Check if the key already exists and update its value
+	for i, kv := range bucket {
+		if kv.Key == key {
+			bucket[i].Value = value
+			return
+		}
+	}
+
+	// If the key doesn't exist, append the new key-value pair
+	h.buckets[index] = append(bucket, KeyValuePair[K, V]{Key: key, Value: value})
+	h.count++
+	if h.LoadFactor() > h.maxLoadFactor {
+		h.rehash(2 * len(h.buckets))
+	}
+}
+
+// Get retrieves the value associated with the given key
+func (h *HashTable[K, V]) Get(key K) (V, bool) {
+	for _, kv := range h.buckets[h.hashFunction(key)] {
+		if kv.Key == key {
+			return kv.Value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes the key-value pair associated with the given key
+func (h *HashTable[K, V]) Delete(key K) bool {
+	index := h.hashFunction(key)
+	bucket := h.buckets[index]
+
+	for i, kv := range bucket {
+		if kv.Key == key {
+			h.buckets[index] = slices.Delete(bucket, i, i+1)
+			h.count--
+			return true
+		}
+	}
+	return false
+}
+
+// Len returns the number of key-value pairs in the hash table
+func (h *HashTable[K, V]) Len() int {
+	return h.count
+}
+
+// LoadFactor returns the average number of entries per bucket
+func (h *HashTable[K, V]) LoadFactor() float64 {
+	return float64(h.count) / float64(len(h.buckets))
+}
+
+// rehash moves every entry into a new slice of the given number of buckets
+func (h *HashTable[K, V]) rehash(size int) {
+	old := h.buckets
+	h.buckets = make([][]KeyValuePair[K, V], size)
+	for _, bucket := range old {
+		for _, kv := range bucket {
+			index := h.hashFunction(kv.Key)
+			h.buckets[index] = append(h.buckets[index], kv)
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in no particular order
+func (h *HashTable[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range h.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the key-value pairs in no particular order.
+// The table must not be modified during iteration.
+func (h *HashTable[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, bucket := range h.buckets {
+			for _, kv := range bucket {
+				if !yield(kv.Key, kv.Value) {
+					return
+				}
+			}
+		}
+	}
+}
+
+// Stats describes how the entries are spread over the buckets
+type Stats struct {
+	Entries      int     // number of key-value pairs
+	Buckets      int     // number of buckets
+	UsedBuckets  int     // buckets holding at least one entry
+	LongestChain int     // entries in the fullest bucket
+	LoadFactor   float64 // Entries / Buckets
+	AverageChain float64 // Entries / UsedBuckets
+	ChainLengths []int   // ChainLengths[n] is the number of buckets holding n entries
+}
+
+// Stats reports the current bucket occupancy
+func (h *HashTable[K, V]) Stats() Stats {
+	s := Stats{
+		Entries:    h.count,
+		Buckets:    len(h.buckets),
+		LoadFactor: h.LoadFactor(),
+	}
+	for _, bucket := range h.buckets {
+		n := len(bucket)
+		if n > 0 {
+			s.UsedBuckets++
+		}
+		s.LongestChain = max(s.LongestChain, n)
+	}
+	s.ChainLengths = make([]int, s.LongestChain+1)
+	for _, bucket := range h.buckets {
+		s.ChainLengths[len(bucket)]++
+	}
+	if s.UsedBuckets > 0 {
+		s.AverageChain = float64(s.Entries) / float64(s.UsedBuckets)
+	}
+	return s
+}
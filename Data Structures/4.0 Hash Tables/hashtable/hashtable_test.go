@@ -0,0 +1,103 @@
+package hashtable
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+)
+
+func TestInsertGetDelete(t *testing.T) {
+	ht := New[string, string](4)
+	ht.Insert("apple", "fruit")
+	ht.Insert("carrot", "vegetable")
+	ht.Insert("apple", "red fruit")
+
+	if v, ok := ht.Get("apple"); !ok || v != "red fruit" {
+		t.Fatalf("Get(apple) = %q, %t; want %q, true", v, ok, "red fruit")
+	}
+	if ht.Len() != 2 {
+		t.Fatalf("Len() = %d; want 2", ht.Len())
+	}
+	if !ht.Delete("carrot") || ht.Delete("carrot") {
+		t.Fatalf("Delete(carrot) should succeed exactly once")
+	}
+	if _, ok := ht.Get("carrot"); ok {
+		t.Fatalf("Get(carrot) found a deleted key")
+	}
+	if ht.Len() != 1 {
+		t.Fatalf("Len() = %d; want 1", ht.Len())
+	}
+}
+
+// TestMatchesBuiltinMap applies the same operations to a HashTable and a
+// built-in map and checks that they agree, across several rehashes.
+func TestMatchesBuiltinMap(t *testing.T) {
+	ht := New[int, int](1)
+	want := make(map[int]int)
+	for i := range 5000 {
+		key := (i * 7919) % 3001
+		if i%5 == 4 {
+			_, exists := want[key]
+			if ht.Delete(key) != exists {
+				t.Fatalf("Delete(%d) disagrees with map", key)
+			}
+			delete(want, key)
+			continue
+		}
+		ht.Insert(key, i)
+		want[key] = i
+	}
+
+	if ht.Len() != len(want) {
+		t.Fatalf("Len() = %d; want %d", ht.Len(), len(want))
+	}
+	if got := maps.Collect(ht.All()); !maps.Equal(got, want) {
+		t.Fatalf("All() does not match the built-in map")
+	}
+	keys := slices.Sorted(ht.Keys())
+	if !slices.Equal(keys, slices.Sorted(maps.Keys(want))) {
+		t.Fatalf("Keys() does not match the built-in map")
+	}
+	if lf := ht.LoadFactor(); lf > DefaultMaxLoadFactor {
+		t.Fatalf("LoadFactor() = %.2f; want at most %.2f", lf, DefaultMaxLoadFactor)
+	}
+}
+
+func TestAnagramsSpread(t *testing.T) {
+	ht := New[string, int](64)
+	for i, word := range []string{"listen", "silent", "enlist", "tinsel", "inlets"} {
+		ht.Insert(word, i)
+	}
+	// With the old rune-sum hash all five anagrams always shared one bucket.
+	if s := ht.Stats(); s.LongestChain == 5 {
+		t.Fatalf("all anagrams landed in one bucket: %+v", s)
+	}
+}
+
+func TestStats(t *testing.T) {
+	ht := NewWithLoadFactor[string, int](8, 2)
+	for i := range 16 {
+		ht.Insert(strconv.Itoa(i), i)
+	}
+	s := ht.Stats()
+	if s.Entries != 16 || s.Buckets != 8 || s.LoadFactor != 2 {
+		t.Fatalf("Stats() = %+v; want 16 entries in 8 buckets", s)
+	}
+	total, used := 0, 0
+	for n, buckets := range s.ChainLengths {
+		total += n * buckets
+		if n > 0 {
+			used += buckets
+		}
+	}
+	if total != s.Entries || used != s.UsedBuckets {
+		t.Fatalf("ChainLengths %v disagree with %+v", s.ChainLengths, s)
+	}
+
+	// One more entry pushes the load factor past 2 and doubles the buckets.
+	ht.Insert("16", 16)
+	if s := ht.Stats(); s.Buckets != 16 {
+		t.Fatalf("Buckets = %d after passing the load factor; want 16", s.Buckets)
+	}
+}
@@ -0,0 +1,193 @@
+package hashtable
+
+import (
+	"hash/maphash"
+	"iter"
+)
+
+// DefaultRobinHoodLoadFactor is the load factor above which New tables grow.
+// Robin Hood hashing keeps probe sequences short even when the table is quite full.
+const DefaultRobinHoodLoadFactor = 0.9
+
+// slot is one cell of a RobinHoodTable. dist is the distance from the key's
+// home slot plus one, so the zero value marks an empty slot.
+type slot[K comparable, V any] struct {
+	key   K
+	value V
+	dist  uint32
+}
+
+// RobinHoodTable is a hash table that stores its entries directly in one slice
+// (open addressing) and resolves collisions by linear probing.
+//
+// On insert, an entry that has probed further than the resident of a slot
+// takes that slot and the resident continues probing ("take from the rich,
+// give to the poor"). This keeps probe lengths close to the average and lets
+// lookups stop as soon as they pass the point where the key would have been
+// placed. Deletes shift the following entries back instead of leaving
+// tombstones.
+type RobinHoodTable[K comparable, V any] struct {
+	slots         []slot[K, V] // len(slots) is always a power of two
+	count         int
+	maxLoadFactor float64
+	seed          maphash.Seed
+}
+
+// NewRobinHood creates a Robin Hood table with room for at least size entries
+// that grows once the load factor passes DefaultRobinHoodLoadFactor
+func NewRobinHood[K comparable, V any](size int) *RobinHoodTable[K, V] {
+	return NewRobinHoodWithLoadFactor[K, V](size, DefaultRobinHoodLoadFactor)
+}
+
+// NewRobinHoodWithLoadFactor creates a Robin Hood table with room for at least
+// size entries that grows once the load factor passes maxLoadFactor.
+// It panics unless 0 < maxLoadFactor < 1.
+func NewRobinHoodWithLoadFactor[K comparable, V any](size int, maxLoadFactor float64) *RobinHoodTable[K, V] {
+	if !(maxLoadFactor > 0 && maxLoadFactor < 1) {
+		panic("hashtable: Robin Hood load factor must be between 0 and 1")
+	}
+	capacity := 8
+	for float64(size) > float64(capacity)*maxLoadFactor {
+		capacity *= 2
+	}
+	return &RobinHoodTable[K, V]{
+		slots:         make([]slot[K, V], capacity),
+		maxLoadFactor: maxLoadFactor,
+		seed:          maphash.MakeSeed(),
+	}
+}
+
+// home returns the slot a key is placed in when there are no collisions
+func (h *RobinHoodTable[K, V]) home(key K) int {
+	return int(maphash.Comparable(h.seed, key) & uint64(len(h.slots)-1))
+}
+
+// find returns the slot index holding key, or -1 if the key is absent
+func (h *RobinHoodTable[K, V]) find(key K) int {
+	mask := len(h.slots) - 1
+	for i, dist := h.home(key), uint32(1); ; i, dist = (i+1)&mask, dist+1 {
+		s := &h.slots[i]
+		// Empty, or a resident closer to home than we are: had the key been
+		// inserted, it would have taken this slot.
+		if s.dist < dist {
+			return -1
+		}
+		if s.dist == dist && s.key == key {
+			return i
+		}
+	}
+}
+
+// Insert adds a key-value pair to the table, replacing the value of an existing key
+func (h *RobinHoodTable[K, V]) Insert(key K, value V) {
+	if i := h.find(key); i >= 0 {
+		h.slots[i].value = value
+		return
+	}
+	if float64(h.count+1) > float64(len(h.slots))*h.maxLoadFactor {
+		h.resize(2 * len(h.slots))
+	}
+	h.place(key, value)
+}
+
+// place stores a key that is not in the table yet, moving richer residents
+// further along to make room
+func (h *RobinHoodTable[K, V]) place(key K, value V) {
+	mask := len(h.slots) - 1
+	entry := slot[K, V]{key: key, value: value, dist: 1}
+	for i := h.home(key); ; i = (i + 1) & mask {
+		s := &h.slots[i]
+		if s.dist == 0 {
+			*s = entry
+			h.count++
+			return
+		}
+		if s.dist < entry.dist {
+			// Take the slot from the richer resident, which carries on
+			// probing in our place
+			*s, entry = entry, *s
+		}
+		entry.dist++
+	}
+}
+
+// Get retrieves the value associated with the given key
+func (h *RobinHoodTable[K, V]) Get(key K) (V, bool) {
+	if i := h.find(key); i >= 0 {
+		return h.slots[i].value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes the key-value pair associated with the given key
+func (h *RobinHoodTable[K, V]) Delete(key K) bool {
+	i := h.find(key)
+	if i < 0 {
+		return false
+	}
+	// Backward-shift deletion: pull every following entry that is not in its
+	// home slot one step closer to home, until an empty or home slot.
+	mask := len(h.slots) - 1
+	for next := (i + 1) & mask; h.slots[next].dist > 1; i, next = next, (next+1)&mask {
+		h.slots[i] = h.slots[next]
+		h.slots[i].dist--
+	}
+	h.slots[i] = slot[K, V]{}
+	h.count--
+	return true
+}
+
+// Len returns the number of key-value pairs in the table
+func (h *RobinHoodTable[K, V]) Len() int {
+	return h.count
+}
+
+// LoadFactor returns the fraction of slots in use
+func (h *RobinHoodTable[K, V]) LoadFactor() float64 {
+	return float64(h.count) / float64(len(h.slots))
+}
+
+// resize reinserts every entry into a new slice of the given number of slots
+func (h *RobinHoodTable[K, V]) resize(capacity int) {
+	old := h.slots
+	h.slots = make([]slot[K, V], capacity)
+	h.count = 0
+	for _, s := range old {
+		if s.dist != 0 {
+			h.place(s.key, s.value)
+		}
+	}
+}
+
+// Keys returns an iterator over the keys in no particular order
+func (h *RobinHoodTable[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range h.All() {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+// All returns an iterator over the key-value pairs in no particular order.
+// The table must not be modified during iteration.
+func (h *RobinHoodTable[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for _, s := range h.slots {
+			if s.dist != 0 && !yield(s.key, s.value) {
+				return
+			}
+		}
+	}
+}
+
+// MaxProbeLength returns the longest distance any entry sits from its home slot
+func (h *RobinHoodTable[K, V]) MaxProbeLength() int {
+	longest := 0
+	for _, s := range h.slots {
+		longest = max(longest, int(s.dist)-1)
+	}
+	return longest
+}
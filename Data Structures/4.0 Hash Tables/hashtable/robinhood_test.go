@@ -0,0 +1,206 @@
+package hashtable
+
+import (
+	"fmt"
+	"maps"
+	"testing"
+)
+
+// Both tables must satisfy the shared API.
+var (
+	_ Map[string, int] = (*HashTable[string, int])(nil)
+	_ Map[string, int] = (*RobinHoodTable[string, int])(nil)
+)
+
+// checkRobinHood verifies the Robin Hood invariant: walking forward from any
+// entry, the next slot is either empty, a home slot, or exactly one further
+// from its own home.
+func checkRobinHood[K comparable, V any](t *testing.T, h *RobinHoodTable[K, V]) {
+	t.Helper()
+	mask := len(h.slots) - 1
+	count := 0
+	for i, s := range h.slots {
+		if s.dist == 0 {
+			continue
+		}
+		count++
+		if want := (h.home(s.key) + int(s.dist) - 1) & mask; want != i {
+			t.Fatalf("slot %d holds a key whose dist says it belongs in slot %d", i, want)
+		}
+		if next := h.slots[(i+1)&mask]; next.dist > s.dist+1 {
+			t.Fatalf("slot %d: dist jumps from %d to %d", i, s.dist, next.dist)
+		}
+	}
+	if count != h.Len() {
+		t.Fatalf("found %d entries; Len() = %d", count, h.Len())
+	}
+}
+
+func TestRobinHoodMatchesBuiltinMap(t *testing.T) {
+	h := NewRobinHood[int, int](0)
+	want := make(map[int]int)
+	for i := range 20000 {
+		key := (i * 7919) % 5003
+		if i%3 == 2 {
+			_, exists := want[key]
+			if h.Delete(key) != exists {
+				t.Fatalf("Delete(%d) disagrees with map", key)
+			}
+			delete(want, key)
+		} else {
+			h.Insert(key, i)
+			want[key] = i
+		}
+		if i%1000 == 0 {
+			checkRobinHood(t, h)
+		}
+	}
+	checkRobinHood(t, h)
+
+	for key, value := range want {
+		if got, ok := h.Get(key); !ok || got != value {
+			t.Fatalf("Get(%d) = %d, %t; want %d, true", key, got, ok, value)
+		}
+	}
+	for key := 5003; key < 6000; key++ {
+		if _, ok := h.Get(key); ok {
+			t.Fatalf("Get(%d) found a key that was never inserted", key)
+		}
+	}
+	if got := maps.Collect(h.All()); !maps.Equal(got, want) {
+		t.Fatalf("All() does not match the built-in map")
+	}
+	if lf := h.LoadFactor(); lf > DefaultRobinHoodLoadFactor {
+		t.Fatalf("LoadFactor() = %.2f; want at most %.2f", lf, DefaultRobinHoodLoadFactor)
+	}
+}
+
+func TestRobinHoodDeleteEverything(t *testing.T) {
+	h := NewRobinHoodWithLoadFactor[string, int](100, 0.95)
+	for i := range 100 {
+		h.Insert(fmt.Sprint("key", i), i)
+	}
+	for i := range 100 {
+		if !h.Delete(fmt.Sprint("key", i)) {
+			t.Fatalf("Delete(key%d) = false", i)
+		}
+		checkRobinHood(t, h)
+	}
+	if h.Len() != 0 || h.MaxProbeLength() != 0 {
+		t.Fatalf("table not empty after deleting every key")
+	}
+}
+
+func TestRobinHoodOverwriteDoesNotGrow(t *testing.T) {
+	h := NewRobinHood[int, int](0)
+	// 7 of 8 slots is as full as the default load factor allows
+	for key := range 7 {
+		h.Insert(key, key)
+	}
+	for key := range 7 {
+		h.Insert(key, -key)
+	}
+	if len(h.slots) != 8 || h.Len() != 7 {
+		t.Fatalf("after overwriting every key: %d slots, Len() = %d; want 8 and 7", len(h.slots), h.Len())
+	}
+	if got, _ := h.Get(3); got != -3 {
+		t.Fatalf("Get(3) = %d; want -3", got)
+	}
+	checkRobinHood(t, h)
+}
+
+// builtinMap adapts Go's map to the Map interface for the benchmarks.
+type builtinMap[K comparable, V any] map[K]V
+
+func (m builtinMap[K, V]) Insert(key K, value V) { m[key] = value }
+
+func (m builtinMap[K, V]) Get(key K) (V, bool) {
+	v, ok := m[key]
+	return v, ok
+}
+
+func (m builtinMap[K, V]) Delete(key K) bool {
+	_, ok := m[key]
+	delete(m, key)
+	return ok
+}
+
+func (m builtinMap[K, V]) Len() int { return len(m) }
+
+// tables lists the implementations compared by the benchmarks.
+var tables = []struct {
+	name string
+	new  func() Map[int, int]
+}{
+	{"Chaining", func() Map[int, int] { return New[int, int](8) }},
+	{"RobinHood", func() Map[int, int] { return NewRobinHood[int, int](8) }},
+	{"Builtin", func() Map[int, int] { return builtinMap[int, int]{} }},
+}
+
+// benchmarkSizes covers tables that fit in cache and tables that do not.
+var benchmarkSizes = []int{1 << 10, 1 << 16, 1 << 20}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, table := range tables {
+			b.Run(fmt.Sprintf("%s/%d", table.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m := table.new()
+					for key := range size {
+						m.Insert(key, key)
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGetHit(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, table := range tables {
+			m := table.new()
+			for key := range size {
+				m.Insert(key, key)
+			}
+			b.Run(fmt.Sprintf("%s/%d", table.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m.Get(i & (size - 1))
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkGetMiss(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, table := range tables {
+			m := table.new()
+			for key := range size {
+				m.Insert(key, key)
+			}
+			b.Run(fmt.Sprintf("%s/%d", table.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					m.Get(size + i)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDeleteInsert(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		for _, table := range tables {
+			m := table.new()
+			for key := range size {
+				m.Insert(key, key)
+			}
+			b.Run(fmt.Sprintf("%s/%d", table.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					key := i & (size - 1)
+					m.Delete(key)
+					m.Insert(key, i)
+				}
+			})
+		}
+	}
+}
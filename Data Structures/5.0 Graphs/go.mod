@@ -0,0 +1,3 @@
+module go-mastery/graphs
+
+go 1.23.4
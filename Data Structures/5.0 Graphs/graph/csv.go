@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteCSV writes the graph as an edge list with the header from,to,weight
+// and one row per edge. Vertices without any edges are written as rows
+// with a single field, so they are not lost. The rows do not say whether
+// the graph is directed. A row whose first field starts with # has that
+// field quoted, so ReadCSV does not take it for a comment.
+func WriteCSV[V comparable](w io.Writer, g Graph[V]) error {
+	cw := csv.NewWriter(w)
+	cw.Write([]string{"from", "to", "weight"})
+	edges := Edges(g)
+	touched := make(map[V]bool)
+	for _, e := range edges {
+		touched[e.From], touched[e.To] = true, true
+	}
+	for _, v := range g.Vertices() {
+		if !touched[v] {
+			if err := writeRecord(w, cw, []string{name(v)}); err != nil {
+				return err
+			}
+		}
+	}
+	for _, e := range edges {
+		if err := writeRecord(w, cw, []string{name(e.From), name(e.To), formatWeight(e.Weight)}); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeRecord writes one row through cw. encoding/csv only quotes fields
+// that CSV itself requires, so a first field starting with # is quoted
+// here and written straight to w, followed by the rest of the row.
+func writeRecord(w io.Writer, cw *csv.Writer, record []string) error {
+	if !strings.HasPrefix(record[0], "#") {
+		return cw.Write(record)
+	}
+	cw.Flush()
+	if err := cw.Error(); err != nil {
+		return err
+	}
+	field := `"` + strings.ReplaceAll(record[0], `"`, `""`) + `"`
+	if len(record) > 1 {
+		field += ","
+	}
+	if _, err := io.WriteString(w, field); err != nil {
+		return err
+	}
+	return cw.Write(record[1:])
+}
+
+// ReadCSV reads an edge list written by WriteCSV into a directed or
+// undirected graph. Each row is from,to,weight or from,to (weight 1), or a
+// single vertex. The header row is optional, blank lines are skipped, and
+// lines starting with an unquoted # are comments. Errors are *ParseError values that
+// give the line number.
+func ReadCSV(r io.Reader, directed bool) (*AdjacencyList[string], error) {
+	g := NewUndirected[string]()
+	if directed {
+		g = NewDirected[string]()
+	}
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+	cr.Comment = '#'
+	for first := true; ; first = false {
+		record, err := cr.Read()
+		if err == io.EOF {
+			return g, nil
+		}
+		if err != nil {
+			var csvErr *csv.ParseError
+			if errors.As(err, &csvErr) {
+				return nil, &ParseError{Format: "csv", Line: csvErr.Line, Err: csvErr.Err}
+			}
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+		if first && len(record) >= 2 && strings.EqualFold(record[0], "from") && strings.EqualFold(record[1], "to") {
+			continue
+		}
+		if err := addRecord(g, record); err != nil {
+			return nil, &ParseError{Format: "csv", Line: line, Err: err}
+		}
+	}
+}
+
+// addRecord adds the vertex or edge described by one CSV row
+func addRecord(g *AdjacencyList[string], record []string) error {
+	if len(record) > 3 {
+		return fmt.Errorf("expected at most 3 fields, got %d", len(record))
+	}
+	for i, field := range record[:min(len(record), 2)] {
+		if field == "" {
+			return fmt.Errorf("field %d: empty vertex name", i+1)
+		}
+	}
+	if len(record) == 1 {
+		g.AddVertex(record[0])
+		return nil
+	}
+	weight := 1.0
+	if len(record) == 3 {
+		var err error
+		if weight, err = parseWeight(record[2]); err != nil {
+			return err
+		}
+	}
+	g.AddWeightedEdge(record[0], record[1], weight)
+	return nil
+}
@@ -0,0 +1,391 @@
+package graph
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteDOT writes the graph in the Graphviz DOT language: every vertex in
+// order, then every edge with its weight as the weight attribute
+func WriteDOT[V comparable](w io.Writer, g Graph[V]) error {
+	bw := bufio.NewWriter(w)
+	kind, op := "graph", "--"
+	if g.Directed() {
+		kind, op = "digraph", "->"
+	}
+	fmt.Fprintf(bw, "%s {\n", kind)
+	for _, v := range g.Vertices() {
+		fmt.Fprintf(bw, "\t%s;\n", quoteDOT(name(v)))
+	}
+	for _, e := range Edges(g) {
+		fmt.Fprintf(bw, "\t%s %s %s [weight=%s];\n", quoteDOT(name(e.From)), op, quoteDOT(name(e.To)), dotWeight(e.Weight))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// dotWeight formats a weight as a DOT ID. DOT numerals have no exponent,
+// so weights such as 1e+300 are quoted.
+func dotWeight(w float64) string {
+	s := formatWeight(w)
+	if strings.ContainsAny(s, "eIN") {
+		return quoteDOT(s)
+	}
+	return s
+}
+
+// quoteDOT returns s as a DOT quoted string
+func quoteDOT(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// ReadDOT reads a graph written in the DOT language. It understands the
+// subset that describes plain graphs: node and edge statements (including
+// chains such as a -> b -> c), attribute lists, and comments. An edge's
+// weight attribute becomes its weight, and edge [weight=...] sets the
+// default for the edges after it; every other attribute is ignored.
+// Subgraphs and ports are not supported. Errors are *ParseError values
+// that give the line number.
+func ReadDOT(r io.Reader) (*AdjacencyList[string], error) {
+	src, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	p := &dotParser{lex: dotLexer{src: string(src), line: 1}}
+	p.next()
+	g, err := p.parse()
+	var parseErr *ParseError
+	if err != nil && !errors.As(err, &parseErr) {
+		// A syntax error, found at the current token
+		err = &ParseError{Format: "dot", Line: p.tok.line, Err: err}
+	}
+	return g, err
+}
+
+type dotKind int
+
+const (
+	dotEOF   dotKind = iota
+	dotID            // identifier, number or quoted string
+	dotPunct         // { } [ ] ; , = : and the edge operators -> and --
+)
+
+type dotToken struct {
+	kind   dotKind
+	text   string
+	quoted bool // a quoted ID is never a keyword
+	line   int
+}
+
+// is reports whether the token is the given punctuation
+func (t dotToken) is(punct string) bool {
+	return t.kind == dotPunct && t.text == punct
+}
+
+// keyword reports whether the token is the given keyword, which DOT
+// matches case-insensitively
+func (t dotToken) keyword(kw string) bool {
+	return t.kind == dotID && !t.quoted && strings.EqualFold(t.text, kw)
+}
+
+func (t dotToken) String() string {
+	if t.kind == dotEOF {
+		return "end of input"
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+type dotLexer struct {
+	src  string
+	pos  int
+	line int
+}
+
+// skipSpace skips white space, comments and # preprocessor lines
+func (l *dotLexer) skipSpace() error {
+	atLineStart := l.pos == 0
+	for l.pos < len(l.src) {
+		c := l.src[l.pos]
+		switch {
+		case c == '\n':
+			l.line++
+			l.pos++
+			atLineStart = true
+			continue
+		case c == ' ' || c == '\t' || c == '\r':
+			l.pos++
+			continue
+		case c == '#' && atLineStart, strings.HasPrefix(l.src[l.pos:], "//"):
+			for l.pos < len(l.src) && l.src[l.pos] != '\n' {
+				l.pos++
+			}
+		case strings.HasPrefix(l.src[l.pos:], "/*"):
+			end := strings.Index(l.src[l.pos+2:], "*/")
+			if end < 0 {
+				return errors.New("unterminated comment")
+			}
+			l.line += strings.Count(l.src[l.pos:l.pos+2+end], "\n")
+			l.pos += end + 4
+		default:
+			return nil
+		}
+		atLineStart = false
+	}
+	return nil
+}
+
+func isIDByte(c byte) bool {
+	return c == '_' || c >= 0x80 || 'a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9'
+}
+
+// next returns the next token
+func (l *dotLexer) next() (dotToken, error) {
+	if err := l.skipSpace(); err != nil {
+		return dotToken{line: l.line}, err
+	}
+	tok := dotToken{line: l.line}
+	if l.pos == len(l.src) {
+		return tok, nil
+	}
+	rest := l.src[l.pos:]
+	switch c := rest[0]; {
+	case strings.HasPrefix(rest, "->"), strings.HasPrefix(rest, "--"):
+		tok.kind, tok.text = dotPunct, rest[:2]
+		l.pos += 2
+	case strings.IndexByte("{}[];,=:", c) >= 0:
+		tok.kind, tok.text = dotPunct, rest[:1]
+		l.pos++
+	case c == '"':
+		var b strings.Builder
+		for i := 1; i < len(rest); i++ {
+			switch rest[i] {
+			case '"':
+				l.pos += i + 1
+				tok.kind, tok.text, tok.quoted = dotID, b.String(), true
+				return tok, nil
+			case '\\':
+				// \" and \\ are escapes; other backslashes are kept
+				if i+1 < len(rest) && (rest[i+1] == '"' || rest[i+1] == '\\') {
+					i++
+				} else if i+1 < len(rest) && rest[i+1] == '\n' {
+					// a backslash before a newline continues the string
+					i++
+					l.line++
+					continue
+				}
+			case '\n':
+				l.line++
+			}
+			b.WriteByte(rest[i])
+		}
+		return tok, errors.New("unterminated string")
+	case c == '<':
+		return tok, errors.New("HTML strings are not supported")
+	case isIDByte(c) || c == '-' || c == '.':
+		end := 1
+		for end < len(rest) && (isIDByte(rest[end]) || rest[end] == '.') {
+			end++
+		}
+		tok.kind, tok.text = dotID, rest[:end]
+		l.pos += end
+	default:
+		return tok, fmt.Errorf("unexpected character %q", c)
+	}
+	return tok, nil
+}
+
+type dotParser struct {
+	lex dotLexer
+	tok dotToken // current token
+	err error    // lexer error, reported when the token is used
+}
+
+// next moves to the next token
+func (p *dotParser) next() {
+	if p.err == nil {
+		p.tok, p.err = p.lex.next()
+	}
+}
+
+// expect consumes the given punctuation
+func (p *dotParser) expect(punct string) error {
+	if p.err != nil {
+		return p.err
+	}
+	if !p.tok.is(punct) {
+		return fmt.Errorf("expected %q, found %v", punct, p.tok)
+	}
+	p.next()
+	return nil
+}
+
+// id consumes an ID and returns its text
+func (p *dotParser) id() (string, error) {
+	if p.err != nil {
+		return "", p.err
+	}
+	if p.tok.kind != dotID {
+		return "", fmt.Errorf("expected an ID, found %v", p.tok)
+	}
+	text := p.tok.text
+	p.next()
+	return text, nil
+}
+
+// parse reads: [strict] (graph | digraph) [ID] { statements }
+func (p *dotParser) parse() (*AdjacencyList[string], error) {
+	if p.tok.keyword("strict") {
+		p.next()
+	}
+	var g *AdjacencyList[string]
+	op := "--"
+	switch {
+	case p.tok.keyword("graph"):
+		g = NewUndirected[string]()
+	case p.tok.keyword("digraph"):
+		g, op = NewDirected[string](), "->"
+	default:
+		if p.err != nil {
+			return nil, p.err
+		}
+		return nil, fmt.Errorf("expected graph or digraph, found %v", p.tok)
+	}
+	p.next()
+	if p.tok.kind == dotID {
+		p.next()
+	}
+	if err := p.expect("{"); err != nil {
+		return nil, err
+	}
+	defaultWeight := 1.0
+	for !p.tok.is("}") {
+		if p.err != nil {
+			return nil, p.err
+		}
+		if p.tok.kind == dotEOF {
+			return nil, errors.New(`expected "}", found end of input`)
+		}
+		if err := p.statement(g, op, &defaultWeight); err != nil {
+			return nil, err
+		}
+		if p.tok.is(";") || p.tok.is(",") {
+			p.next()
+		}
+	}
+	p.next()
+	if p.err != nil {
+		return nil, p.err
+	}
+	if p.tok.kind != dotEOF {
+		return nil, fmt.Errorf("unexpected %v after the graph", p.tok)
+	}
+	return g, nil
+}
+
+// statement reads one statement of the graph body
+func (p *dotParser) statement(g *AdjacencyList[string], op string, defaultWeight *float64) error {
+	switch {
+	case p.tok.keyword("subgraph") || p.tok.is("{"):
+		return errors.New("subgraphs are not supported")
+	case p.tok.keyword("graph") || p.tok.keyword("node"):
+		p.next()
+		_, err := p.attributes()
+		return err
+	case p.tok.keyword("edge"):
+		p.next()
+		attrs, err := p.attributes()
+		if err != nil {
+			return err
+		}
+		*defaultWeight, err = weightAttribute(attrs, *defaultWeight)
+		return err
+	}
+
+	from, err := p.id()
+	if err != nil {
+		return err
+	}
+	if p.tok.is("=") { // graph attribute: ID = ID
+		p.next()
+		_, err := p.id()
+		return err
+	}
+	if p.tok.is(":") {
+		return errors.New("ports are not supported")
+	}
+	// A node statement, or an edge chain a -> b -> c
+	vertices := []string{from}
+	for p.tok.is("->") || p.tok.is("--") {
+		if p.tok.text != op {
+			return fmt.Errorf("edge operator %s in a graph that uses %s", p.tok.text, op)
+		}
+		p.next()
+		to, err := p.id()
+		if err != nil {
+			return err
+		}
+		vertices = append(vertices, to)
+	}
+	attrs, err := p.attributes()
+	if err != nil {
+		return err
+	}
+	if len(vertices) == 1 {
+		g.AddVertex(from)
+		return nil
+	}
+	weight, err := weightAttribute(attrs, *defaultWeight)
+	if err != nil {
+		return err
+	}
+	for i := 1; i < len(vertices); i++ {
+		g.AddWeightedEdge(vertices[i-1], vertices[i], weight)
+	}
+	return nil
+}
+
+// weightAttribute returns the weight attribute, or def if there is none
+func weightAttribute(attrs map[string]dotToken, def float64) (float64, error) {
+	tok, ok := attrs["weight"]
+	if !ok {
+		return def, nil
+	}
+	weight, err := parseWeight(tok.text)
+	if err != nil {
+		return 0, &ParseError{Format: "dot", Line: tok.line, Err: err}
+	}
+	return weight, nil
+}
+
+// attributes reads any number of attribute lists [a=b, c=d; e] and returns
+// the value token of each attribute; an attribute without a value is set
+// to "true"
+func (p *dotParser) attributes() (map[string]dotToken, error) {
+	attrs := make(map[string]dotToken)
+	for p.err == nil && p.tok.is("[") {
+		p.next()
+		for !p.tok.is("]") {
+			key, err := p.id()
+			if err != nil {
+				return nil, err
+			}
+			value := dotToken{kind: dotID, text: "true", line: p.tok.line}
+			if p.tok.is("=") {
+				p.next()
+				value = p.tok
+				if _, err := p.id(); err != nil {
+					return nil, err
+				}
+			}
+			attrs[key] = value
+			if p.tok.is(",") || p.tok.is(";") {
+				p.next()
+			}
+		}
+		p.next()
+	}
+	return attrs, p.err
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// The graph formats in this package (DOT, GraphML, JSON and CSV) write any
+// Graph, naming each vertex with fmt.Sprint, and read into an
+// AdjacencyList[string]. Vertices whose names print the same are merged
+// when the graph is read back. Weights are written in the shortest form
+// that parses back to the same float64, so an AdjacencyList[string]
+// survives a round trip unchanged apart from the order of neighbours in
+// undirected graphs.
+
+// ParseError reports a problem found while reading a graph, with the line
+// it occurred on
+type ParseError struct {
+	Format string // "csv", "dot", "graphml" or "json"
+	Line   int
+	Err    error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("graph: %s line %d: %v", e.Format, e.Line, e.Err)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+// name returns the name a vertex is written under
+func name[V comparable](v V) string {
+	return fmt.Sprint(v)
+}
+
+// formatWeight writes a weight in the shortest form that reads back exactly
+func formatWeight(w float64) string {
+	return strconv.FormatFloat(w, 'g', -1, 64)
+}
+
+// parseWeight reads a weight written by formatWeight or by hand
+func parseWeight(s string) (float64, error) {
+	w, err := strconv.ParseFloat(s, 64)
+	if err != nil || math.IsNaN(w) {
+		return 0, fmt.Errorf("invalid weight %q", s)
+	}
+	return w, nil
+}
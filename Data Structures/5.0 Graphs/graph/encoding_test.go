@@ -0,0 +1,234 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// formats lists every writer and reader. CSV does not record whether the
+// graph is directed or where isolated vertices go in the vertex order.
+var formats = []struct {
+	name         string
+	write        func(io.Writer, Graph[string]) error
+	read         func(r io.Reader, directed bool) (*AdjacencyList[string], error)
+	keepsOrdered bool
+}{
+	{"csv", WriteCSV[string], ReadCSV, false},
+	{"dot", WriteDOT[string], func(r io.Reader, _ bool) (*AdjacencyList[string], error) { return ReadDOT(r) }, true},
+	{"graphml", WriteGraphML[string], func(r io.Reader, _ bool) (*AdjacencyList[string], error) { return ReadGraphML(r) }, true},
+	{"json", WriteJSON[string], func(r io.Reader, _ bool) (*AdjacencyList[string], error) { return ReadJSON(r) }, true},
+}
+
+// sampleGraphs returns graphs with awkward names and weights: quotes,
+// separators, markup, non-ASCII text, names that look like CSV comments, a
+// self-loop, isolated vertices, infinite weights and weights that are not
+// exact in decimal
+func sampleGraphs() []*AdjacencyList[string] {
+	var graphs []*AdjacencyList[string]
+	for _, g := range []*AdjacencyList[string]{NewDirected[string](), NewUndirected[string]()} {
+		g.AddWeightedEdge("api", "auth", 12)
+		g.AddWeightedEdge("api", `db "primary", us-east`, 0.1)
+		g.AddWeightedEdge("auth", "<cache> & co", -2.5e-7)
+		g.AddWeightedEdge("käse", "käse", 1)
+		g.AddVertex("lonely \\ vertex")
+		g.AddWeightedEdge("auth", `db "primary", us-east`, 1e300)
+		g.AddWeightedEdge("#tag", "api", 2)
+		g.AddWeightedEdge(`#"quoted", tag`, "#tag", 3)
+		g.AddVertex("#lonely")
+		g.AddWeightedEdge("api", "<cache> & co", math.Inf(1))
+		g.AddWeightedEdge(`#"quoted", tag`, "api", math.Inf(-1))
+		graphs = append(graphs, g)
+	}
+	return graphs
+}
+
+type edgeKey struct{ from, to string }
+
+// edgeSet returns the edges of g with their weights. Undirected edges are
+// stored under both orders of their endpoints.
+func edgeSet(g *AdjacencyList[string]) map[edgeKey]float64 {
+	edges := make(map[edgeKey]float64)
+	for _, e := range g.Edges() {
+		edges[edgeKey{e.From, e.To}] = e.Weight
+		if !g.Directed() {
+			edges[edgeKey{e.To, e.From}] = e.Weight
+		}
+	}
+	return edges
+}
+
+func sameGraph(t *testing.T, got, want *AdjacencyList[string], ordered bool) {
+	t.Helper()
+	if got.Directed() != want.Directed() {
+		t.Fatalf("Directed() = %t; want %t", got.Directed(), want.Directed())
+	}
+	gotVertices, wantVertices := got.Vertices(), want.Vertices()
+	if !ordered {
+		slices.Sort(gotVertices)
+		slices.Sort(wantVertices)
+	}
+	if !slices.Equal(gotVertices, wantVertices) {
+		t.Fatalf("Vertices() = %q; want %q", gotVertices, wantVertices)
+	}
+	if got.Size() != want.Size() || !maps.Equal(edgeSet(got), edgeSet(want)) {
+		t.Fatalf("Edges() = %v; want %v", got.Edges(), want.Edges())
+	}
+	if ordered && want.Directed() && !slices.Equal(got.Edges(), want.Edges()) {
+		t.Fatalf("Edges() = %v; want %v in order", got.Edges(), want.Edges())
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, f := range formats {
+		for _, want := range sampleGraphs() {
+			var buf bytes.Buffer
+			if err := f.write(&buf, want); err != nil {
+				t.Fatalf("%s: write: %v", f.name, err)
+			}
+			got, err := f.read(bytes.NewReader(buf.Bytes()), want.Directed())
+			if err != nil {
+				t.Fatalf("%s: read: %v\n%s", f.name, err, buf.String())
+			}
+			sameGraph(t, got, want, f.keepsOrdered)
+
+			// Writing the graph that was read gives the same output
+			var again bytes.Buffer
+			f.write(&again, got)
+			if want.Directed() && again.String() != buf.String() {
+				t.Fatalf("%s: second round trip differs:\n%s\n%s", f.name, buf.String(), again.String())
+			}
+		}
+	}
+}
+
+// TestWriteNonStringVertices writes an AdjacencyList[int], whose vertices
+// are named with fmt.Sprint
+func TestWriteNonStringVertices(t *testing.T) {
+	g := NewDirected[int]()
+	g.AddWeightedEdge(1, 2, 0.5)
+	var buf bytes.Buffer
+	WriteCSV(&buf, g)
+	if want := "from,to,weight\n1,2,0.5\n"; buf.String() != want {
+		t.Fatalf("WriteCSV = %q; want %q", buf.String(), want)
+	}
+	buf.Reset()
+	WriteDOT(&buf, g)
+	if want := "digraph {\n\t\"1\";\n\t\"2\";\n\t\"1\" -> \"2\" [weight=0.5];\n}\n"; buf.String() != want {
+		t.Fatalf("WriteDOT = %q; want %q", buf.String(), want)
+	}
+}
+
+func TestReadHandWritten(t *testing.T) {
+	want := NewDirected[string]()
+	want.AddWeightedEdge("a", "b", 2)
+	want.AddWeightedEdge("b", "c", 2)
+	want.AddWeightedEdge("c", "d", 1)
+	want.AddVertex("e")
+
+	csvInput := `# dependencies
+a, b, 2
+b,c,2
+
+c,d
+e
+`
+	dotInput := `/* dependencies */
+strict digraph deps {
+	rankdir=LR; node [shape=box]
+	// chains share their attributes
+	a -> b -> c [weight=2, color="red"]
+	EDGE [weight=1]
+	c -> d
+	"e"
+}
+`
+	graphMLInput := `<?xml version="1.0"?>
+<graphml>
+  <key id="d0" for="edge" attr.name="weight" attr.type="double"><default>2</default></key>
+  <key id="d1" for="node" attr.name="label" attr.type="string"/>
+  <graph id="G" edgedefault="directed">
+    <node id="a"><data key="d1">A</data></node>
+    <edge source="a" target="b"/>
+    <edge source="b" target="c"/>
+    <edge source="c" target="d"><data key="d0"> 1 </data></edge>
+    <node id="e"/>
+  </graph>
+</graphml>
+`
+	jsonInput := `{"directed": true, "adjacency": [
+	{"vertex": "a", "neighbors": [{"to": "b", "weight": 2}]},
+	{"vertex": "b", "neighbors": [{"to": "c", "weight": 2}]},
+	{"vertex": "c", "neighbors": [{"to": "d"}]},
+	{"vertex": "e"}
+]}`
+
+	g, err := ReadCSV(strings.NewReader(csvInput), true)
+	if err != nil {
+		t.Fatalf("ReadCSV: %v", err)
+	}
+	sameGraph(t, g, want, false)
+	for name, read := range map[string]func(io.Reader) (*AdjacencyList[string], error){
+		"dot": ReadDOT, "graphml": ReadGraphML, "json": ReadJSON,
+	} {
+		input := map[string]string{"dot": dotInput, "graphml": graphMLInput, "json": jsonInput}[name]
+		g, err := read(strings.NewReader(input))
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		sameGraph(t, g, want, false)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		format string
+		input  string
+		line   int
+		msg    string
+	}{
+		{"csv", "from,to,weight\na,b,1\nb,c,heavy\n", 3, `invalid weight "heavy"`},
+		{"csv", "a,b\n\n# comment\n,c\n", 4, "empty vertex name"},
+		{"csv", "a,b,1,extra\n", 1, "at most 3 fields"},
+		{"csv", "a,b\n\"c,d\n", 2, "extraneous or missing"},
+		{"dot", "digraph {\n a -> b\n b -- c\n}", 3, "edge operator --"},
+		{"dot", "graph {\n a -- b [weight=\n  NaN]\n}", 3, `invalid weight "NaN"`},
+		{"dot", "digraph {\n\n subgraph x { a }\n}", 3, "subgraphs"},
+		{"dot", "digraph {\n a -> \"b\n", 2, "unterminated string"},
+		{"dot", "digraph {\n a -> b\n", 3, "end of input"},
+		{"dot", "tree {}", 1, "expected graph or digraph"},
+		{"graphml", "<graphml>\n<graph>\n<edge source=\"a\" target=\"b\"><data key=\"weight\">x</data></edge>\n</graph></graphml>", 3, "invalid weight"},
+		{"graphml", "<graphml>\n<graph>\n<node/>\n</graph></graphml>", 3, "node without an id"},
+		{"graphml", "<graphml>\n<graph>\n</graphml>", 3, "element <graph> closed by </graphml>"},
+		{"graphml", "<graphml/>", 1, "no graph element"},
+		{"json", "{\n\"directed\": true,\n\"adjacency\": [\n{\"vertex\": 7}]}", 4, "cannot unmarshal number"},
+		{"json", "{\n\"adjacency\": [\n}", 3, "invalid character"},
+		{"json", "{\"adjacency\": [\n{\"vertex\": \"heavy\", \"neighbors\": [\n{\"to\": \"a\", \"weight\": \"heavy\"}]}]}", 3, `invalid weight "heavy"`},
+		{"json", "{\"adjacency\": [\n{\"vertex\": \"a\", \"neighbors\": [{\"to\": \"b\", \"weight\": 1},\n\n{\"to\": \"b\", \"weight\": true}]}]}", 4, "invalid weight true"},
+	} {
+		var err error
+		switch tc.format {
+		case "csv":
+			_, err = ReadCSV(strings.NewReader(tc.input), true)
+		case "dot":
+			_, err = ReadDOT(strings.NewReader(tc.input))
+		case "graphml":
+			_, err = ReadGraphML(strings.NewReader(tc.input))
+		case "json":
+			_, err = ReadJSON(strings.NewReader(tc.input))
+		}
+		var parseErr *ParseError
+		if !errors.As(err, &parseErr) {
+			t.Errorf("%s %q: error %v is not a *ParseError", tc.format, tc.input, err)
+			continue
+		}
+		if parseErr.Format != tc.format || parseErr.Line != tc.line || !strings.Contains(err.Error(), tc.msg) {
+			t.Errorf("%s %q: got %v (line %d); want line %d and %q", tc.format, tc.input, err, parseErr.Line, tc.line, tc.msg)
+		}
+	}
+}
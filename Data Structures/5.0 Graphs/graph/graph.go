@@ -0,0 +1,284 @@
+// Package graph defines the Graph interface that the graph algorithms in
+// this repository run against, and implements it with a generic adjacency
+// list that can be directed or undirected and carries a weight on every
+// edge, and with an adapter that exposes a 2D grid.
+//
+// Vertices and neighbours are always returned in the order they were added,
+// so programs that print or traverse a graph behave the same on every run.
+package graph
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+)
+
+// Graph is the read-only view of a weighted graph that algorithms need.
+// Implementations should return vertices and neighbours in a fixed order so
+// that algorithms give the same result on every run.
+type Graph[V comparable] interface {
+	// Directed reports whether the graph's edges have a direction
+	Directed() bool
+	// Vertices returns every vertex
+	Vertices() []V
+	// HasVertex reports whether the vertex is in the graph
+	HasVertex(v V) bool
+	// Adjacent yields each neighbour reachable over one outgoing edge with
+	// the weight of that edge. It yields nothing for unknown vertices.
+	Adjacent(v V) iter.Seq2[V, float64]
+}
+
+// Edges returns every edge of g, grouped by source vertex in the order of
+// Vertices. Undirected edges are returned once, from the endpoint that
+// comes first.
+func Edges[V comparable](g Graph[V]) []Edge[V] {
+	var edges []Edge[V]
+	directed := g.Directed()
+	seen := make(map[V]bool)
+	for _, from := range g.Vertices() {
+		seen[from] = true
+		for to, weight := range g.Adjacent(from) {
+			if !directed && seen[to] && to != from {
+				continue
+			}
+			edges = append(edges, Edge[V]{From: from, To: to, Weight: weight})
+		}
+	}
+	return edges
+}
+
+// Edge is a weighted connection between two vertices
+type Edge[V comparable] struct {
+	From   V
+	To     V
+	Weight float64
+}
+
+// adjacency holds the neighbours of one vertex in insertion order
+type adjacency[V comparable] struct {
+	order   []V
+	weights map[V]float64
+}
+
+func newAdjacency[V comparable]() *adjacency[V] {
+	return &adjacency[V]{weights: make(map[V]float64)}
+}
+
+// set adds or updates the edge to v and reports whether it is new
+func (a *adjacency[V]) set(v V, weight float64) bool {
+	_, exists := a.weights[v]
+	if !exists {
+		a.order = append(a.order, v)
+	}
+	a.weights[v] = weight
+	return !exists
+}
+
+// remove deletes the edge to v and reports whether it existed
+func (a *adjacency[V]) remove(v V) bool {
+	if _, exists := a.weights[v]; !exists {
+		return false
+	}
+	delete(a.weights, v)
+	a.order = slices.DeleteFunc(a.order, func(u V) bool { return u == v })
+	return true
+}
+
+// AdjacencyList is a Graph stored as adjacency lists
+type AdjacencyList[V comparable] struct {
+	directed bool
+	vertices []V // insertion order
+	out      map[V]*adjacency[V]
+	in       map[V]*adjacency[V] // same as out for undirected graphs
+	edges    int
+}
+
+// NewDirected creates an empty directed graph
+func NewDirected[V comparable]() *AdjacencyList[V] {
+	out := make(map[V]*adjacency[V])
+	return &AdjacencyList[V]{directed: true, out: out, in: make(map[V]*adjacency[V])}
+}
+
+// NewUndirected creates an empty undirected graph
+func NewUndirected[V comparable]() *AdjacencyList[V] {
+	out := make(map[V]*adjacency[V])
+	return &AdjacencyList[V]{directed: false, out: out, in: out}
+}
+
+// Directed reports whether the graph's edges have a direction
+func (g *AdjacencyList[V]) Directed() bool {
+	return g.directed
+}
+
+// AddVertex adds a vertex to the graph if it is not already present
+func (g *AdjacencyList[V]) AddVertex(vertex V) {
+	if _, exists := g.out[vertex]; exists {
+		return
+	}
+	g.vertices = append(g.vertices, vertex)
+	g.out[vertex] = newAdjacency[V]()
+	if g.directed {
+		g.in[vertex] = newAdjacency[V]()
+	}
+}
+
+// AddEdge adds an edge of weight 1 between two vertices, adding the vertices if needed
+func (g *AdjacencyList[V]) AddEdge(from, to V) {
+	g.AddWeightedEdge(from, to, 1)
+}
+
+// AddWeightedEdge adds an edge between two vertices, adding the vertices if needed.
+// Adding an edge that already exists updates its weight instead of duplicating it.
+func (g *AdjacencyList[V]) AddWeightedEdge(from, to V, weight float64) {
+	g.AddVertex(from)
+	g.AddVertex(to)
+	if g.out[from].set(to, weight) {
+		g.edges++
+	}
+	g.in[to].set(from, weight)
+}
+
+// RemoveEdge removes the edge between two vertices and reports whether it existed
+func (g *AdjacencyList[V]) RemoveEdge(from, to V) bool {
+	adj, exists := g.out[from]
+	if !exists || !adj.remove(to) {
+		return false
+	}
+	g.in[to].remove(from)
+	g.edges--
+	return true
+}
+
+// RemoveVertex removes a vertex and every edge touching it, and reports
+// whether the vertex existed. Each neighbour's list is scanned once, so it
+// takes O(V + the sum of the neighbours' degrees) time.
+func (g *AdjacencyList[V]) RemoveVertex(vertex V) bool {
+	out, exists := g.out[vertex]
+	if !exists {
+		return false
+	}
+	for _, to := range out.order {
+		if to != vertex {
+			g.in[to].remove(vertex)
+		}
+	}
+	removed := len(out.order)
+	if g.directed {
+		for _, from := range g.in[vertex].order {
+			if from != vertex {
+				g.out[from].remove(vertex)
+				removed++ // a self-loop was already counted with out
+			}
+		}
+	}
+	g.edges -= removed
+	delete(g.out, vertex)
+	delete(g.in, vertex)
+	g.vertices = slices.DeleteFunc(g.vertices, func(v V) bool { return v == vertex })
+	return true
+}
+
+// HasVertex reports whether the vertex is in the graph
+func (g *AdjacencyList[V]) HasVertex(vertex V) bool {
+	_, exists := g.out[vertex]
+	return exists
+}
+
+// HasEdge reports whether there is an edge from one vertex to another.
+// In an undirected graph the order of the vertices does not matter.
+func (g *AdjacencyList[V]) HasEdge(from, to V) bool {
+	_, exists := g.Weight(from, to)
+	return exists
+}
+
+// Weight returns the weight of the edge between two vertices
+func (g *AdjacencyList[V]) Weight(from, to V) (float64, bool) {
+	adj, exists := g.out[from]
+	if !exists {
+		return 0, false
+	}
+	weight, exists := adj.weights[to]
+	return weight, exists
+}
+
+// Neighbors returns the vertices reachable over one outgoing edge, in the
+// order the edges were added. It returns nil for unknown vertices.
+func (g *AdjacencyList[V]) Neighbors(vertex V) []V {
+	adj, exists := g.out[vertex]
+	if !exists {
+		return nil
+	}
+	return slices.Clone(adj.order)
+}
+
+// Adjacent yields each neighbour with the weight of the edge to it, in the
+// order the edges were added. The graph must not be changed while the
+// sequence is being iterated.
+func (g *AdjacencyList[V]) Adjacent(vertex V) iter.Seq2[V, float64] {
+	return func(yield func(V, float64) bool) {
+		adj, exists := g.out[vertex]
+		if !exists {
+			return
+		}
+		for _, to := range adj.order {
+			if !yield(to, adj.weights[to]) {
+				return
+			}
+		}
+	}
+}
+
+// Predecessors returns the vertices with an edge into the given vertex, in
+// the order the edges were added. For undirected graphs it equals Neighbors.
+func (g *AdjacencyList[V]) Predecessors(vertex V) []V {
+	adj, exists := g.in[vertex]
+	if !exists {
+		return nil
+	}
+	return slices.Clone(adj.order)
+}
+
+// OutDegree returns the number of edges leaving the vertex
+func (g *AdjacencyList[V]) OutDegree(vertex V) int {
+	if adj, exists := g.out[vertex]; exists {
+		return len(adj.order)
+	}
+	return 0
+}
+
+// InDegree returns the number of edges entering the vertex.
+// For undirected graphs it equals OutDegree.
+func (g *AdjacencyList[V]) InDegree(vertex V) int {
+	if adj, exists := g.in[vertex]; exists {
+		return len(adj.order)
+	}
+	return 0
+}
+
+// Vertices returns every vertex in the order it was added
+func (g *AdjacencyList[V]) Vertices() []V {
+	return slices.Clone(g.vertices)
+}
+
+// Edges returns every edge, grouped by source vertex in insertion order.
+// Undirected edges are returned once, from the endpoint that was added first.
+func (g *AdjacencyList[V]) Edges() []Edge[V] {
+	return Edges[V](g)
+}
+
+// Order returns the number of vertices
+func (g *AdjacencyList[V]) Order() int {
+	return len(g.vertices)
+}
+
+// Size returns the number of edges
+func (g *AdjacencyList[V]) Size() int {
+	return g.edges
+}
+
+// Display prints the adjacency list of the graph in insertion order
+func (g *AdjacencyList[V]) Display() {
+	for _, vertex := range g.vertices {
+		fmt.Printf("%v -> %v\n", vertex, g.out[vertex].order)
+	}
+}
@@ -0,0 +1,248 @@
+package graph
+
+import (
+	"maps"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"testing"
+)
+
+func TestUndirected(t *testing.T) {
+	g := NewUndirected[string]()
+	g.AddEdge("A", "B")
+	g.AddEdge("A", "C")
+	g.AddEdge("B", "D")
+	g.AddEdge("C", "D")
+	g.AddWeightedEdge("D", "E", 2.5)
+	g.AddEdge("B", "A") // same edge as A-B, must not be duplicated
+
+	if g.Order() != 5 || g.Size() != 5 {
+		t.Fatalf("Order() = %d, Size() = %d; want 5, 5", g.Order(), g.Size())
+	}
+	if !g.HasEdge("E", "D") || g.HasEdge("A", "D") {
+		t.Fatalf("HasEdge is not symmetric or reports a missing edge")
+	}
+	if w, _ := g.Weight("E", "D"); w != 2.5 {
+		t.Fatalf("Weight(E, D) = %v; want 2.5", w)
+	}
+	if got := g.Neighbors("D"); !slices.Equal(got, []string{"B", "C", "E"}) {
+		t.Fatalf("Neighbors(D) = %v", got)
+	}
+	if g.InDegree("D") != 3 || g.OutDegree("D") != 3 {
+		t.Fatalf("degree of D = %d/%d; want 3/3", g.InDegree("D"), g.OutDegree("D"))
+	}
+	wantEdges := []Edge[string]{
+		{"A", "B", 1}, {"A", "C", 1}, {"B", "D", 1}, {"C", "D", 1}, {"D", "E", 2.5},
+	}
+	if got := g.Edges(); !slices.Equal(got, wantEdges) {
+		t.Fatalf("Edges() = %v; want %v", got, wantEdges)
+	}
+
+	if !g.RemoveVertex("D") || g.RemoveVertex("D") {
+		t.Fatalf("RemoveVertex(D) should succeed exactly once")
+	}
+	if g.Size() != 2 || g.HasEdge("B", "D") || g.OutDegree("E") != 0 {
+		t.Fatalf("edges of D survived its removal: %v", g.Edges())
+	}
+	if got := g.Vertices(); !slices.Equal(got, []string{"A", "B", "C", "E"}) {
+		t.Fatalf("Vertices() = %v", got)
+	}
+}
+
+func TestDirected(t *testing.T) {
+	g := NewDirected[int]()
+	g.AddWeightedEdge(1, 2, 3)
+	g.AddWeightedEdge(1, 3, 4)
+	g.AddWeightedEdge(3, 2, 1)
+	g.AddWeightedEdge(1, 2, 7) // updates the weight
+	g.AddWeightedEdge(2, 2, 0) // self-loop
+
+	if g.Size() != 4 {
+		t.Fatalf("Size() = %d; want 4", g.Size())
+	}
+	if w, _ := g.Weight(1, 2); w != 7 {
+		t.Fatalf("Weight(1, 2) = %v; want 7", w)
+	}
+	if g.HasEdge(2, 1) {
+		t.Fatalf("HasEdge(2, 1) on a directed graph with only 1->2")
+	}
+	if g.InDegree(2) != 3 || g.OutDegree(2) != 1 || g.OutDegree(1) != 2 {
+		t.Fatalf("unexpected degrees: in(2)=%d out(2)=%d out(1)=%d", g.InDegree(2), g.OutDegree(2), g.OutDegree(1))
+	}
+	if got := g.Predecessors(2); !slices.Equal(got, []int{1, 3, 2}) {
+		t.Fatalf("Predecessors(2) = %v", got)
+	}
+
+	if !g.RemoveEdge(1, 2) || g.RemoveEdge(1, 2) {
+		t.Fatalf("RemoveEdge(1, 2) should succeed exactly once")
+	}
+	if g.InDegree(2) != 2 || g.Size() != 3 {
+		t.Fatalf("after RemoveEdge: in(2)=%d size=%d", g.InDegree(2), g.Size())
+	}
+
+	g.RemoveVertex(2)
+	if g.Size() != 1 || g.OutDegree(3) != 0 || g.HasVertex(2) {
+		t.Fatalf("after RemoveVertex(2): %v", g.Edges())
+	}
+	if g.Neighbors(2) != nil || g.InDegree(2) != 0 {
+		t.Fatalf("removed vertex still has neighbours")
+	}
+}
+
+func TestGrid(t *testing.T) {
+	g := NewGrid([][]int{
+		{0, 0, 1},
+		{1, 0, 0},
+		{0, 0},
+	})
+	var _ Graph[Cell] = g
+
+	want := []Cell{{0, 0}, {0, 1}, {1, 1}, {1, 2}, {2, 0}, {2, 1}}
+	if got := g.Vertices(); !slices.Equal(got, want) {
+		t.Fatalf("Vertices() = %v; want %v", got, want)
+	}
+	for _, c := range []Cell{{0, 2}, {2, 2}, {-1, 0}, {3, 0}} {
+		if g.HasVertex(c) {
+			t.Fatalf("HasVertex(%v) = true for a blocked or outside cell", c)
+		}
+	}
+	var got []Cell
+	for c, w := range g.Adjacent(Cell{1, 1}) {
+		if w != 1 {
+			t.Fatalf("weight to %v = %v; want 1", c, w)
+		}
+		got = append(got, c)
+	}
+	if want := []Cell{{0, 1}, {2, 1}, {1, 2}}; !slices.Equal(got, want) {
+		t.Fatalf("Adjacent(1,1) = %v; want %v", got, want)
+	}
+	if n := len(Edges[Cell](g)); n != 5 {
+		t.Fatalf("len(Edges) = %d; want 5", n)
+	}
+	for range g.Adjacent(Cell{0, 2}) {
+		t.Fatalf("a blocked cell has neighbours")
+	}
+}
+
+// TestInterface runs the same code against both implementations
+func TestInterface(t *testing.T) {
+	list := NewUndirected[Cell]()
+	grid := NewGrid([][]int{{0, 0}, {0, 1}})
+	for _, e := range Edges[Cell](grid) {
+		list.AddWeightedEdge(e.From, e.To, e.Weight)
+	}
+	for _, g := range []Graph[Cell]{list, grid} {
+		degree := 0
+		for range g.Adjacent(Cell{0, 0}) {
+			degree++
+		}
+		if degree != 2 || !g.HasVertex(Cell{1, 0}) || g.HasVertex(Cell{1, 1}) {
+			t.Fatalf("%T: degree(0,0) = %d", g, degree)
+		}
+	}
+}
+
+func TestWeightedGrid(t *testing.T) {
+	inf := math.Inf(1)
+	g := NewWeightedGrid([][]float64{
+		{1, 3, 1},
+		{1, inf, 1},
+		{2, 1, 1},
+	})
+	weights := func(c Cell) map[Cell]float64 {
+		got := make(map[Cell]float64)
+		for n, w := range g.Adjacent(c) {
+			got[n] = w
+		}
+		return got
+	}
+	want := map[Cell]float64{{0, 0}: 2, {0, 2}: 2}
+	if got := weights(Cell{0, 1}); !maps.Equal(got, want) {
+		t.Fatalf("four-way from (0,1) = %v; want %v", got, want)
+	}
+
+	g.SetMoves(EightWay)
+	// (2,1) to (1,0) is diagonal; (1,1) is blocked, so (2,1) to (1,2) and
+	// (1,0) would clip its corner
+	want = map[Cell]float64{{2, 0}: 1.5, {2, 2}: 1}
+	if got := weights(Cell{2, 1}); !maps.Equal(got, want) {
+		t.Fatalf("eight-way from (2,1) = %v; want %v", got, want)
+	}
+	open := NewGrid([][]int{{0, 0}, {0, 0}})
+	open.SetMoves(EightWay)
+	for n, w := range open.Adjacent(Cell{0, 0}) {
+		if n == (Cell{1, 1}) && w != math.Sqrt2 {
+			t.Fatalf("diagonal weight = %v; want √2", w)
+		}
+	}
+	if n := len(Edges[Cell](open)); n != 6 {
+		t.Fatalf("a 2x2 eight-way grid has %d edges; want 6", n)
+	}
+	if cost, ok := g.Cost(Cell{1, 1}); ok {
+		t.Fatalf("Cost of an obstacle = %v, true", cost)
+	}
+
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("NewWeightedGrid accepted a negative cost")
+		}
+	}()
+	NewWeightedGrid([][]float64{{1, -1}})
+}
+
+func TestPathTree(t *testing.T) {
+	tree := NewPathTree("a")
+	if !tree.Relax("a", "b", 4) || !tree.Relax("b", "c", 1) || !tree.Relax("a", "c", 2) {
+		t.Fatalf("Relax did not record a shorter path")
+	}
+	if tree.Relax("b", "c", 1) || tree.Relax("a", "c", 2) {
+		t.Fatalf("Relax accepted a path that is not shorter")
+	}
+	if got := tree.PathTo("c"); !slices.Equal(got, []string{"a", "c"}) || tree.Dist["c"] != 2 {
+		t.Fatalf("PathTo(c) = %v at distance %v; want [a c] at 2", got, tree.Dist["c"])
+	}
+	if got := tree.PathTo("a"); !slices.Equal(got, []string{"a"}) {
+		t.Fatalf("PathTo(a) = %v; want [a]", got)
+	}
+	if tree.Reached("d") || tree.PathTo("d") != nil {
+		t.Fatalf("unreached vertex d has a path")
+	}
+}
+
+// TestRemoveVertex compares RemoveVertex with removing each edge of the
+// vertex on its own, on graphs with self-loops and edges both ways
+func TestRemoveVertex(t *testing.T) {
+	seed := rand.Uint64()
+	rng := rand.New(rand.NewPCG(seed, seed))
+	for range 100 {
+		directed := rng.IntN(2) == 0
+		var g, want *AdjacencyList[int]
+		if directed {
+			g, want = NewDirected[int](), NewDirected[int]()
+		} else {
+			g, want = NewUndirected[int](), NewUndirected[int]()
+		}
+		for range 30 {
+			u, v, w := rng.IntN(8), rng.IntN(8), float64(rng.IntN(5))
+			g.AddWeightedEdge(u, v, w)
+			want.AddWeightedEdge(u, v, w)
+		}
+		victim := rng.IntN(8)
+		g.RemoveVertex(victim)
+		for _, to := range want.Neighbors(victim) {
+			want.RemoveEdge(victim, to)
+		}
+		for _, from := range want.Predecessors(victim) {
+			want.RemoveEdge(from, victim)
+		}
+		if g.Size() != want.Size() || !slices.Equal(g.Edges(), want.Edges()) {
+			t.Fatalf("seed %d: after RemoveVertex(%d): %v; want %v", seed, victim, g.Edges(), want.Edges())
+		}
+		for _, v := range g.Vertices() {
+			if !slices.Equal(g.Predecessors(v), want.Predecessors(v)) {
+				t.Fatalf("seed %d: Predecessors(%d) = %v; want %v", seed, v, g.Predecessors(v), want.Predecessors(v))
+			}
+		}
+	}
+}
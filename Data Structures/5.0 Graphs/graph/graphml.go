@@ -0,0 +1,161 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// WriteGraphML writes the graph as a GraphML document with a weight key
+// of type double on every edge
+func WriteGraphML[V comparable](w io.Writer, g Graph[V]) error {
+	bw := bufio.NewWriter(w)
+	edgeDefault := "undirected"
+	if g.Directed() {
+		edgeDefault = "directed"
+	}
+	fmt.Fprintln(bw, xml.Header+`<graphml xmlns="http://graphml.graphdrawing.org/xmlns">`)
+	fmt.Fprintln(bw, `  <key id="weight" for="edge" attr.name="weight" attr.type="double"><default>1</default></key>`)
+	fmt.Fprintf(bw, "  <graph edgedefault=%q>\n", edgeDefault)
+	for _, v := range g.Vertices() {
+		fmt.Fprintf(bw, "    <node id=\"%s\"/>\n", escapeXML(name(v)))
+	}
+	for _, e := range Edges(g) {
+		fmt.Fprintf(bw, "    <edge source=\"%s\" target=\"%s\"><data key=\"weight\">%s</data></edge>\n",
+			escapeXML(name(e.From)), escapeXML(name(e.To)), formatWeight(e.Weight))
+	}
+	fmt.Fprintln(bw, "  </graph>\n</graphml>")
+	return bw.Flush()
+}
+
+// escapeXML escapes s for use in XML text or a quoted attribute
+func escapeXML(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+// graphMLKey declares a data attribute, such as the weight of edges
+type graphMLKey struct {
+	ID      string  `xml:"id,attr"`
+	For     string  `xml:"for,attr"`
+	Name    string  `xml:"attr.name,attr"`
+	Default *string `xml:"default"`
+}
+
+type graphMLNode struct {
+	ID string `xml:"id,attr"`
+}
+
+type graphMLEdge struct {
+	Source string `xml:"source,attr"`
+	Target string `xml:"target,attr"`
+	Data   []struct {
+		Key   string `xml:"key,attr"`
+		Value string `xml:",chardata"`
+	} `xml:"data"`
+}
+
+// ReadGraphML reads the first graph of a GraphML document. The edge key
+// whose attr.name is "weight" supplies the weights, using its default for
+// edges without one; edges are weighted 1 if there is no such key. Other
+// keys, nested graphs and hyperedges are ignored. Errors are *ParseError
+// values that give the line number.
+func ReadGraphML(r io.Reader) (*AdjacencyList[string], error) {
+	dec := xml.NewDecoder(r)
+	var g *AdjacencyList[string]
+	weightKey, defaultWeight := "weight", 1.0
+	depth := 0 // nesting of graph elements; only depth 1 is read
+	for {
+		line, _ := dec.InputPos()
+		tok, err := dec.Token()
+		if err == io.EOF {
+			if g == nil {
+				return nil, &ParseError{Format: "graphml", Line: line, Err: errors.New("no graph element")}
+			}
+			return g, nil
+		}
+		if err != nil {
+			var syntaxErr *xml.SyntaxError
+			if errors.As(err, &syntaxErr) {
+				return nil, &ParseError{Format: "graphml", Line: syntaxErr.Line, Err: errors.New(syntaxErr.Msg)}
+			}
+			return nil, err
+		}
+		if end, ok := tok.(xml.EndElement); ok && end.Name.Local == "graph" {
+			if depth--; depth == 0 {
+				return g, nil // the rest of the document is ignored
+			}
+		}
+		start, ok := tok.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		line, _ = dec.InputPos()
+		fail := func(err error) (*AdjacencyList[string], error) {
+			return nil, &ParseError{Format: "graphml", Line: line, Err: err}
+		}
+
+		switch start.Name.Local {
+		case "key":
+			var key graphMLKey
+			if err := dec.DecodeElement(&key, &start); err != nil {
+				return fail(err)
+			}
+			if key.Name == "weight" && (key.For == "edge" || key.For == "all") {
+				weightKey = key.ID
+				if key.Default != nil {
+					if defaultWeight, err = parseWeight(strings.TrimSpace(*key.Default)); err != nil {
+						return fail(err)
+					}
+				}
+			}
+		case "graph":
+			depth++
+			if g != nil {
+				continue // a nested graph
+			}
+			g = NewUndirected[string]()
+			for _, attr := range start.Attr {
+				if attr.Name.Local == "edgedefault" && attr.Value == "directed" {
+					g = NewDirected[string]()
+				}
+			}
+		case "node":
+			var node graphMLNode
+			if err := dec.DecodeElement(&node, &start); err != nil {
+				return fail(err)
+			}
+			if depth != 1 {
+				continue
+			}
+			if node.ID == "" {
+				return fail(errors.New("node without an id"))
+			}
+			g.AddVertex(node.ID)
+		case "edge":
+			var edge graphMLEdge
+			if err := dec.DecodeElement(&edge, &start); err != nil {
+				return fail(err)
+			}
+			if depth != 1 {
+				continue
+			}
+			if edge.Source == "" || edge.Target == "" {
+				return fail(errors.New("edge without a source or target"))
+			}
+			weight := defaultWeight
+			for _, data := range edge.Data {
+				if data.Key == weightKey {
+					if weight, err = parseWeight(strings.TrimSpace(data.Value)); err != nil {
+						return fail(err)
+					}
+				}
+			}
+			g.AddWeightedEdge(edge.Source, edge.Target, weight)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"iter"
+	"math"
+)
+
+// Cell is the position of a square in a Grid
+type Cell struct {
+	Row, Col int
+}
+
+// Moves selects the cells that can be reached from a cell in one step
+type Moves int
+
+const (
+	// FourWay moves left, up, down and right
+	FourWay Moves = iota
+	// EightWay also moves diagonally. A diagonal step is √2 times as long
+	// and may not cut the corner of an obstacle: both cells it passes
+	// between must be open.
+	EightWay
+)
+
+// Grid exposes a 2D grid as an undirected graph. Every open cell is a
+// vertex with an edge to each open cell one step away. Each cell has a
+// cost, and a step weighs the mean cost of the two cells times the length
+// of the step, so crossing rough terrain costs more and the weight is the
+// same in both directions.
+type Grid struct {
+	cells [][]int     // from NewGrid
+	costs [][]float64 // from NewWeightedGrid
+	moves Moves
+}
+
+// NewGrid wraps cells, where 0 is an open cell of cost 1 and any other
+// value is an obstacle. Rows may have different lengths; cells beyond the
+// end of a row are obstacles. The grid is not copied, so later changes to
+// cells are seen by the graph. Moves are FourWay until SetMoves is called.
+func NewGrid(cells [][]int) *Grid {
+	return &Grid{cells: cells}
+}
+
+// NewWeightedGrid wraps costs, where each value is the cost of crossing
+// that cell and +Inf marks an obstacle. It panics if a cost is negative or
+// NaN. As with NewGrid, the grid is not copied.
+func NewWeightedGrid(costs [][]float64) *Grid {
+	for _, row := range costs {
+		for _, cost := range row {
+			if cost < 0 || math.IsNaN(cost) {
+				panic("graph: cell cost must not be negative or NaN")
+			}
+		}
+	}
+	return &Grid{costs: costs}
+}
+
+// SetMoves chooses between four-way and eight-way movement
+func (g *Grid) SetMoves(moves Moves) {
+	g.moves = moves
+}
+
+// Rows returns the number of rows
+func (g *Grid) Rows() int {
+	if g.costs != nil {
+		return len(g.costs)
+	}
+	return len(g.cells)
+}
+
+// width returns the length of row r
+func (g *Grid) width(r int) int {
+	if g.costs != nil {
+		return len(g.costs[r])
+	}
+	return len(g.cells[r])
+}
+
+// Cost returns the cost of crossing the cell, and false if the cell is an
+// obstacle or outside the grid
+func (g *Grid) Cost(c Cell) (float64, bool) {
+	if c.Row < 0 || c.Row >= g.Rows() || c.Col < 0 {
+		return 0, false
+	}
+	if g.costs != nil {
+		if row := g.costs[c.Row]; c.Col < len(row) && !math.IsInf(row[c.Col], 1) {
+			return row[c.Col], true
+		}
+		return 0, false
+	}
+	if row := g.cells[c.Row]; c.Col < len(row) && row[c.Col] == 0 {
+		return 1, true
+	}
+	return 0, false
+}
+
+// Open reports whether the cell is inside the grid and not an obstacle
+func (g *Grid) Open(c Cell) bool {
+	_, ok := g.Cost(c)
+	return ok
+}
+
+// Directed reports false: a move between two cells can always be reversed
+// at the same cost
+func (g *Grid) Directed() bool {
+	return false
+}
+
+// Vertices returns every open cell in row-major order
+func (g *Grid) Vertices() []Cell {
+	var cells []Cell
+	for r := range g.Rows() {
+		for c := range g.width(r) {
+			if g.Open(Cell{r, c}) {
+				cells = append(cells, Cell{r, c})
+			}
+		}
+	}
+	return cells
+}
+
+// HasVertex reports whether the cell is open
+func (g *Grid) HasVertex(c Cell) bool {
+	return g.Open(c)
+}
+
+// steps are the moves to the neighbours of a cell, in the order Adjacent
+// yields them: the four straight moves, then the four diagonals
+var steps = [...]Cell{{0, -1}, {-1, 0}, {1, 0}, {0, 1}, {-1, -1}, {-1, 1}, {1, -1}, {1, 1}}
+
+// Adjacent yields the open cells one step from c with the weight of the
+// step. It yields nothing if c itself is not open.
+func (g *Grid) Adjacent(c Cell) iter.Seq2[Cell, float64] {
+	return func(yield func(Cell, float64) bool) {
+		from, ok := g.Cost(c)
+		if !ok {
+			return
+		}
+		n := 4
+		if g.moves == EightWay {
+			n = 8
+		}
+		for i, s := range steps[:n] {
+			next := Cell{c.Row + s.Row, c.Col + s.Col}
+			to, ok := g.Cost(next)
+			if !ok {
+				continue
+			}
+			length := 1.0
+			if i >= 4 {
+				// A diagonal may not squeeze between two obstacles or
+				// clip the corner of one
+				if !g.Open(Cell{c.Row + s.Row, c.Col}) || !g.Open(Cell{c.Row, c.Col + s.Col}) {
+					continue
+				}
+				length = math.Sqrt2
+			}
+			if !yield(next, (from+to)/2*length) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,150 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"regexp"
+)
+
+// jsonGraph is the JSON adjacency format:
+//
+//	{
+//	  "directed": true,
+//	  "adjacency": [
+//	    {"vertex": "api", "neighbors": [{"to": "auth", "weight": 12}]},
+//	    {"vertex": "auth", "neighbors": [{"to": "vpn", "weight": "+Inf"}]}
+//	  ]
+//	}
+//
+// Vertices and neighbours are arrays rather than objects so that their
+// order is kept. JSON numbers cannot be infinite, so infinite weights are
+// written as the strings "+Inf" and "-Inf".
+type jsonGraph struct {
+	Directed  bool         `json:"directed"`
+	Adjacency []jsonVertex `json:"adjacency"`
+}
+
+type jsonVertex struct {
+	Vertex    string         `json:"vertex"`
+	Neighbors []jsonNeighbor `json:"neighbors"`
+}
+
+type jsonNeighbor struct {
+	To     string      `json:"to"`
+	Weight *jsonWeight `json:"weight,omitempty"` // 1 if omitted
+}
+
+// jsonWeight is a weight that is written as a number when finite and as a
+// string otherwise
+type jsonWeight float64
+
+func (w jsonWeight) MarshalJSON() ([]byte, error) {
+	if math.IsInf(float64(w), 0) || math.IsNaN(float64(w)) {
+		return json.Marshal(formatWeight(float64(w)))
+	}
+	return json.Marshal(float64(w))
+}
+
+func (w *jsonWeight) UnmarshalJSON(data []byte) error {
+	if len(data) == 0 || data[0] != '"' {
+		if err := json.Unmarshal(data, (*float64)(w)); err != nil {
+			return &weightError{raw: data, err: fmt.Errorf("invalid weight %s", data)}
+		}
+		return nil
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	parsed, err := parseWeight(s)
+	if err != nil {
+		return &weightError{raw: data, err: err}
+	}
+	*w = jsonWeight(parsed)
+	return nil
+}
+
+// weightError is returned for a weight string that does not parse. The
+// decoder does not say where such errors occur, so the raw value is kept
+// to find it.
+type weightError struct {
+	raw []byte
+	err error
+}
+
+func (e *weightError) Error() string { return e.err.Error() }
+
+// WriteJSON writes the graph in the JSON adjacency format: every vertex in
+// order with its outgoing edges. An undirected edge is listed under both of
+// its endpoints.
+func WriteJSON[V comparable](w io.Writer, g Graph[V]) error {
+	vertices := g.Vertices()
+	out := jsonGraph{Directed: g.Directed(), Adjacency: make([]jsonVertex, 0, len(vertices))}
+	for _, v := range vertices {
+		vertex := jsonVertex{Vertex: name(v), Neighbors: []jsonNeighbor{}}
+		for to, weight := range g.Adjacent(v) {
+			vertex.Neighbors = append(vertex.Neighbors, jsonNeighbor{To: name(to), Weight: (*jsonWeight)(&weight)})
+		}
+		out.Adjacency = append(out.Adjacency, vertex)
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(out)
+}
+
+// ReadJSON reads a graph in the JSON adjacency format written by WriteJSON.
+// Neighbours that are not listed as vertices are added. Errors are
+// *ParseError values that give the line number.
+func ReadJSON(r io.Reader) (*AdjacencyList[string], error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	var in jsonGraph
+	if err := json.Unmarshal(data, &in); err != nil {
+		// Both error types only know the byte offset, so count the lines
+		// before it
+		var offset int64
+		var syntaxErr *json.SyntaxError
+		var typeErr *json.UnmarshalTypeError
+		var weightErr *weightError
+		switch {
+		case errors.As(err, &syntaxErr):
+			offset = syntaxErr.Offset
+		case errors.As(err, &typeErr):
+			offset = typeErr.Offset
+		case errors.As(err, &weightErr):
+			// Decoding stops at the first bad weight, so the first
+			// weight with the same value is the one
+			pattern := `"weight"\s*:\s*` + regexp.QuoteMeta(string(weightErr.raw))
+			offset = int64(regexp.MustCompile(pattern).FindIndex(data)[0])
+			err = weightErr.err
+		default:
+			return nil, err
+		}
+		line := 1 + bytes.Count(data[:min(offset, int64(len(data)))], []byte("\n"))
+		return nil, &ParseError{Format: "json", Line: line, Err: err}
+	}
+
+	g := NewUndirected[string]()
+	if in.Directed {
+		g = NewDirected[string]()
+	}
+	for _, v := range in.Adjacency {
+		g.AddVertex(v.Vertex)
+	}
+	for _, v := range in.Adjacency {
+		for _, n := range v.Neighbors {
+			weight := 1.0
+			if n.Weight != nil {
+				weight = float64(*n.Weight)
+			}
+			g.AddWeightedEdge(v.Vertex, n.To, weight)
+		}
+	}
+	return g, nil
+}
@@ -0,0 +1,53 @@
+package graph
+
+import "slices"
+
+// PathTree holds the shortest paths from one source vertex, as found by
+// single-source algorithms such as Dijkstra and Bellman-Ford
+type PathTree[V comparable] struct {
+	Source V
+	// Dist maps every vertex reachable from Source to the length of a
+	// shortest path to it. Unreachable vertices are absent.
+	Dist map[V]float64
+	// Prev maps every reachable vertex except Source to the vertex before
+	// it on a shortest path
+	Prev map[V]V
+}
+
+// NewPathTree creates a tree in which only the source has been reached
+func NewPathTree[V comparable](source V) *PathTree[V] {
+	return &PathTree[V]{Source: source, Dist: map[V]float64{source: 0}, Prev: make(map[V]V)}
+}
+
+// Reached reports whether v can be reached from the source
+func (t *PathTree[V]) Reached(v V) bool {
+	_, ok := t.Dist[v]
+	return ok
+}
+
+// PathTo returns a shortest path from the source to v, both included, or
+// nil if v cannot be reached
+func (t *PathTree[V]) PathTo(v V) []V {
+	if !t.Reached(v) {
+		return nil
+	}
+	path := []V{v}
+	for v != t.Source {
+		v = t.Prev[v]
+		path = append(path, v)
+	}
+	slices.Reverse(path)
+	return path
+}
+
+// Relax lowers the distance of v if the edge from u, which must have been
+// reached, gives a shorter path, and reports whether it did
+func (t *PathTree[V]) Relax(u, v V, weight float64) bool {
+	d := t.Dist[u] + weight
+	if best, seen := t.Dist[v]; seen && d >= best {
+		return false
+	}
+	t.Dist[v] = d
+	t.Prev[v] = u
+	return true
+}
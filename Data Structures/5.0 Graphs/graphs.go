@@ -1,47 +1,68 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"log"
+	"os"
+	"strings"
 
-// Graph represents a graph using an adjacency list
-type Graph struct {
-	adjacencyList map[string][]string
-}
+	"go-mastery/graphs/graph"
+)
 
-// NewGraph creates a new graph
-func NewGraph() *Graph {
-	return &Graph{adjacencyList: make(map[string][]string)}
-}
+func main() {
+	g := graph.NewUndirected[string]()
 
-// AddVertex adds a vertex to the graph
-func (g *Graph) AddVertex(vertex string) {
-	if _, exists := g.adjacencyList[vertex]; !exists {
-		g.adjacencyList[vertex] = []string{}
-	}
-}
+	g.AddEdge("A", "B")
+	g.AddEdge("A", "C")
+	g.AddEdge("B", "D")
+	g.AddEdge("C", "D")
+	g.AddEdge("D", "E")
+	g.AddEdge("B", "A") // already present, so nothing changes
 
-// AddEdge adds an edge between two vertices
-func (g *Graph) AddEdge(vertex1, vertex2 string) {
-	g.AddVertex(vertex1)
-	g.AddVertex(vertex2)
-	g.adjacencyList[vertex1] = append(g.adjacencyList[vertex1], vertex2)
-	g.adjacencyList[vertex2] = append(g.adjacencyList[vertex2], vertex1)
-}
+	g.Display()
+	// Output:
+	// A -> [B C]
+	// B -> [A D]
+	// C -> [A D]
+	// D -> [B C E]
+	// E -> [D]
 
-// Display prints the adjacency list of the graph
-func (g *Graph) Display() {
-	for vertex, neighbors := range g.adjacencyList {
-		fmt.Printf("%s -> %v\n", vertex, neighbors)
-	}
-}
+	// A directed, weighted service dependency map
+	deps := graph.NewDirected[string]()
+	deps.AddWeightedEdge("api", "auth", 12)
+	deps.AddWeightedEdge("api", "db", 30)
+	deps.AddWeightedEdge("auth", "db", 8)
+	deps.AddWeightedEdge("worker", "db", 45)
 
-func main() {
-	graph := NewGraph()
+	fmt.Println("db in-degree:", deps.InDegree("db")) // Output: db in-degree: 3
 
-	graph.AddEdge("A", "B")
-	graph.AddEdge("A", "C")
-	graph.AddEdge("B", "D")
-	graph.AddEdge("C", "D")
-	graph.AddEdge("D", "E")
+	deps.RemoveEdge("api", "db")
+	deps.RemoveVertex("worker")
+	for _, e := range deps.Edges() {
+		fmt.Printf("%s -> %s (%.0fms)\n", e.From, e.To, e.Weight)
+	}
+	// Output:
+	// api -> auth (12ms)
+	// auth -> db (8ms)
+
+	// Graphs can be read from files instead of being built with AddEdge
+	// calls. Here the file is a string; use os.Open for a real one.
+	csv := "from,to,weight\napi,auth,12\napi,db,30\nauth,db,8\n"
+	loaded, err := graph.ReadCSV(strings.NewReader(csv), true)
+	if err != nil {
+		log.Fatal(err)
+	}
+	graph.WriteDOT(os.Stdout, loaded)
+	// Output:
+	// digraph {
+	// 	"api";
+	// 	"auth";
+	// 	"db";
+	// 	"api" -> "auth" [weight=12];
+	// 	"api" -> "db" [weight=30];
+	// 	"auth" -> "db" [weight=8];
+	// }
 
-	graph.Display()
+	_, err = graph.ReadCSV(strings.NewReader("api,auth,12\nauth,db,slow\n"), true)
+	fmt.Println(err) // Output: graph: csv line 2: invalid weight "slow"
 }
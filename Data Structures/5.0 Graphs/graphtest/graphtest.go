@@ -0,0 +1,64 @@
+// Package graphtest builds random graphs for the tests of the graph
+// algorithms. Each test gets its own generator, whose seed is logged when
+// the test fails, and can be rerun with that seed:
+//
+//	go test -run TestShortestPaths -graphtest.seed=12345
+package graphtest
+
+import (
+	"flag"
+	"math/rand/v2"
+	"testing"
+
+	"go-mastery/graphs/graph"
+)
+
+var seed = flag.Uint64("graphtest.seed", 0, "seed for the random graphs; 0 picks a new one per test")
+
+// Rand returns a generator for one test, seeded from -graphtest.seed or
+// at random, and logs the seed if the test fails
+func Rand(t testing.TB) *rand.Rand {
+	s := *seed
+	if s == 0 {
+		s = rand.Uint64()
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("random graphs: rerun with -graphtest.seed=%d", s)
+		}
+	})
+	return rand.New(rand.NewPCG(s, s))
+}
+
+// Random returns a graph on the vertices 0 to n-1 with the given number of
+// random edges, each weighted by weight
+func Random(rng *rand.Rand, directed bool, n, edges int, weight func(u, v int) float64) *graph.AdjacencyList[int] {
+	g := graph.NewUndirected[int]()
+	if directed {
+		g = graph.NewDirected[int]()
+	}
+	for v := range n {
+		g.AddVertex(v)
+	}
+	for range edges {
+		u, v := rng.IntN(n), rng.IntN(n)
+		g.AddWeightedEdge(u, v, weight(u, v))
+	}
+	return g
+}
+
+// Weights returns weights from lo to hi-1
+func Weights(rng *rand.Rand, lo, hi int) func(u, v int) float64 {
+	return func(int, int) float64 { return float64(lo + rng.IntN(hi-lo)) }
+}
+
+// ReducedCosts returns weights from 0 to 9 shifted by random potentials of
+// the n vertices, w + p[u] - p[v]. Some are negative, but every cycle
+// weighs the same as without the potentials, so none is negative.
+func ReducedCosts(rng *rand.Rand, n int) func(u, v int) float64 {
+	p := make([]float64, n)
+	for v := range p {
+		p[v] = float64(rng.IntN(20))
+	}
+	return func(u, v int) float64 { return float64(rng.IntN(10)) + p[u] - p[v] }
+}
@@ -0,0 +1,544 @@
+// Package btree implements a B+tree over byte-string keys whose nodes are
+// stored in fixed-size pages, either in memory or in a file. The binary page
+// layout is documented in page.go.
+package btree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"iter"
+	"os"
+	"slices"
+)
+
+var (
+	ErrCorrupt      = errors.New("btree: corrupt page")
+	ErrPageNotFound = errors.New("btree: page not found")
+	ErrTooLarge     = errors.New("btree: key and value are too large for a page")
+	ErrNotEmpty     = errors.New("btree: bulk load requires an empty tree")
+	ErrUnsorted     = errors.New("btree: bulk load keys are not strictly ascending")
+)
+
+const (
+	DefaultPageSize = 4096
+	DefaultOrder    = 64
+	// minEntrySize is the smallest per-entry budget New accepts
+	minEntrySize = 16
+)
+
+// Options configure a new tree. Zero fields take their defaults. When an
+// existing tree is opened, the page size and order stored in its meta page
+// are used and non-zero options must match them.
+type Options struct {
+	// PageSize is the size of every page in bytes
+	PageSize int
+	// Order is the maximum number of children of an internal node. Leaves
+	// hold at most Order-1 entries.
+	Order int
+}
+
+// BTree is a B+tree that maps byte-string keys to byte-string values in
+// ascending bytes.Compare order. All entries live in the leaves, which are
+// linked left to right so range scans read each leaf once; internal nodes
+// only hold separator keys. Every node is one page of the PageStore, and
+// nodes are read from the store on every access, so the tree can be far
+// larger than memory.
+//
+// Updates are written to the store immediately, but the meta page (root,
+// page count, number of keys) is only written by Sync and Close. A BTree is
+// not safe for concurrent use.
+type BTree struct {
+	store     PageStore
+	meta      meta
+	maxKeys   int // Order-1
+	minKeys   int // maxKeys/2; every node but the root holds at least this many keys
+	maxEntry  int // byte budget of one entry
+	metaDirty bool
+	err       error // first error hit by an iterator
+}
+
+// New opens the tree stored in store, or creates an empty tree if the store
+// has no meta page yet
+func New(store PageStore, opts Options) (*BTree, error) {
+	pageSize := orDefault(opts.PageSize, DefaultPageSize)
+	buf := make([]byte, max(pageSize, metaSize))
+	err := store.ReadPage(0, buf)
+	switch {
+	case errors.Is(err, ErrPageNotFound):
+		t, err := newTree(store, pageSize, orDefault(opts.Order, DefaultOrder))
+		if err != nil {
+			return nil, err
+		}
+		// The first leaf is the root of an empty tree
+		t.meta.pageCount = 1
+		root := &node{id: t.allocateFresh(), leaf: true}
+		t.meta.root = root.id
+		if err := t.write(root); err != nil {
+			return nil, err
+		}
+		return t, t.writeMeta()
+	case err != nil:
+		return nil, err
+	}
+
+	m, err := decodeMeta(buf)
+	if err != nil {
+		return nil, err
+	}
+	if opts.PageSize != 0 && opts.PageSize != m.pageSize || opts.Order != 0 && opts.Order != m.order {
+		return nil, fmt.Errorf("btree: store has page size %d and order %d, options ask for %d and %d",
+			m.pageSize, m.order, opts.PageSize, opts.Order)
+	}
+	t, err := newTree(store, m.pageSize, m.order)
+	if err != nil {
+		return nil, err
+	}
+	t.meta = *m
+	return t, nil
+}
+
+func orDefault(value, fallback int) int {
+	if value == 0 {
+		return fallback
+	}
+	return value
+}
+
+func newTree(store PageStore, pageSize, order int) (*BTree, error) {
+	if order < 3 {
+		return nil, fmt.Errorf("btree: order %d is less than 3", order)
+	}
+	if pageSize < metaSize {
+		return nil, fmt.Errorf("btree: page size %d is too small", pageSize)
+	}
+	maxKeys := order - 1
+	// An internal page needs 4 bytes for its first child on top of the header
+	maxEntry := min((pageSize-headerSize-4)/maxKeys, 1<<16-1)
+	if maxEntry < minEntrySize {
+		return nil, fmt.Errorf("btree: order %d is too large for page size %d", order, pageSize)
+	}
+	return &BTree{
+		store:    store,
+		meta:     meta{pageSize: pageSize, order: order},
+		maxKeys:  maxKeys,
+		minKeys:  maxKeys / 2,
+		maxEntry: maxEntry,
+	}, nil
+}
+
+// NewMemory creates an empty tree of the given order with default-sized
+// pages held in memory
+func NewMemory(order int) (*BTree, error) {
+	return New(NewMemoryStore(), Options{Order: order})
+}
+
+// Open opens the tree in the file at path, creating the file if needed
+func Open(path string, opts Options) (*BTree, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	pageSize := opts.PageSize
+	if pageSize == 0 {
+		// An existing file knows its page size; read it from the meta page
+		pageSize = DefaultPageSize
+		p := make([]byte, metaSize)
+		if _, err := f.ReadAt(p, 0); err == nil {
+			if m, err := decodeMeta(p); err == nil {
+				pageSize = m.pageSize
+			}
+		}
+	}
+	t, err := New(NewFileStore(f, pageSize), opts)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return t, nil
+}
+
+// Len returns the number of keys in the tree
+func (t *BTree) Len() int {
+	return int(t.meta.len)
+}
+
+// Order returns the maximum number of children of an internal node
+func (t *BTree) Order() int {
+	return t.meta.order
+}
+
+// MaxEntrySize returns the largest len(key)+len(value) that Put accepts
+func (t *BTree) MaxEntrySize() int {
+	return t.maxEntry - 6
+}
+
+// Sync writes the meta page and makes all changes durable
+func (t *BTree) Sync() error {
+	if t.metaDirty {
+		if err := t.writeMeta(); err != nil {
+			return err
+		}
+	}
+	return t.store.Sync()
+}
+
+// Close syncs the tree and closes its store
+func (t *BTree) Close() error {
+	return errors.Join(t.Sync(), t.store.Close())
+}
+
+// Err returns the first error that stopped an iterator returned by All or
+// Range early
+func (t *BTree) Err() error {
+	return t.err
+}
+
+func (t *BTree) writeMeta() error {
+	p := make([]byte, t.meta.pageSize)
+	t.meta.encode(p)
+	if err := t.store.WritePage(0, p); err != nil {
+		return err
+	}
+	t.metaDirty = false
+	return nil
+}
+
+func (t *BTree) read(id PageID) (*node, error) {
+	p := make([]byte, t.meta.pageSize)
+	if err := t.store.ReadPage(id, p); err != nil {
+		return nil, fmt.Errorf("btree: reading page %d: %w", id, err)
+	}
+	return decodeNode(id, p)
+}
+
+func (t *BTree) write(n *node) error {
+	p := make([]byte, t.meta.pageSize)
+	n.encode(p)
+	return t.store.WritePage(n.id, p)
+}
+
+// allocate returns a page for a new node, reusing freed pages first
+func (t *BTree) allocate() (PageID, error) {
+	t.metaDirty = true
+	if t.meta.freeHead == 0 {
+		return t.allocateFresh(), nil
+	}
+	id := t.meta.freeHead
+	p := make([]byte, t.meta.pageSize)
+	if err := t.store.ReadPage(id, p); err != nil {
+		return 0, err
+	}
+	if p[0] != freePage {
+		return 0, fmt.Errorf("%w: page %d on the free list is in use", ErrCorrupt, id)
+	}
+	t.meta.freeHead = PageID(binary.LittleEndian.Uint32(p[4:]))
+	return id, nil
+}
+
+// allocateFresh appends a page to the end of the store
+func (t *BTree) allocateFresh() PageID {
+	t.metaDirty = true
+	id := t.meta.pageCount
+	t.meta.pageCount++
+	return id
+}
+
+// free puts a page on the free list
+func (t *BTree) free(id PageID) error {
+	p := make([]byte, t.meta.pageSize)
+	p[0] = freePage
+	binary.LittleEndian.PutUint32(p[4:], uint32(t.meta.freeHead))
+	t.meta.freeHead = id
+	t.metaDirty = true
+	return t.store.WritePage(id, p)
+}
+
+// search returns the index of key in keys, or where it would be inserted
+func search(keys [][]byte, key []byte) (int, bool) {
+	return slices.BinarySearchFunc(keys, key, bytes.Compare)
+}
+
+// childIndex returns the child of an internal node that covers key
+func childIndex(n *node, key []byte) int {
+	i, found := search(n.keys, key)
+	if found {
+		i++ // keys equal to a separator live to its right
+	}
+	return i
+}
+
+// findLeaf descends from the root to the leaf that covers key
+func (t *BTree) findLeaf(key []byte) (*node, error) {
+	n, err := t.read(t.meta.root)
+	for err == nil && !n.leaf {
+		n, err = t.read(n.children[childIndex(n, key)])
+	}
+	return n, err
+}
+
+// Get returns the value stored for a key
+func (t *BTree) Get(key []byte) ([]byte, bool, error) {
+	leaf, err := t.findLeaf(key)
+	if err != nil {
+		return nil, false, err
+	}
+	if i, found := search(leaf.keys, key); found {
+		return leaf.values[i], true, nil
+	}
+	return nil, false, nil
+}
+
+// Put sets the value for a key, replacing any existing value. It returns
+// ErrTooLarge if len(key)+len(value) exceeds MaxEntrySize.
+func (t *BTree) Put(key, value []byte) error {
+	if len(key)+len(value) > t.MaxEntrySize() {
+		return ErrTooLarge
+	}
+	root, err := t.read(t.meta.root)
+	if err != nil {
+		return err
+	}
+	sep, right, err := t.insert(root, slices.Clone(key), slices.Clone(value))
+	if err != nil || right == 0 {
+		return err
+	}
+
+	// The root split: grow the tree by one level
+	id, err := t.allocate()
+	if err != nil {
+		return err
+	}
+	newRoot := &node{id: id, keys: [][]byte{sep}, children: []PageID{root.id, right}}
+	t.meta.root = id
+	return t.write(newRoot)
+}
+
+// insert adds the entry below n. If n had to split, it returns the first key
+// of the new right sibling and its page.
+func (t *BTree) insert(n *node, key, value []byte) ([]byte, PageID, error) {
+	if n.leaf {
+		i, found := search(n.keys, key)
+		if found {
+			n.values[i] = value
+			return nil, 0, t.write(n)
+		}
+		n.keys = slices.Insert(n.keys, i, key)
+		n.values = slices.Insert(n.values, i, value)
+		t.meta.len++
+		t.metaDirty = true
+		return t.splitIfFull(n)
+	}
+
+	i := childIndex(n, key)
+	child, err := t.read(n.children[i])
+	if err != nil {
+		return nil, 0, err
+	}
+	sep, right, err := t.insert(child, key, value)
+	if err != nil || right == 0 {
+		return nil, 0, err
+	}
+	n.keys = slices.Insert(n.keys, i, sep)
+	n.children = slices.Insert(n.children, i+1, right)
+	return t.splitIfFull(n)
+}
+
+// splitIfFull writes n, first moving its upper half to a new right sibling
+// if it has more than maxKeys keys
+func (t *BTree) splitIfFull(n *node) ([]byte, PageID, error) {
+	if len(n.keys) <= t.maxKeys {
+		return nil, 0, t.write(n)
+	}
+	id, err := t.allocate()
+	if err != nil {
+		return nil, 0, err
+	}
+	mid := len(n.keys) / 2
+	right := &node{id: id, leaf: n.leaf}
+	var sep []byte
+	if n.leaf {
+		// Leaves keep every key, so the separator is copied up
+		sep = n.keys[mid]
+		right.keys = slices.Clone(n.keys[mid:])
+		right.values = slices.Clone(n.values[mid:])
+		right.next, n.next = n.next, id
+		n.keys, n.values = n.keys[:mid], n.values[:mid]
+	} else {
+		// Internal nodes move the separator up
+		sep = n.keys[mid]
+		right.keys = slices.Clone(n.keys[mid+1:])
+		right.children = slices.Clone(n.children[mid+1:])
+		n.keys, n.children = n.keys[:mid], n.children[:mid+1]
+	}
+	if err := t.write(right); err != nil {
+		return nil, 0, err
+	}
+	return sep, id, t.write(n)
+}
+
+// Delete removes a key and reports whether it was present
+func (t *BTree) Delete(key []byte) (bool, error) {
+	root, err := t.read(t.meta.root)
+	if err != nil {
+		return false, err
+	}
+	removed, err := t.remove(root, key)
+	if err != nil || !removed {
+		return removed, err
+	}
+	t.meta.len--
+	t.metaDirty = true
+
+	// A root left with a single child is replaced by it
+	if !root.leaf && len(root.keys) == 0 {
+		t.meta.root = root.children[0]
+		return true, t.free(root.id)
+	}
+	return true, nil
+}
+
+// remove deletes key below n and writes every node it changes. Children
+// that end up with fewer than minKeys keys are refilled from a sibling.
+func (t *BTree) remove(n *node, key []byte) (bool, error) {
+	if n.leaf {
+		i, found := search(n.keys, key)
+		if !found {
+			return false, nil
+		}
+		n.keys = slices.Delete(n.keys, i, i+1)
+		n.values = slices.Delete(n.values, i, i+1)
+		return true, t.write(n)
+	}
+
+	i := childIndex(n, key)
+	child, err := t.read(n.children[i])
+	if err != nil {
+		return false, err
+	}
+	removed, err := t.remove(child, key)
+	if err != nil || !removed || len(child.keys) >= t.minKeys {
+		return removed, err
+	}
+	return true, t.rebalance(n, i, child)
+}
+
+// rebalance refills child i of parent, which has one key too few, by
+// borrowing from a sibling that can spare a key or else merging with one
+func (t *BTree) rebalance(parent *node, i int, child *node) error {
+	if i > 0 {
+		left, err := t.read(parent.children[i-1])
+		if err != nil {
+			return err
+		}
+		if len(left.keys) > t.minKeys {
+			borrowFromLeft(parent, i, left, child)
+			return t.writeAll(parent, left, child)
+		}
+		return t.merge(parent, i-1, left, child)
+	}
+	right, err := t.read(parent.children[i+1])
+	if err != nil {
+		return err
+	}
+	if len(right.keys) > t.minKeys {
+		borrowFromRight(parent, i, child, right)
+		return t.writeAll(parent, child, right)
+	}
+	return t.merge(parent, i, child, right)
+}
+
+func borrowFromLeft(parent *node, i int, left, child *node) {
+	last := len(left.keys) - 1
+	if child.leaf {
+		child.keys = slices.Insert(child.keys, 0, left.keys[last])
+		child.values = slices.Insert(child.values, 0, left.values[last])
+		left.keys, left.values = left.keys[:last], left.values[:last]
+		parent.keys[i-1] = child.keys[0]
+		return
+	}
+	child.keys = slices.Insert(child.keys, 0, parent.keys[i-1])
+	child.children = slices.Insert(child.children, 0, left.children[last+1])
+	parent.keys[i-1] = left.keys[last]
+	left.keys, left.children = left.keys[:last], left.children[:last+1]
+}
+
+func borrowFromRight(parent *node, i int, child, right *node) {
+	if child.leaf {
+		child.keys = append(child.keys, right.keys[0])
+		child.values = append(child.values, right.values[0])
+		right.keys, right.values = right.keys[1:], right.values[1:]
+		parent.keys[i] = right.keys[0]
+		return
+	}
+	child.keys = append(child.keys, parent.keys[i])
+	child.children = append(child.children, right.children[0])
+	parent.keys[i] = right.keys[0]
+	right.keys, right.children = right.keys[1:], right.children[1:]
+}
+
+// merge moves everything from children j+1 into child j and frees the page
+// of the right one
+func (t *BTree) merge(parent *node, j int, left, right *node) error {
+	if left.leaf {
+		left.keys = append(left.keys, right.keys...)
+		left.values = append(left.values, right.values...)
+		left.next = right.next
+	} else {
+		left.keys = append(append(left.keys, parent.keys[j]), right.keys...)
+		left.children = append(left.children, right.children...)
+	}
+	parent.keys = slices.Delete(parent.keys, j, j+1)
+	parent.children = slices.Delete(parent.children, j+1, j+2)
+	if err := t.writeAll(parent, left); err != nil {
+		return err
+	}
+	return t.free(right.id)
+}
+
+func (t *BTree) writeAll(nodes ...*node) error {
+	for _, n := range nodes {
+		if err := t.write(n); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// All returns an iterator over all entries in ascending key order. The tree
+// must not be modified during iteration. If reading a page fails, iteration
+// stops and Err reports the error.
+func (t *BTree) All() iter.Seq2[[]byte, []byte] {
+	return t.Range(nil, nil)
+}
+
+// Range returns an iterator over the entries with lo <= key <= hi in
+// ascending key order. A nil lo or hi leaves that end of the range open.
+// The tree must not be modified during iteration. If reading a page fails,
+// iteration stops and Err reports the error.
+func (t *BTree) Range(lo, hi []byte) iter.Seq2[[]byte, []byte] {
+	return func(yield func([]byte, []byte) bool) {
+		leaf, err := t.findLeaf(lo)
+		var i int
+		if err == nil {
+			i, _ = search(leaf.keys, lo)
+		}
+		for err == nil {
+			for ; i < len(leaf.keys); i++ {
+				if hi != nil && bytes.Compare(leaf.keys[i], hi) > 0 {
+					return
+				}
+				if !yield(leaf.keys[i], leaf.values[i]) {
+					return
+				}
+			}
+			if leaf.next == 0 {
+				return
+			}
+			leaf, err = t.read(leaf.next)
+			i = 0
+		}
+		t.err = err
+	}
+}
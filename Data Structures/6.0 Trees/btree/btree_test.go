@@ -0,0 +1,325 @@
+package btree
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"maps"
+	"math/rand"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+// checkInvariants walks every page of the tree and verifies key order,
+// node occupancy, equal leaf depth, the leaf chain, the key count and that
+// every page is either reachable or on the free list.
+func checkInvariants(t *testing.T, tr *BTree) {
+	t.Helper()
+	var leaves []PageID
+	leafDepth := -1
+	count := 0
+	used := map[PageID]bool{}
+
+	var walk func(id PageID, depth int, lo, hi []byte)
+	walk = func(id PageID, depth int, lo, hi []byte) {
+		if used[id] {
+			t.Fatalf("page %d is reachable twice", id)
+		}
+		used[id] = true
+		n, err := tr.read(id)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(n.keys) > tr.maxKeys {
+			t.Fatalf("page %d has %d keys; max %d", id, len(n.keys), tr.maxKeys)
+		}
+		if id != tr.meta.root && len(n.keys) < tr.minKeys {
+			t.Fatalf("page %d has %d keys; min %d", id, len(n.keys), tr.minKeys)
+		}
+		for i, key := range n.keys {
+			if i > 0 && bytes.Compare(n.keys[i-1], key) >= 0 {
+				t.Fatalf("page %d: keys out of order", id)
+			}
+			if lo != nil && bytes.Compare(key, lo) < 0 || hi != nil && bytes.Compare(key, hi) >= 0 {
+				t.Fatalf("page %d: key %q outside [%q, %q)", id, key, lo, hi)
+			}
+		}
+		if n.leaf {
+			if leafDepth == -1 {
+				leafDepth = depth
+			} else if depth != leafDepth {
+				t.Fatalf("leaf %d at depth %d; others at %d", id, depth, leafDepth)
+			}
+			leaves = append(leaves, id)
+			count += len(n.keys)
+			return
+		}
+		for i, child := range n.children {
+			childLo, childHi := lo, hi
+			if i > 0 {
+				childLo = n.keys[i-1]
+			}
+			if i < len(n.keys) {
+				childHi = n.keys[i]
+			}
+			walk(child, depth+1, childLo, childHi)
+		}
+	}
+	walk(tr.meta.root, 0, nil, nil)
+
+	for i, id := range leaves {
+		n, _ := tr.read(id)
+		want := PageID(0)
+		if i+1 < len(leaves) {
+			want = leaves[i+1]
+		}
+		if n.next != want {
+			t.Fatalf("leaf %d links to %d; want %d", id, n.next, want)
+		}
+	}
+	if count != tr.Len() {
+		t.Fatalf("tree holds %d keys; Len() = %d", count, tr.Len())
+	}
+
+	p := make([]byte, tr.meta.pageSize)
+	for id := tr.meta.freeHead; id != 0; id = PageID(binary.LittleEndian.Uint32(p[4:])) {
+		if used[id] {
+			t.Fatalf("free page %d is in use", id)
+		}
+		used[id] = true
+		if err := tr.store.ReadPage(id, p); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(used) != int(tr.meta.pageCount)-1 {
+		t.Fatalf("%d pages are used or free; the store has %d", len(used), tr.meta.pageCount-1)
+	}
+}
+
+func key(i int) []byte {
+	return fmt.Appendf(nil, "key%06d", i)
+}
+
+// TestAgainstMap applies random operations to trees of several orders, in
+// memory and in a file, and compares them with a map.
+func TestAgainstMap(t *testing.T) {
+	for _, order := range []int{3, 4, 5, 16} {
+		for _, backing := range []string{"memory", "file"} {
+			t.Run(fmt.Sprintf("order%d/%s", order, backing), func(t *testing.T) {
+				var tr *BTree
+				var err error
+				if backing == "memory" {
+					tr, err = NewMemory(order)
+				} else {
+					tr, err = Open(filepath.Join(t.TempDir(), "tree.db"), Options{PageSize: 512, Order: order})
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				defer tr.Close()
+
+				rng := rand.New(rand.NewSource(int64(order)))
+				want := map[string]string{}
+				for i := range 4000 {
+					k := key(rng.Intn(1000))
+					if rng.Intn(3) == 0 {
+						removed, err := tr.Delete(k)
+						if err != nil {
+							t.Fatal(err)
+						}
+						_, wantRemoved := want[string(k)]
+						if removed != wantRemoved {
+							t.Fatalf("Delete(%s) = %t; want %t", k, removed, wantRemoved)
+						}
+						delete(want, string(k))
+					} else {
+						v := fmt.Sprint(i)
+						if err := tr.Put(k, []byte(v)); err != nil {
+							t.Fatal(err)
+						}
+						want[string(k)] = v
+					}
+					if i%500 == 0 {
+						checkInvariants(t, tr)
+					}
+				}
+				checkInvariants(t, tr)
+				checkContents(t, tr, want)
+
+				lo, hi := key(250), key(260)
+				var got []string
+				for k := range tr.Range(lo, hi) {
+					got = append(got, string(k))
+				}
+				var wantKeys []string
+				for k := range want {
+					if k >= string(lo) && k <= string(hi) {
+						wantKeys = append(wantKeys, k)
+					}
+				}
+				slices.Sort(wantKeys)
+				if !slices.Equal(got, wantKeys) {
+					t.Fatalf("Range = %v; want %v", got, wantKeys)
+				}
+
+				for k := range want {
+					if _, err := tr.Delete([]byte(k)); err != nil {
+						t.Fatal(err)
+					}
+				}
+				checkInvariants(t, tr)
+				if tr.Len() != 0 {
+					t.Fatalf("Len() = %d after deleting every key", tr.Len())
+				}
+			})
+		}
+	}
+}
+
+// checkContents compares Get and All with want
+func checkContents(t *testing.T, tr *BTree, want map[string]string) {
+	t.Helper()
+	got := map[string]string{}
+	for k, v := range tr.All() {
+		got[string(k)] = string(v)
+	}
+	if tr.Err() != nil {
+		t.Fatal(tr.Err())
+	}
+	if !maps.Equal(got, want) {
+		t.Fatalf("All() returned %d entries, want %d", len(got), len(want))
+	}
+	for k, v := range want {
+		value, ok, err := tr.Get([]byte(k))
+		if err != nil || !ok || string(value) != v {
+			t.Fatalf("Get(%s) = %s, %t, %v; want %s", k, value, ok, err, v)
+		}
+	}
+	if _, ok, _ := tr.Get([]byte("missing")); ok {
+		t.Fatalf("Get(missing) found a value")
+	}
+}
+
+func TestReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tree.db")
+	tr, err := Open(path, Options{PageSize: 256, Order: 8})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{}
+	for i := range 500 {
+		tr.Put(key(i), key(i*2))
+		want[string(key(i))] = string(key(i * 2))
+	}
+	for i := 0; i < 500; i += 3 {
+		tr.Delete(key(i))
+		delete(want, string(key(i)))
+	}
+	if err := tr.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := Open(path, Options{Order: 9}); err == nil {
+		t.Fatalf("Open with a different order succeeded")
+	}
+	tr, err = Open(path, Options{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer tr.Close()
+	if tr.Order() != 8 {
+		t.Fatalf("Order() = %d after reopening; want 8", tr.Order())
+	}
+	checkInvariants(t, tr)
+	checkContents(t, tr, want)
+}
+
+func TestBulkLoad(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 3, 7, 8, 9, 100, 1000, 4097} {
+		for _, order := range []int{3, 4, 5, 32} {
+			tr, err := NewMemory(order)
+			if err != nil {
+				t.Fatal(err)
+			}
+			want := map[string]string{}
+			err = tr.BulkLoad(func(yield func([]byte, []byte) bool) {
+				for i := range n {
+					want[string(key(i))] = fmt.Sprint(i)
+					if !yield(key(i), []byte(fmt.Sprint(i))) {
+						return
+					}
+				}
+			})
+			if err != nil {
+				t.Fatalf("n=%d order=%d: %v", n, order, err)
+			}
+			checkInvariants(t, tr)
+			checkContents(t, tr, want)
+
+			// The loaded tree accepts ordinary updates
+			tr.Put([]byte("a"), nil)
+			tr.Delete(key(n / 2))
+			checkInvariants(t, tr)
+		}
+	}
+}
+
+func TestBulkLoadErrors(t *testing.T) {
+	tr, _ := NewMemory(4)
+	unsorted := func(yield func([]byte, []byte) bool) {
+		_ = yield([]byte("b"), nil) && yield([]byte("a"), nil)
+	}
+	if err := tr.BulkLoad(unsorted); !errors.Is(err, ErrUnsorted) {
+		t.Fatalf("BulkLoad(unsorted) = %v; want ErrUnsorted", err)
+	}
+
+	tr, _ = NewMemory(4)
+	tr.Put([]byte("x"), nil)
+	if err := tr.BulkLoad(func(func([]byte, []byte) bool) {}); !errors.Is(err, ErrNotEmpty) {
+		t.Fatalf("BulkLoad on a non-empty tree = %v; want ErrNotEmpty", err)
+	}
+	if err := tr.Put(make([]byte, tr.MaxEntrySize()+1), nil); !errors.Is(err, ErrTooLarge) {
+		t.Fatalf("Put(too large) = %v; want ErrTooLarge", err)
+	}
+	if err := tr.Put(make([]byte, tr.MaxEntrySize()), nil); err != nil {
+		t.Fatalf("Put(largest entry) = %v", err)
+	}
+}
+
+func TestOptions(t *testing.T) {
+	if _, err := NewMemory(2); err == nil {
+		t.Fatalf("order 2 was accepted")
+	}
+	if _, err := New(NewMemoryStore(), Options{PageSize: 512, Order: 100}); err == nil {
+		t.Fatalf("order 100 was accepted for 512-byte pages")
+	}
+}
+
+// TestPageLayout pins the documented binary layout of a leaf page.
+func TestPageLayout(t *testing.T) {
+	n := &node{id: 5, leaf: true, keys: [][]byte{[]byte("ab")}, values: [][]byte{[]byte("xyz")}, next: 9}
+	p := make([]byte, 32)
+	n.encode(p)
+	want := []byte{
+		1, 0, 1, 0, 9, 0, 0, 0, // leaf, 1 key, next leaf 9
+		2, 0, 'a', 'b', 3, 0, 'x', 'y', 'z',
+	}
+	if !bytes.Equal(p[:len(want)], want) {
+		t.Fatalf("encoded leaf = %v; want %v", p[:len(want)], want)
+	}
+	decoded, err := decodeNode(5, p)
+	if err != nil || decoded.next != 9 || string(decoded.keys[0]) != "ab" || string(decoded.values[0]) != "xyz" {
+		t.Fatalf("decodeNode = %+v, %v", decoded, err)
+	}
+
+	p[0] = 7
+	if _, err := decodeNode(5, p); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("decodeNode(bad type) = %v; want ErrCorrupt", err)
+	}
+	p[0], p[2] = 1, 200
+	if _, err := decodeNode(5, p); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("decodeNode(bad count) = %v; want ErrCorrupt", err)
+	}
+}
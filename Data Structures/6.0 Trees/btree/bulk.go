@@ -0,0 +1,138 @@
+package btree
+
+import (
+	"bytes"
+	"iter"
+	"slices"
+)
+
+// childRef is a finished node of the level being built by BulkLoad
+type childRef struct {
+	first []byte // smallest key below the node
+	id    PageID
+}
+
+// BulkLoad fills an empty tree from entries in strictly ascending key
+// order. Instead of inserting one key at a time, it writes every leaf once,
+// packed full, and then builds each level of internal nodes from the first
+// keys of the level below. Only the last node of each level may be less
+// full, and never below the minimum occupancy.
+//
+// BulkLoad returns ErrNotEmpty if the tree has keys, and ErrUnsorted or
+// ErrTooLarge for bad input. An error after some entries were loaded leaves
+// the tree inconsistent, so the store should then be discarded.
+func (t *BTree) BulkLoad(entries iter.Seq2[[]byte, []byte]) error {
+	if t.meta.len != 0 {
+		return ErrNotEmpty
+	}
+
+	// Build the leaves. The last full leaf stays unwritten until the next one
+	// fills up, so the last two leaves can share entries at the end.
+	var leaves []childRef
+	var prev *node
+	cur := &node{id: t.meta.root, leaf: true}
+	for key, value := range entries {
+		if len(key)+len(value) > t.MaxEntrySize() {
+			return ErrTooLarge
+		}
+		if t.meta.len > 0 && bytes.Compare(key, lastKey(cur, prev)) <= 0 {
+			return ErrUnsorted
+		}
+		if len(cur.keys) == t.maxKeys {
+			id, err := t.allocate()
+			if err != nil {
+				return err
+			}
+			cur.next = id
+			if prev != nil {
+				if err := t.write(prev); err != nil {
+					return err
+				}
+				leaves = append(leaves, childRef{prev.keys[0], prev.id})
+			}
+			prev, cur = cur, &node{id: id, leaf: true}
+		}
+		cur.keys = append(cur.keys, slices.Clone(key))
+		cur.values = append(cur.values, slices.Clone(value))
+		t.meta.len++
+		t.metaDirty = true
+	}
+	if prev != nil {
+		if len(cur.keys) < t.minKeys {
+			// Move the upper half of prev's entries into the last leaf
+			mid := (len(prev.keys) + len(cur.keys)) / 2
+			mid = len(prev.keys) + len(cur.keys) - mid
+			cur.keys = append(slices.Clone(prev.keys[mid:]), cur.keys...)
+			cur.values = append(slices.Clone(prev.values[mid:]), cur.values...)
+			prev.keys, prev.values = prev.keys[:mid], prev.values[:mid]
+		}
+		if err := t.write(prev); err != nil {
+			return err
+		}
+		leaves = append(leaves, childRef{prev.keys[0], prev.id})
+	}
+	if err := t.write(cur); err != nil {
+		return err
+	}
+	if len(cur.keys) > 0 {
+		leaves = append(leaves, childRef{cur.keys[0], cur.id})
+	}
+
+	level := leaves
+	for len(level) > 1 {
+		next, err := t.buildLevel(level)
+		if err != nil {
+			return err
+		}
+		level = next
+	}
+	if len(level) == 1 {
+		t.meta.root = level[0].id
+	}
+	return nil
+}
+
+// lastKey returns the largest key loaded so far
+func lastKey(cur, prev *node) []byte {
+	if len(cur.keys) > 0 {
+		return cur.keys[len(cur.keys)-1]
+	}
+	return prev.keys[len(prev.keys)-1]
+}
+
+// buildLevel writes internal nodes with up to Order children each over the
+// given nodes and returns the new nodes
+func (t *BTree) buildLevel(children []childRef) ([]childRef, error) {
+	order := t.meta.order
+	var sizes []int
+	for rest := len(children); rest > 0; rest -= order {
+		sizes = append(sizes, min(rest, order))
+	}
+	// An underfull last node shares children with the one before it
+	if last := len(sizes) - 1; last > 0 && sizes[last]-1 < t.minKeys {
+		total := sizes[last-1] + sizes[last]
+		sizes[last-1], sizes[last] = total-total/2, total/2
+	}
+
+	var level []childRef
+	for _, size := range sizes {
+		group := children[:size]
+		children = children[size:]
+		id, err := t.allocate()
+		if err != nil {
+			return nil, err
+		}
+		n := &node{id: id, children: make([]PageID, size), keys: make([][]byte, size-1)}
+		for i, c := range group {
+			n.children[i] = c.id
+			if i > 0 {
+				n.keys[i-1] = c.first
+			}
+		}
+		if err := t.write(n); err != nil {
+			return nil, err
+		}
+		level = append(level, childRef{group[0].first, id})
+	}
+	return level, nil
+}
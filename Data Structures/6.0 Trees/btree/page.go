@@ -0,0 +1,203 @@
+package btree
+
+import (
+	"encoding/binary"
+	"fmt"
+)
+
+// Page layout
+//
+// Every page is PageSize bytes long and all integers are little endian.
+// Page 0 is the meta page:
+//
+//	offset  size  field
+//	0       4     magic "BPT+"
+//	4       2     format version (1)
+//	6       2     reserved
+//	8       4     page size in bytes
+//	12      4     order (maximum number of children of a node)
+//	16      4     root page
+//	20      4     number of pages in the store, including the meta page
+//	24      4     first page of the free list, 0 if empty
+//	28      4     reserved
+//	32      8     number of keys in the tree
+//
+// Every other page starts with an 8-byte header:
+//
+//	offset  size  field
+//	0       1     page type: 1 leaf, 2 internal, 3 free
+//	1       1     reserved
+//	2       2     number of keys n
+//	4       4     leaf: next leaf page, 0 for the last leaf
+//	              free: next page of the free list, 0 for the last one
+//
+// A leaf page continues with n entries, in ascending key order:
+//
+//	2 bytes key length, key, 2 bytes value length, value
+//
+// An internal page continues with its first child and then n keys, each
+// followed by the child to its right:
+//
+//	4 bytes child 0, then n times: 2 bytes key length, key, 4 bytes child
+//
+// The keys in child i are >= key i-1 and < key i. The rest of a page is zero.
+
+const (
+	magic         = "BPT+"
+	formatVersion = 1
+	metaSize      = 40
+	headerSize    = 8
+
+	leafPage     = 1
+	internalPage = 2
+	freePage     = 3
+)
+
+// PageID identifies a page in a PageStore. Page 0 is the meta page, so 0
+// also means "no page" in links.
+type PageID uint32
+
+// meta is the decoded meta page
+type meta struct {
+	pageSize  int
+	order     int
+	root      PageID
+	pageCount PageID
+	freeHead  PageID
+	len       uint64
+}
+
+func (m *meta) encode(p []byte) {
+	clear(p)
+	copy(p, magic)
+	binary.LittleEndian.PutUint16(p[4:], formatVersion)
+	binary.LittleEndian.PutUint32(p[8:], uint32(m.pageSize))
+	binary.LittleEndian.PutUint32(p[12:], uint32(m.order))
+	binary.LittleEndian.PutUint32(p[16:], uint32(m.root))
+	binary.LittleEndian.PutUint32(p[20:], uint32(m.pageCount))
+	binary.LittleEndian.PutUint32(p[24:], uint32(m.freeHead))
+	binary.LittleEndian.PutUint64(p[32:], m.len)
+}
+
+func decodeMeta(p []byte) (*meta, error) {
+	if len(p) < metaSize || string(p[:4]) != magic {
+		return nil, fmt.Errorf("%w: bad magic in meta page", ErrCorrupt)
+	}
+	if v := binary.LittleEndian.Uint16(p[4:]); v != formatVersion {
+		return nil, fmt.Errorf("%w: unsupported format version %d", ErrCorrupt, v)
+	}
+	return &meta{
+		pageSize:  int(binary.LittleEndian.Uint32(p[8:])),
+		order:     int(binary.LittleEndian.Uint32(p[12:])),
+		root:      PageID(binary.LittleEndian.Uint32(p[16:])),
+		pageCount: PageID(binary.LittleEndian.Uint32(p[20:])),
+		freeHead:  PageID(binary.LittleEndian.Uint32(p[24:])),
+		len:       binary.LittleEndian.Uint64(p[32:]),
+	}, nil
+}
+
+// node is a decoded leaf or internal page
+type node struct {
+	id       PageID
+	leaf     bool
+	keys     [][]byte
+	values   [][]byte // leaf only, parallel to keys
+	children []PageID // internal only, len(keys)+1 entries
+	next     PageID   // leaf only
+}
+
+// encode writes the node into p, which must be a whole page. The caller
+// guarantees that the node fits.
+func (n *node) encode(p []byte) {
+	clear(p)
+	binary.LittleEndian.PutUint16(p[2:], uint16(len(n.keys)))
+	off := headerSize
+	putBytes := func(b []byte) {
+		binary.LittleEndian.PutUint16(p[off:], uint16(len(b)))
+		off += 2 + copy(p[off+2:], b)
+	}
+	putChild := func(id PageID) {
+		binary.LittleEndian.PutUint32(p[off:], uint32(id))
+		off += 4
+	}
+
+	if n.leaf {
+		p[0] = leafPage
+		binary.LittleEndian.PutUint32(p[4:], uint32(n.next))
+		for i, key := range n.keys {
+			putBytes(key)
+			putBytes(n.values[i])
+		}
+		return
+	}
+	p[0] = internalPage
+	putChild(n.children[0])
+	for i, key := range n.keys {
+		putBytes(key)
+		putChild(n.children[i+1])
+	}
+}
+
+// decodeNode parses a leaf or internal page. Keys and values point into p,
+// so p must not be reused afterwards.
+func decodeNode(id PageID, p []byte) (*node, error) {
+	corrupt := func(what string) error {
+		return fmt.Errorf("%w: page %d: %s", ErrCorrupt, id, what)
+	}
+	count := int(binary.LittleEndian.Uint16(p[2:]))
+	off := headerSize
+	getBytes := func() ([]byte, bool) {
+		if off+2 > len(p) {
+			return nil, false
+		}
+		size := int(binary.LittleEndian.Uint16(p[off:]))
+		off += 2
+		if off+size > len(p) {
+			return nil, false
+		}
+		b := p[off : off+size : off+size]
+		off += size
+		return b, true
+	}
+	getChild := func() (PageID, bool) {
+		if off+4 > len(p) {
+			return 0, false
+		}
+		child := PageID(binary.LittleEndian.Uint32(p[off:]))
+		off += 4
+		return child, child != 0
+	}
+
+	n := &node{id: id, keys: make([][]byte, count)}
+	switch p[0] {
+	case leafPage:
+		n.leaf = true
+		n.next = PageID(binary.LittleEndian.Uint32(p[4:]))
+		n.values = make([][]byte, count)
+		for i := range count {
+			key, ok := getBytes()
+			value, ok2 := getBytes()
+			if !ok || !ok2 {
+				return nil, corrupt("leaf entries overflow the page")
+			}
+			n.keys[i], n.values[i] = key, value
+		}
+	case internalPage:
+		n.children = make([]PageID, count+1)
+		var ok bool
+		if n.children[0], ok = getChild(); !ok {
+			return nil, corrupt("bad child link")
+		}
+		for i := range count {
+			key, ok := getBytes()
+			child, ok2 := getChild()
+			if !ok || !ok2 {
+				return nil, corrupt("internal entries overflow the page")
+			}
+			n.keys[i], n.children[i+1] = key, child
+		}
+	default:
+		return nil, corrupt(fmt.Sprintf("unexpected page type %d", p[0]))
+	}
+	return n, nil
+}
@@ -0,0 +1,83 @@
+package btree
+
+import (
+	"errors"
+	"io"
+	"os"
+	"slices"
+)
+
+// PageStore reads and writes fixed-size pages by number. The B+tree always
+// passes buffers of exactly one page.
+type PageStore interface {
+	// ReadPage fills p with the page. It returns ErrPageNotFound for a page
+	// that was never written.
+	ReadPage(id PageID, p []byte) error
+	WritePage(id PageID, p []byte) error
+	// Sync makes all written pages durable
+	Sync() error
+	Close() error
+}
+
+// MemoryStore keeps pages in memory. Pages are still encoded in the binary
+// page layout, so a tree behaves the same on a MemoryStore and a FileStore.
+type MemoryStore struct {
+	pages [][]byte
+}
+
+// NewMemoryStore creates an empty in-memory page store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (s *MemoryStore) ReadPage(id PageID, p []byte) error {
+	if int(id) >= len(s.pages) || s.pages[id] == nil {
+		return ErrPageNotFound
+	}
+	copy(p, s.pages[id])
+	return nil
+}
+
+func (s *MemoryStore) WritePage(id PageID, p []byte) error {
+	for int(id) >= len(s.pages) {
+		s.pages = append(s.pages, nil)
+	}
+	s.pages[id] = slices.Clone(p)
+	return nil
+}
+
+func (s *MemoryStore) Sync() error  { return nil }
+func (s *MemoryStore) Close() error { return nil }
+
+// FileStore keeps page i at offset i*pageSize of a file
+type FileStore struct {
+	f        *os.File
+	pageSize int
+}
+
+// NewFileStore creates a page store on top of an open file. The store takes
+// ownership of the file and closes it in Close.
+func NewFileStore(f *os.File, pageSize int) *FileStore {
+	return &FileStore{f: f, pageSize: pageSize}
+}
+
+func (s *FileStore) ReadPage(id PageID, p []byte) error {
+	_, err := s.f.ReadAt(p, int64(id)*int64(s.pageSize))
+	if errors.Is(err, io.EOF) {
+		return ErrPageNotFound
+	}
+	return err
+}
+
+func (s *FileStore) WritePage(id PageID, p []byte) error {
+	_, err := s.f.WriteAt(p, int64(id)*int64(s.pageSize))
+	return err
+}
+
+func (s *FileStore) Sync() error {
+	return s.f.Sync()
+}
+
+func (s *FileStore) Close() error {
+	return s.f.Close()
+}
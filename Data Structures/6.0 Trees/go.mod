@@ -0,0 +1,3 @@
+module go-mastery/trees
+
+go 1.23.4
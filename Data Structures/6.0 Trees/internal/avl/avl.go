@@ -0,0 +1,58 @@
+// Package avl holds the rotations shared by the AVL trees in this module.
+// Each tree keeps its own node type, with whatever extra fields it needs,
+// and gives this package access to the links through the Node interface.
+package avl
+
+// Node is implemented by a pointer to a node of an AVL tree. Height must
+// return 0 for a nil pointer. Update recomputes the height, and any other
+// field that describes the subtree, from the children.
+type Node[N any] interface {
+	Left() N
+	Right() N
+	SetLeft(N)
+	SetRight(N)
+	Height() int
+	Update()
+}
+
+// balanceFactor is positive when the left subtree is taller
+func balanceFactor[N Node[N]](n N) int {
+	return n.Left().Height() - n.Right().Height()
+}
+
+func rotateRight[N Node[N]](n N) N {
+	l := n.Left()
+	n.SetLeft(l.Right())
+	l.SetRight(n)
+	n.Update()
+	l.Update()
+	return l
+}
+
+func rotateLeft[N Node[N]](n N) N {
+	r := n.Right()
+	n.SetRight(r.Left())
+	r.SetLeft(n)
+	n.Update()
+	r.Update()
+	return r
+}
+
+// Rebalance updates n and restores the AVL property at n, whose children
+// differ in height by at most two, and returns the new root of the subtree
+func Rebalance[N Node[N]](n N) N {
+	n.Update()
+	switch bf := balanceFactor(n); {
+	case bf > 1:
+		if balanceFactor(n.Left()) < 0 {
+			n.SetLeft(rotateLeft(n.Left()))
+		}
+		return rotateRight(n)
+	case bf < -1:
+		if balanceFactor(n.Right()) > 0 {
+			n.SetRight(rotateRight(n.Right()))
+		}
+		return rotateLeft(n)
+	}
+	return n
+}
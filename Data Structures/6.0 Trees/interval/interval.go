@@ -0,0 +1,245 @@
+// Package interval implements an interval tree: a map from closed
+// intervals to values that finds every interval overlapping a point or
+// another interval.
+package interval
+
+import (
+	"cmp"
+	"iter"
+
+	"go-mastery/trees/internal/avl"
+)
+
+// Interval is the closed interval [Lo, Hi], which contains both endpoints
+type Interval[K cmp.Ordered] struct {
+	Lo, Hi K
+}
+
+// Point returns the interval [p, p], which overlaps exactly the intervals
+// that contain p
+func Point[K cmp.Ordered](p K) Interval[K] {
+	return Interval[K]{p, p}
+}
+
+// Overlaps reports whether the two intervals have a point in common
+func (i Interval[K]) Overlaps(j Interval[K]) bool {
+	return i.Lo <= j.Hi && j.Lo <= i.Hi
+}
+
+// Contains reports whether p lies in the interval
+func (i Interval[K]) Contains(p K) bool {
+	return i.Lo <= p && p <= i.Hi
+}
+
+// compare orders intervals by Lo, then by Hi
+func (i Interval[K]) compare(j Interval[K]) int {
+	if c := cmp.Compare(i.Lo, j.Lo); c != 0 {
+		return c
+	}
+	return cmp.Compare(i.Hi, j.Hi)
+}
+
+// node is a node of the AVL tree. maxHi is the largest Hi in the subtree
+// rooted at the node, and like height it is kept up to date by every update.
+type node[K cmp.Ordered, V any] struct {
+	interval Interval[K]
+	value    V
+	left     *node[K, V]
+	right    *node[K, V]
+	height   int
+	maxHi    K
+}
+
+// The balancing itself lives in internal/avl, shared with tree.OrderedMap.
+// Each rotation calls Update, which is where maxHi is kept right.
+
+func (n *node[K, V]) Left() *node[K, V]      { return n.left }
+func (n *node[K, V]) Right() *node[K, V]     { return n.right }
+func (n *node[K, V]) SetLeft(l *node[K, V])  { n.left = l }
+func (n *node[K, V]) SetRight(r *node[K, V]) { n.right = r }
+
+func (n *node[K, V]) Height() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+// Update recomputes height and maxHi from the children
+func (n *node[K, V]) Update() {
+	n.height = 1 + max(n.left.Height(), n.right.Height())
+	n.maxHi = n.interval.Hi
+	if n.left != nil {
+		n.maxHi = max(n.maxHi, n.left.maxHi)
+	}
+	if n.right != nil {
+		n.maxHi = max(n.maxHi, n.right.maxHi)
+	}
+}
+
+// Tree maps closed intervals to values. It is an AVL tree ordered by the
+// intervals' low endpoints, in which every node also stores the largest
+// high endpoint in its subtree. A search can then skip every subtree whose
+// largest high endpoint is below the query, so Insert, Delete and Get take
+// O(log n) time and Overlapping takes O(log n + k) time for k results. The
+// zero value is an empty tree ready to use.
+type Tree[K cmp.Ordered, V any] struct {
+	root *node[K, V]
+	len  int
+}
+
+// New creates an empty interval tree
+func New[K cmp.Ordered, V any]() *Tree[K, V] {
+	return &Tree[K, V]{}
+}
+
+// Len returns the number of intervals in the tree
+func (t *Tree[K, V]) Len() int {
+	return t.len
+}
+
+// Height returns the height of the underlying tree
+func (t *Tree[K, V]) Height() int {
+	return t.root.Height()
+}
+
+// Insert sets the value for an interval, replacing the value if the same
+// interval is already present. It panics if iv.Lo > iv.Hi.
+func (t *Tree[K, V]) Insert(iv Interval[K], value V) {
+	if iv.Lo > iv.Hi {
+		panic("interval: Lo is greater than Hi")
+	}
+	var added bool
+	t.root, added = insert(t.root, iv, value)
+	if added {
+		t.len++
+	}
+}
+
+func insert[K cmp.Ordered, V any](n *node[K, V], iv Interval[K], value V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{interval: iv, value: value, height: 1, maxHi: iv.Hi}, true
+	}
+	var added bool
+	switch c := iv.compare(n.interval); {
+	case c < 0:
+		n.left, added = insert(n.left, iv, value)
+	case c > 0:
+		n.right, added = insert(n.right, iv, value)
+	default:
+		n.value = value
+		return n, false
+	}
+	return avl.Rebalance(n), added
+}
+
+// Get returns the value stored for exactly this interval
+func (t *Tree[K, V]) Get(iv Interval[K]) (V, bool) {
+	n := t.root
+	for n != nil {
+		switch c := iv.compare(n.interval); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes exactly this interval and reports whether it was present
+func (t *Tree[K, V]) Delete(iv Interval[K]) bool {
+	var deleted bool
+	t.root, deleted = remove(t.root, iv)
+	if deleted {
+		t.len--
+	}
+	return deleted
+}
+
+func remove[K cmp.Ordered, V any](n *node[K, V], iv Interval[K]) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var deleted bool
+	switch c := iv.compare(n.interval); {
+	case c < 0:
+		n.left, deleted = remove(n.left, iv)
+	case c > 0:
+		n.right, deleted = remove(n.right, iv)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// Replace the node with its in-order successor
+		var successor *node[K, V]
+		n.right, successor = removeMin(n.right)
+		successor.left, successor.right = n.left, n.right
+		return avl.Rebalance(successor), true
+	}
+	if !deleted {
+		return n, false
+	}
+	return avl.Rebalance(n), true
+}
+
+// removeMin unlinks the node with the smallest interval from a non-empty
+// subtree and returns the rebalanced subtree and that node
+func removeMin[K cmp.Ordered, V any](n *node[K, V]) (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var smallest *node[K, V]
+	n.left, smallest = removeMin(n.left)
+	return avl.Rebalance(n), smallest
+}
+
+// Overlapping returns an iterator over the intervals that overlap q, in
+// ascending order of their low endpoints. Use Point(p) as q to find the
+// intervals that contain p. An interval with q.Lo > q.Hi overlaps nothing.
+func (t *Tree[K, V]) Overlapping(q Interval[K]) iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		if q.Lo <= q.Hi {
+			overlapping(t.root, q, yield)
+		}
+	}
+}
+
+// overlapping yields the intervals of n's subtree that overlap q and
+// reports whether to continue
+func overlapping[K cmp.Ordered, V any](n *node[K, V], q Interval[K], yield func(Interval[K], V) bool) bool {
+	// Nothing in the subtree reaches q
+	if n == nil || n.maxHi < q.Lo {
+		return true
+	}
+	if !overlapping(n.left, q, yield) {
+		return false
+	}
+	// This interval and everything to its right start after q ends
+	if n.interval.Lo > q.Hi {
+		return true
+	}
+	if n.interval.Hi >= q.Lo && !yield(n.interval, n.value) {
+		return false
+	}
+	return overlapping(n.right, q, yield)
+}
+
+// All returns an iterator over all intervals in ascending order of their
+// low endpoints, then their high endpoints
+func (t *Tree[K, V]) All() iter.Seq2[Interval[K], V] {
+	return func(yield func(Interval[K], V) bool) {
+		inOrder(t.root, yield)
+	}
+}
+
+func inOrder[K cmp.Ordered, V any](n *node[K, V], yield func(Interval[K], V) bool) bool {
+	return n == nil ||
+		inOrder(n.left, yield) && yield(n.interval, n.value) && inOrder(n.right, yield)
+}
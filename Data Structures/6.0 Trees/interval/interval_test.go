@@ -0,0 +1,183 @@
+package interval
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkInvariants verifies that intervals are in BST order, that heights
+// and maxHi are correct, and that every node is balanced.
+func checkInvariants[K cmp.Ordered, V any](t *testing.T, tr *Tree[K, V]) {
+	t.Helper()
+	count := 0
+	var walk func(n *node[K, V], lo, hi *Interval[K]) int
+	walk = func(n *node[K, V], lo, hi *Interval[K]) int {
+		if n == nil {
+			return 0
+		}
+		count++
+		if (lo != nil && n.interval.compare(*lo) <= 0) || (hi != nil && n.interval.compare(*hi) >= 0) {
+			t.Fatalf("interval %v is out of BST order", n.interval)
+		}
+		lh := walk(n.left, lo, &n.interval)
+		rh := walk(n.right, &n.interval, hi)
+		if lh-rh > 1 || rh-lh > 1 {
+			t.Fatalf("node %v is unbalanced: left height %d, right height %d", n.interval, lh, rh)
+		}
+		maxHi := n.interval.Hi
+		for _, c := range []*node[K, V]{n.left, n.right} {
+			if c != nil {
+				maxHi = max(maxHi, c.maxHi)
+			}
+		}
+		if height := 1 + max(lh, rh); n.height != height || n.maxHi != maxHi {
+			t.Fatalf("node %v caches height %d maxHi %v; want %d %v", n.interval, n.height, n.maxHi, height, maxHi)
+		}
+		return n.height
+	}
+	walk(tr.root, nil, nil)
+	if count != tr.Len() {
+		t.Fatalf("found %d nodes; Len() = %d", count, tr.Len())
+	}
+}
+
+type entry struct {
+	iv    Interval[int]
+	value int
+}
+
+func collect(seq func(func(Interval[int], int) bool)) []entry {
+	var got []entry
+	for iv, v := range seq {
+		got = append(got, entry{iv, v})
+	}
+	return got
+}
+
+// TestAgainstBruteForce applies random operations to a Tree and to a
+// sorted slice of entries and compares every query.
+func TestAgainstBruteForce(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var tr Tree[int, int] // the zero value is ready to use
+	var want []entry      // sorted by interval
+	find := func(iv Interval[int]) (int, bool) {
+		return slices.BinarySearchFunc(want, iv, func(e entry, iv Interval[int]) int {
+			return e.iv.compare(iv)
+		})
+	}
+	randomInterval := func() Interval[int] {
+		lo := rng.Intn(1000)
+		return Interval[int]{lo, lo + rng.Intn(rng.Intn(100)+1)}
+	}
+	for op := range 20000 {
+		iv := randomInterval()
+		switch rng.Intn(4) {
+		case 0, 1:
+			tr.Insert(iv, op)
+			if i, found := find(iv); found {
+				want[i].value = op
+			} else {
+				want = slices.Insert(want, i, entry{iv, op})
+			}
+		case 2:
+			// Delete an existing interval most of the time
+			if len(want) > 0 && rng.Intn(4) > 0 {
+				iv = want[rng.Intn(len(want))].iv
+			}
+			i, found := find(iv)
+			if got := tr.Delete(iv); got != found {
+				t.Fatalf("Delete(%v) = %t; want %t", iv, got, found)
+			}
+			if found {
+				want = slices.Delete(want, i, i+1)
+			}
+		default:
+			if rng.Intn(2) == 0 {
+				iv = Point(iv.Lo)
+			}
+			var expected []entry
+			for _, e := range want {
+				if e.iv.Overlaps(iv) {
+					expected = append(expected, e)
+				}
+			}
+			if got := collect(tr.Overlapping(iv)); !slices.Equal(got, expected) {
+				t.Fatalf("Overlapping(%v) = %v; want %v", iv, got, expected)
+			}
+		}
+		if op%1000 == 0 {
+			checkInvariants(t, &tr)
+			if got := collect(tr.All()); !slices.Equal(got, want) {
+				t.Fatalf("All() = %v; want %v", got, want)
+			}
+		}
+	}
+	checkInvariants(t, &tr)
+	for _, e := range want {
+		if v, ok := tr.Get(e.iv); !ok || v != e.value {
+			t.Fatalf("Get(%v) = %d, %t; want %d", e.iv, v, ok, e.value)
+		}
+	}
+}
+
+func TestClosedEndpoints(t *testing.T) {
+	tr := New[int, string]()
+	tr.Insert(Interval[int]{10, 20}, "a")
+	tr.Insert(Interval[int]{20, 30}, "b")
+	tr.Insert(Point(25), "c")
+	tr.Insert(Interval[int]{10, 20}, "a2") // replaces a
+	for _, tc := range []struct {
+		q    Interval[int]
+		want []string
+	}{
+		{Point(20), []string{"a2", "b"}},
+		{Point(9), nil},
+		{Interval[int]{21, 24}, []string{"b"}},
+		{Interval[int]{25, 100}, []string{"b", "c"}},
+		{Interval[int]{0, 100}, []string{"a2", "b", "c"}},
+		{Interval[int]{30, 10}, nil}, // empty query
+	} {
+		var got []string
+		for _, v := range tr.Overlapping(tc.q) {
+			got = append(got, v)
+		}
+		if !slices.Equal(got, tc.want) {
+			t.Errorf("Overlapping(%v) = %v; want %v", tc.q, got, tc.want)
+		}
+	}
+	if tr.Len() != 3 {
+		t.Fatalf("Len() = %d; want 3", tr.Len())
+	}
+}
+
+// TestSortedInsertsStayBalanced inserts intervals in ascending order, which
+// would turn an unbalanced tree into a linked list.
+func TestSortedInsertsStayBalanced(t *testing.T) {
+	tr := New[int, int]()
+	const n = 1 << 16
+	for i := range n {
+		tr.Insert(Interval[int]{i, i + 10}, i)
+	}
+	checkInvariants(t, tr)
+	if tr.Height() > 24 {
+		t.Fatalf("Height() = %d for %d ascending intervals", tr.Height(), n)
+	}
+	count := 0
+	for range tr.Overlapping(Point(n / 2)) {
+		count++
+	}
+	if count != 11 {
+		t.Fatalf("%d intervals contain %d; want 11", count, n/2)
+	}
+}
+
+func TestInvalidInterval(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Insert of [2, 1] did not panic")
+		}
+	}()
+	New[int, int]().Insert(Interval[int]{2, 1}, 0)
+}
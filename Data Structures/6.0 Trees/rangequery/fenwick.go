@@ -0,0 +1,96 @@
+package rangequery
+
+import "math/bits"
+
+// Number is the set of types a Fenwick tree can sum
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr |
+		~float32 | ~float64
+}
+
+// Fenwick is a Fenwick tree (binary indexed tree) over a sequence of
+// numbers. It supports adding to a value and summing a prefix in O(log n)
+// time using a single slice of n numbers, which makes it smaller and faster
+// than a segment tree when only sums are needed.
+//
+// With 1-based positions, position p holds the sum of the values in
+// (p - lowbit(p), p], where lowbit(p) is the lowest set bit of p. A prefix
+// sum adds up O(log n) of these blocks by clearing the low bit repeatedly.
+type Fenwick[N Number] struct {
+	tree []N // tree[p-1] holds the block ending at position p
+}
+
+// NewFenwick creates a Fenwick tree over n zeros
+func NewFenwick[N Number](n int) *Fenwick[N] {
+	return &Fenwick[N]{tree: make([]N, n)}
+}
+
+// FenwickFrom creates a Fenwick tree over a copy of values in O(n) time
+func FenwickFrom[N Number](values []N) *Fenwick[N] {
+	f := &Fenwick[N]{tree: append([]N(nil), values...)}
+	// Each block passes its sum on to the next block that contains it
+	for p := 1; p <= len(f.tree); p++ {
+		if parent := p + p&-p; parent <= len(f.tree) {
+			f.tree[parent-1] += f.tree[p-1]
+		}
+	}
+	return f
+}
+
+// Len returns the length of the sequence
+func (f *Fenwick[N]) Len() int {
+	return len(f.tree)
+}
+
+// Add adds delta to the value at index i
+func (f *Fenwick[N]) Add(i int, delta N) {
+	checkIndex(i, len(f.tree))
+	for p := i + 1; p <= len(f.tree); p += p & -p {
+		f.tree[p-1] += delta
+	}
+}
+
+// PrefixSum returns the sum of the values in [0, i)
+func (f *Fenwick[N]) PrefixSum(i int) N {
+	checkRange(0, i, len(f.tree))
+	var sum N
+	for p := i; p > 0; p &= p - 1 {
+		sum += f.tree[p-1]
+	}
+	return sum
+}
+
+// RangeSum returns the sum of the values in [lo, hi)
+func (f *Fenwick[N]) RangeSum(lo, hi int) N {
+	checkRange(lo, hi, len(f.tree))
+	return f.PrefixSum(hi) - f.PrefixSum(lo)
+}
+
+// Get returns the value at index i
+func (f *Fenwick[N]) Get(i int) N {
+	return f.RangeSum(i, i+1)
+}
+
+// Set replaces the value at index i
+func (f *Fenwick[N]) Set(i int, v N) {
+	f.Add(i, v-f.Get(i))
+}
+
+// Search returns the smallest i such that PrefixSum(i+1) >= target, or Len()
+// if there is none. It takes O(log n) time and requires every value to be
+// non-negative, so that prefix sums never decrease. With counts as values,
+// this finds the element of a given rank.
+func (f *Fenwick[N]) Search(target N) int {
+	p := 0
+	var sum N
+	// Walk down the implicit tree, taking each block that keeps the sum
+	// below the target
+	for step := 1 << bits.Len(uint(len(f.tree))); step > 0; step /= 2 {
+		if next := p + step; next <= len(f.tree) && sum+f.tree[next-1] < target {
+			p = next
+			sum += f.tree[next-1]
+		}
+	}
+	return p
+}
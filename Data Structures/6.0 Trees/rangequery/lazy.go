@@ -0,0 +1,173 @@
+package rangequery
+
+// LazyOps describes the values and updates of a LazySegmentTree. Values of
+// type T are combined as in SegmentTree, and updates of type F change every
+// value in a range.
+type LazyOps[T, F any] struct {
+	// Combine is an associative function with Identity as its identity
+	Combine  func(a, b T) T
+	Identity T
+	// Apply returns the combination of size values after applying f to
+	// each of them, given their combination v before the update. For range
+	// addition on sums this is v + f*size.
+	Apply func(f F, v T, size int) T
+	// Compose returns the update that applies g and then f
+	Compose func(f, g F) F
+}
+
+// AddSum returns the operations for range addition and range sums
+func AddSum[N Number]() LazyOps[N, N] {
+	return LazyOps[N, N]{
+		Combine: func(a, b N) N { return a + b },
+		Apply:   func(f, v N, size int) N { return v + f*N(size) },
+		Compose: func(f, g N) N { return f + g },
+	}
+}
+
+// LazySegmentTree is a segment tree that also applies an update to every
+// value in a range in O(log n) time. An update to a range that covers a
+// node's whole segment is applied to the node's combined value and kept as
+// pending on the node. It is pushed down to the children only when a later
+// query or update needs to look inside that segment.
+type LazySegmentTree[T, F any] struct {
+	n       int
+	ops     LazyOps[T, F]
+	nodes   []T
+	pending []F
+	has     []bool // whether pending holds an update
+}
+
+// NewLazySegmentTree creates a lazy segment tree over a copy of values in
+// O(n) time
+func NewLazySegmentTree[T, F any](values []T, ops LazyOps[T, F]) *LazySegmentTree[T, F] {
+	n := len(values)
+	t := &LazySegmentTree[T, F]{
+		n:       n,
+		ops:     ops,
+		nodes:   make([]T, 4*max(n, 1)),
+		pending: make([]F, 4*max(n, 1)),
+		has:     make([]bool, 4*max(n, 1)),
+	}
+	if n > 0 {
+		t.build(1, 0, n, values)
+	}
+	return t
+}
+
+// build fills node i, which covers [lo, hi) of values
+func (t *LazySegmentTree[T, F]) build(i, lo, hi int, values []T) {
+	if hi-lo == 1 {
+		t.nodes[i] = values[lo]
+		return
+	}
+	mid := (lo + hi) / 2
+	t.build(2*i, lo, mid, values)
+	t.build(2*i+1, mid, hi, values)
+	t.nodes[i] = t.ops.Combine(t.nodes[2*i], t.nodes[2*i+1])
+}
+
+// Len returns the length of the sequence
+func (t *LazySegmentTree[T, F]) Len() int {
+	return t.n
+}
+
+// apply applies f to node i, which covers size values
+func (t *LazySegmentTree[T, F]) apply(i int, f F, size int) {
+	t.nodes[i] = t.ops.Apply(f, t.nodes[i], size)
+	if t.has[i] {
+		t.pending[i] = t.ops.Compose(f, t.pending[i])
+	} else {
+		t.pending[i], t.has[i] = f, true
+	}
+}
+
+// push moves the pending update of node i, which covers [lo, hi), to its
+// children
+func (t *LazySegmentTree[T, F]) push(i, lo, hi int) {
+	if !t.has[i] {
+		return
+	}
+	mid := (lo + hi) / 2
+	t.apply(2*i, t.pending[i], mid-lo)
+	t.apply(2*i+1, t.pending[i], hi-mid)
+	var zero F
+	t.pending[i], t.has[i] = zero, false
+}
+
+// Query returns the combination of the values in [lo, hi), or the identity
+// if the range is empty. It panics if the range is out of bounds.
+func (t *LazySegmentTree[T, F]) Query(lo, hi int) T {
+	checkRange(lo, hi, t.n)
+	if lo == hi {
+		return t.ops.Identity
+	}
+	return t.query(1, 0, t.n, lo, hi)
+}
+
+// query combines the values of [lo, hi) within node i, which covers
+// [nodeLo, nodeHi)
+func (t *LazySegmentTree[T, F]) query(i, nodeLo, nodeHi, lo, hi int) T {
+	if hi <= nodeLo || nodeHi <= lo {
+		return t.ops.Identity
+	}
+	if lo <= nodeLo && nodeHi <= hi {
+		return t.nodes[i]
+	}
+	t.push(i, nodeLo, nodeHi)
+	mid := (nodeLo + nodeHi) / 2
+	return t.ops.Combine(t.query(2*i, nodeLo, mid, lo, hi), t.query(2*i+1, mid, nodeHi, lo, hi))
+}
+
+// Update applies f to every value in [lo, hi). It panics if the range is
+// out of bounds.
+func (t *LazySegmentTree[T, F]) Update(lo, hi int, f F) {
+	checkRange(lo, hi, t.n)
+	if lo < hi {
+		t.update(1, 0, t.n, lo, hi, f)
+	}
+}
+
+// update applies f to the values of [lo, hi) within node i, which covers
+// [nodeLo, nodeHi)
+func (t *LazySegmentTree[T, F]) update(i, nodeLo, nodeHi, lo, hi int, f F) {
+	if hi <= nodeLo || nodeHi <= lo {
+		return
+	}
+	if lo <= nodeLo && nodeHi <= hi {
+		t.apply(i, f, nodeHi-nodeLo)
+		return
+	}
+	t.push(i, nodeLo, nodeHi)
+	mid := (nodeLo + nodeHi) / 2
+	t.update(2*i, nodeLo, mid, lo, hi, f)
+	t.update(2*i+1, mid, nodeHi, lo, hi, f)
+	t.nodes[i] = t.ops.Combine(t.nodes[2*i], t.nodes[2*i+1])
+}
+
+// Get returns the value at index i
+func (t *LazySegmentTree[T, F]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.query(1, 0, t.n, i, i+1)
+}
+
+// Set replaces the value at index i in O(log n) time
+func (t *LazySegmentTree[T, F]) Set(i int, v T) {
+	checkIndex(i, t.n)
+	t.set(1, 0, t.n, i, v)
+}
+
+// set replaces the value at index within node i, which covers [lo, hi)
+func (t *LazySegmentTree[T, F]) set(i, lo, hi, index int, v T) {
+	if hi-lo == 1 {
+		t.nodes[i] = v
+		return
+	}
+	t.push(i, lo, hi)
+	mid := (lo + hi) / 2
+	if index < mid {
+		t.set(2*i, lo, mid, index, v)
+	} else {
+		t.set(2*i+1, mid, hi, index, v)
+	}
+	t.nodes[i] = t.ops.Combine(t.nodes[2*i], t.nodes[2*i+1])
+}
@@ -0,0 +1,268 @@
+package rangequery
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+	"testing"
+)
+
+// randomRange returns a random range [lo, hi) of a sequence of length n,
+// which may be empty
+func randomRange(rng *rand.Rand, n int) (int, int) {
+	lo := rng.Intn(n + 1)
+	return lo, lo + rng.Intn(n-lo+1)
+}
+
+// TestSegmentTree compares a SegmentTree of strings, whose combine function
+// (concatenation) is not commutative, with a brute-force slice for many
+// sequence lengths
+func TestSegmentTree(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	concat := func(a, b string) string { return a + b }
+	for n := range 40 {
+		want := make([]string, n)
+		for i := range want {
+			want[i] = string(rune('a' + rng.Intn(26)))
+		}
+		st := NewSegmentTree(want, concat, "")
+		want = append([]string(nil), want...) // the tree must keep its own copy
+		for range 200 {
+			if n > 0 && rng.Intn(2) == 0 {
+				i := rng.Intn(n)
+				want[i] = string(rune('a' + rng.Intn(26)))
+				st.Set(i, want[i])
+			}
+			lo, hi := randomRange(rng, n)
+			if got := st.Query(lo, hi); got != strings.Join(want[lo:hi], "") {
+				t.Fatalf("n=%d: Query(%d, %d) = %q; want %q", n, lo, hi, got, strings.Join(want[lo:hi], ""))
+			}
+			if n > 0 {
+				i := rng.Intn(n)
+				if got := st.Get(i); got != want[i] {
+					t.Fatalf("n=%d: Get(%d) = %q; want %q", n, i, got, want[i])
+				}
+			}
+		}
+	}
+}
+
+// lazyCase is a kind of range update with its brute-force equivalent
+type lazyCase struct {
+	name   string
+	ops    LazyOps[int, int]
+	update func(v, f int) int
+	query  func(values []int) int
+}
+
+func TestLazySegmentTree(t *testing.T) {
+	minOf := func(values []int) int {
+		m := math.MaxInt
+		for _, v := range values {
+			m = min(m, v)
+		}
+		return m
+	}
+	for _, tc := range []lazyCase{
+		{
+			name:   "AddSum",
+			ops:    AddSum[int](),
+			update: func(v, f int) int { return v + f },
+			query: func(values []int) int {
+				sum := 0
+				for _, v := range values {
+					sum += v
+				}
+				return sum
+			},
+		},
+		{
+			name: "AddMin",
+			ops: LazyOps[int, int]{
+				Combine:  func(a, b int) int { return min(a, b) },
+				Identity: math.MaxInt,
+				Apply:    func(f, v, _ int) int { return v + f },
+				Compose:  func(f, g int) int { return f + g },
+			},
+			update: func(v, f int) int { return v + f },
+			query:  minOf,
+		},
+		{
+			// Assignment is not commutative: the later update wins
+			name: "AssignMin",
+			ops: LazyOps[int, int]{
+				Combine:  func(a, b int) int { return min(a, b) },
+				Identity: math.MaxInt,
+				Apply:    func(f, _, _ int) int { return f },
+				Compose:  func(f, _ int) int { return f },
+			},
+			update: func(_, f int) int { return f },
+			query:  minOf,
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			for n := range 40 {
+				want := make([]int, n)
+				for i := range want {
+					want[i] = rng.Intn(100)
+				}
+				lt := NewLazySegmentTree(want, tc.ops)
+				for range 200 {
+					lo, hi := randomRange(rng, n)
+					switch rng.Intn(4) {
+					case 0:
+						f := rng.Intn(21) - 10
+						lt.Update(lo, hi, f)
+						for i := lo; i < hi; i++ {
+							want[i] = tc.update(want[i], f)
+						}
+					case 1:
+						if n > 0 {
+							i, v := rng.Intn(n), rng.Intn(100)
+							lt.Set(i, v)
+							want[i] = v
+						}
+					case 2:
+						if n > 0 {
+							if i := rng.Intn(n); lt.Get(i) != want[i] {
+								t.Fatalf("n=%d: Get(%d) = %d; want %d", n, i, lt.Get(i), want[i])
+							}
+						}
+					default:
+						if got := lt.Query(lo, hi); got != tc.query(want[lo:hi]) {
+							t.Fatalf("n=%d: Query(%d, %d) = %d; want %d", n, lo, hi, got, tc.query(want[lo:hi]))
+						}
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestFenwick(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	for n := range 40 {
+		want := make([]int, n)
+		for i := range want {
+			want[i] = rng.Intn(10)
+		}
+		f := FenwickFrom(want)
+		if n > 0 {
+			// Building by adding must give the same tree
+			added := NewFenwick[int](n)
+			for i, v := range want {
+				added.Add(i, v)
+			}
+			for i := range n {
+				if added.tree[i] != f.tree[i] {
+					t.Fatalf("n=%d: FenwickFrom and Add disagree at %d", n, i)
+				}
+			}
+		}
+		for range 200 {
+			lo, hi := randomRange(rng, n)
+			switch rng.Intn(4) {
+			case 0:
+				if n > 0 {
+					i, delta := rng.Intn(n), rng.Intn(10)
+					f.Add(i, delta)
+					want[i] += delta
+				}
+			case 1:
+				if n > 0 {
+					i, v := rng.Intn(n), rng.Intn(10)
+					f.Set(i, v)
+					want[i] = v
+				}
+			case 2:
+				// Search finds the first index where the prefix sum
+				// reaches the target
+				target := rng.Intn(5*n + 1)
+				wantIndex, sum := n, 0
+				for i, v := range want {
+					if sum += v; sum >= target {
+						wantIndex = i
+						break
+					}
+				}
+				if got := f.Search(target); got != wantIndex {
+					t.Fatalf("n=%d: Search(%d) = %d; want %d", n, target, got, wantIndex)
+				}
+			default:
+				sum := 0
+				for _, v := range want[lo:hi] {
+					sum += v
+				}
+				if got := f.RangeSum(lo, hi); got != sum {
+					t.Fatalf("n=%d: RangeSum(%d, %d) = %d; want %d", n, lo, hi, got, sum)
+				}
+			}
+		}
+	}
+}
+
+func TestOutOfBounds(t *testing.T) {
+	st := NewSegmentTree([]int{1, 2, 3}, func(a, b int) int { return a + b }, 0)
+	lt := NewLazySegmentTree([]int{1, 2, 3}, AddSum[int]())
+	f := FenwickFrom([]int{1, 2, 3})
+	for name, call := range map[string]func(){
+		"SegmentTree.Query": func() { st.Query(2, 4) },
+		"SegmentTree.Set":   func() { st.Set(3, 0) },
+		"Lazy.Update":       func() { lt.Update(2, 1, 0) },
+		"Lazy.Get":          func() { lt.Get(-1) },
+		"Fenwick.Add":       func() { f.Add(3, 1) },
+		"Fenwick.PrefixSum": func() { f.PrefixSum(4) },
+	} {
+		func() {
+			defer func() {
+				if recover() == nil {
+					t.Errorf("%s did not panic", name)
+				}
+			}()
+			call()
+		}()
+	}
+}
+
+func BenchmarkRangeSum(b *testing.B) {
+	const n = 1 << 16
+	values := make([]int, n)
+	for i := range values {
+		values[i] = i
+	}
+	st := NewSegmentTree(values, func(a, b int) int { return a + b }, 0)
+	lt := NewLazySegmentTree(values, AddSum[int]())
+	f := FenwickFrom(values)
+	rng := rand.New(rand.NewSource(1))
+	ranges := make([][2]int, 1024)
+	for i := range ranges {
+		ranges[i][0], ranges[i][1] = randomRange(rng, n)
+	}
+	b.Run("Scan", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r, sum := ranges[i%len(ranges)], 0
+			for _, v := range values[r[0]:r[1]] {
+				sum += v
+			}
+		}
+	})
+	b.Run("SegmentTree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r := ranges[i%len(ranges)]
+			st.Query(r[0], r[1])
+		}
+	})
+	b.Run("LazySegmentTree", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r := ranges[i%len(ranges)]
+			lt.Query(r[0], r[1])
+		}
+	})
+	b.Run("Fenwick", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			r := ranges[i%len(ranges)]
+			f.RangeSum(r[0], r[1])
+		}
+	})
+}
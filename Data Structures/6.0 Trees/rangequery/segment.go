@@ -0,0 +1,92 @@
+// Package rangequery implements trees that answer queries over a range of
+// a sequence in O(log n) time: segment trees for any associative combine
+// function, optionally with lazy range updates, and Fenwick trees for
+// prefix sums.
+package rangequery
+
+import "fmt"
+
+// checkRange panics unless [lo, hi) is a valid range of a sequence of
+// length n, like slicing does
+func checkRange(lo, hi, n int) {
+	if lo < 0 || hi > n || lo > hi {
+		panic(fmt.Sprintf("rangequery: range [%d, %d) out of bounds for length %d", lo, hi, n))
+	}
+}
+
+// checkIndex panics unless i is a valid index of a sequence of length n
+func checkIndex(i, n int) {
+	if i < 0 || i >= n {
+		panic(fmt.Sprintf("rangequery: index %d out of range for length %d", i, n))
+	}
+}
+
+// SegmentTree stores a sequence and answers combine(v[lo], ..., v[hi-1])
+// for any range in O(log n) time. combine must be associative, and identity
+// must satisfy combine(identity, x) == combine(x, identity) == x: for
+// example + and 0 for sums, or min and the largest value for minimums.
+// combine does not need to be commutative.
+//
+// The tree is stored bottom-up in one slice of 2n values: the sequence
+// fills positions n to 2n-1 and position i > 0 holds the combination of
+// positions 2i and 2i+1.
+type SegmentTree[T any] struct {
+	n        int
+	nodes    []T
+	combine  func(a, b T) T
+	identity T
+}
+
+// NewSegmentTree creates a segment tree over a copy of values in O(n) time
+func NewSegmentTree[T any](values []T, combine func(a, b T) T, identity T) *SegmentTree[T] {
+	n := len(values)
+	t := &SegmentTree[T]{n: n, nodes: make([]T, 2*n), combine: combine, identity: identity}
+	copy(t.nodes[n:], values)
+	for i := n - 1; i > 0; i-- {
+		t.nodes[i] = combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+	return t
+}
+
+// Len returns the length of the sequence
+func (t *SegmentTree[T]) Len() int {
+	return t.n
+}
+
+// Get returns the value at index i
+func (t *SegmentTree[T]) Get(i int) T {
+	checkIndex(i, t.n)
+	return t.nodes[t.n+i]
+}
+
+// Set replaces the value at index i in O(log n) time
+func (t *SegmentTree[T]) Set(i int, v T) {
+	checkIndex(i, t.n)
+	i += t.n
+	t.nodes[i] = v
+	for i > 1 {
+		i /= 2
+		t.nodes[i] = t.combine(t.nodes[2*i], t.nodes[2*i+1])
+	}
+}
+
+// Query returns the combination of the values in [lo, hi), or the identity
+// if the range is empty. It panics if the range is out of bounds.
+func (t *SegmentTree[T]) Query(lo, hi int) T {
+	checkRange(lo, hi, t.n)
+	// Climb from both ends, collecting the nodes that lie wholly inside
+	// the range. The left and right results are kept apart so the values
+	// are combined in order.
+	left, right := t.identity, t.identity
+	for lo, hi = lo+t.n, hi+t.n; lo < hi; lo, hi = lo/2, hi/2 {
+		if lo%2 == 1 {
+			left = t.combine(left, t.nodes[lo])
+			lo++
+		}
+		if hi%2 == 1 {
+			hi--
+			right = t.combine(t.nodes[hi], right)
+		}
+	}
+	return t.combine(left, right)
+}
@@ -0,0 +1,250 @@
+package skiplist
+
+import (
+	"cmp"
+	"iter"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// cnode is a node of the concurrent skip list. Links and the value are
+// atomic so readers can follow them without taking mu. A node is logically
+// present once fullyLinked is set and until marked is set.
+type cnode[K cmp.Ordered, V any] struct {
+	key         K
+	value       atomic.Pointer[V]
+	next        []atomic.Pointer[cnode[K, V]]
+	mu          sync.Mutex
+	marked      atomic.Bool // logically deleted
+	fullyLinked atomic.Bool // linked on every level
+}
+
+// present reports whether the node belongs to the map
+func (n *cnode[K, V]) present() bool {
+	return n.fullyLinked.Load() && !n.marked.Load()
+}
+
+// ConcurrentSkipList is a sorted map that is safe for use by multiple
+// goroutines. It is the lazy skip list of Herlihy, Lev, Luchangco and Shavit:
+//
+//   - Get, All and Range take no locks. They follow the atomic links and
+//     skip nodes that are not yet fully linked or already marked as deleted.
+//   - Put and Delete lock only the predecessors of the key on each level
+//     (and the node itself), then check that nothing changed while they
+//     were searching and retry if it did. Writers to different parts of the
+//     list do not block each other.
+//
+// Iterators are weakly consistent: they never yield a key twice or out of
+// order, and may or may not see updates made during the iteration.
+// Use NewConcurrent to create a ConcurrentSkipList.
+type ConcurrentSkipList[K cmp.Ordered, V any] struct {
+	head *cnode[K, V] // sentinel that is never marked
+	len  atomic.Int64
+}
+
+// NewConcurrent creates an empty concurrent skip list
+func NewConcurrent[K cmp.Ordered, V any]() *ConcurrentSkipList[K, V] {
+	head := &cnode[K, V]{next: make([]atomic.Pointer[cnode[K, V]], maxLevel)}
+	head.fullyLinked.Store(true)
+	return &ConcurrentSkipList[K, V]{head: head}
+}
+
+// Len returns the number of keys in the skip list
+func (l *ConcurrentSkipList[K, V]) Len() int {
+	return int(l.len.Load())
+}
+
+// find fills preds and succs with the nodes around key on every level and
+// returns the highest level on which a node with key was found, or -1
+func (l *ConcurrentSkipList[K, V]) find(key K, preds, succs *[maxLevel]*cnode[K, V]) int {
+	found := -1
+	pred := l.head
+	for i := maxLevel - 1; i >= 0; i-- {
+		curr := pred.next[i].Load()
+		for curr != nil && cmp.Less(curr.key, key) {
+			pred = curr
+			curr = pred.next[i].Load()
+		}
+		if found == -1 && curr != nil && curr.key == key {
+			found = i
+		}
+		preds[i] = pred
+		succs[i] = curr
+	}
+	return found
+}
+
+// seek returns the first node with a key greater than or equal to key
+func (l *ConcurrentSkipList[K, V]) seek(key K) *cnode[K, V] {
+	pred := l.head
+	var curr *cnode[K, V]
+	for i := maxLevel - 1; i >= 0; i-- {
+		curr = pred.next[i].Load()
+		for curr != nil && cmp.Less(curr.key, key) {
+			pred = curr
+			curr = pred.next[i].Load()
+		}
+	}
+	return curr
+}
+
+// Get returns the value stored for a key. It never blocks.
+func (l *ConcurrentSkipList[K, V]) Get(key K) (V, bool) {
+	if n := l.seek(key); n != nil && n.key == key && n.present() {
+		return *n.value.Load(), true
+	}
+	var zero V
+	return zero, false
+}
+
+// Put sets the value for a key, replacing any existing value
+func (l *ConcurrentSkipList[K, V]) Put(key K, value V) {
+	level := randomLevel()
+	var preds, succs [maxLevel]*cnode[K, V]
+	for {
+		if found := l.find(key, &preds, &succs); found != -1 {
+			if l.replace(succs[found], &value) {
+				return
+			}
+			// The node is being deleted; search again once it is gone
+			runtime.Gosched()
+			continue
+		}
+
+		locked, valid := lockPreds(&preds, &succs, level, true)
+		if !valid {
+			unlockAll(locked)
+			continue
+		}
+
+		n := &cnode[K, V]{key: key, next: make([]atomic.Pointer[cnode[K, V]], level)}
+		n.value.Store(&value)
+		for i := range level {
+			n.next[i].Store(succs[i])
+		}
+		for i := range level {
+			preds[i].next[i].Store(n)
+		}
+		n.fullyLinked.Store(true)
+		unlockAll(locked)
+		l.len.Add(1)
+		return
+	}
+}
+
+// replace stores value in an existing node and reports false if the node
+// was deleted first
+func (l *ConcurrentSkipList[K, V]) replace(n *cnode[K, V], value *V) bool {
+	for !n.fullyLinked.Load() {
+		if n.marked.Load() {
+			return false
+		}
+		runtime.Gosched()
+	}
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.marked.Load() {
+		return false
+	}
+	n.value.Store(value)
+	return true
+}
+
+// Delete removes a key and reports whether it was present
+func (l *ConcurrentSkipList[K, V]) Delete(key K) bool {
+	var preds, succs [maxLevel]*cnode[K, V]
+	var victim *cnode[K, V]
+	for {
+		found := l.find(key, &preds, &succs)
+		if victim == nil {
+			if found == -1 {
+				return false
+			}
+			candidate := succs[found]
+			// Only a node found on its top level has been seen through all
+			// of its predecessors
+			if !candidate.present() || found != len(candidate.next)-1 {
+				return false
+			}
+			candidate.mu.Lock()
+			if candidate.marked.Load() {
+				candidate.mu.Unlock()
+				return false
+			}
+			// Marking is the moment the key leaves the map; unlinking follows
+			candidate.marked.Store(true)
+			victim = candidate
+		}
+
+		for i := range victim.next {
+			succs[i] = victim
+		}
+		locked, valid := lockPreds(&preds, &succs, len(victim.next), false)
+		if !valid {
+			unlockAll(locked)
+			continue
+		}
+		for i := len(victim.next) - 1; i >= 0; i-- {
+			preds[i].next[i].Store(victim.next[i].Load())
+		}
+		victim.mu.Unlock()
+		unlockAll(locked)
+		l.len.Add(-1)
+		return true
+	}
+}
+
+// lockPreds locks the distinct predecessors on levels 0 to level-1, bottom
+// up, and checks that nothing changed since the search: each predecessor is
+// not deleted and still links to its successor, which for an insert must not
+// be deleted either. Locks are always taken from larger keys to smaller ones,
+// so writers cannot deadlock. The caller unlocks the returned nodes.
+func lockPreds[K cmp.Ordered, V any](preds, succs *[maxLevel]*cnode[K, V], level int, insert bool) ([]*cnode[K, V], bool) {
+	var locked []*cnode[K, V]
+	for i := range level {
+		pred, succ := preds[i], succs[i]
+		if len(locked) == 0 || locked[len(locked)-1] != pred {
+			pred.mu.Lock()
+			locked = append(locked, pred)
+		}
+		if pred.marked.Load() || pred.next[i].Load() != succ {
+			return locked, false
+		}
+		if insert && succ != nil && succ.marked.Load() {
+			return locked, false
+		}
+	}
+	return locked, true
+}
+
+func unlockAll[K cmp.Ordered, V any](nodes []*cnode[K, V]) {
+	for _, n := range nodes {
+		n.mu.Unlock()
+	}
+}
+
+// All returns an iterator over all keys and values in ascending key order.
+// It takes no locks and may run while other goroutines modify the list.
+func (l *ConcurrentSkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := l.head.next[0].Load(); n != nil; n = n.next[0].Load() {
+			if n.present() && !yield(n.key, *n.value.Load()) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the keys k with lo <= k <= hi and their
+// values, in ascending key order. It takes no locks and may run while other
+// goroutines modify the list.
+func (l *ConcurrentSkipList[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for n := l.seek(lo); n != nil && cmp.Compare(n.key, hi) <= 0; n = n.next[0].Load() {
+			if n.present() && !yield(n.key, *n.value.Load()) {
+				return
+			}
+		}
+	}
+}
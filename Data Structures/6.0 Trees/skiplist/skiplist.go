@@ -0,0 +1,156 @@
+// Package skiplist implements sorted maps on top of skip lists: a
+// single-goroutine SkipList and a ConcurrentSkipList whose readers never lock.
+package skiplist
+
+import (
+	"cmp"
+	"iter"
+	"math/bits"
+	"math/rand/v2"
+)
+
+// maxLevel bounds the number of levels. With a promotion probability of 1/4
+// this is enough for about 4^24 keys.
+const maxLevel = 24
+
+// randomLevel returns a level between 1 and maxLevel, where each level is a
+// quarter as likely as the one below it
+func randomLevel() int {
+	return min(1+bits.TrailingZeros64(rand.Uint64())/2, maxLevel)
+}
+
+// node is an element of the skip list. next[i] is the following node on
+// level i, so a node with a level of n appears on levels 0 to n-1.
+type node[K cmp.Ordered, V any] struct {
+	key   K
+	value V
+	next  []*node[K, V]
+}
+
+// SkipList is a map whose keys are kept in sorted order. It is a sorted
+// linked list (level 0) with express lanes above it: every node of one level
+// is also on the next level with probability 1/4. Searches start on the
+// highest level and drop down a level whenever the next key is too large, so
+// Put, Get and Delete take O(log n) expected time without any rebalancing.
+// The zero value is an empty map ready to use.
+type SkipList[K cmp.Ordered, V any] struct {
+	head  node[K, V] // sentinel; only next is used
+	level int        // number of levels in use
+	len   int
+}
+
+// New creates an empty skip list
+func New[K cmp.Ordered, V any]() *SkipList[K, V] {
+	return &SkipList[K, V]{}
+}
+
+// Len returns the number of keys in the skip list
+func (l *SkipList[K, V]) Len() int {
+	return l.len
+}
+
+// Level returns the number of levels in use
+func (l *SkipList[K, V]) Level() int {
+	return l.level
+}
+
+// findPreds fills preds with the last node before key on each level in use
+// and returns the node at or after key on level 0
+func (l *SkipList[K, V]) findPreds(key K, preds *[maxLevel]*node[K, V]) *node[K, V] {
+	x := &l.head
+	for i := l.level - 1; i >= 0; i-- {
+		for x.next[i] != nil && cmp.Less(x.next[i].key, key) {
+			x = x.next[i]
+		}
+		preds[i] = x
+	}
+	if l.level == 0 {
+		return nil
+	}
+	return x.next[0]
+}
+
+// seek returns the first node with a key greater than or equal to key
+func (l *SkipList[K, V]) seek(key K) *node[K, V] {
+	var preds [maxLevel]*node[K, V]
+	return l.findPreds(key, &preds)
+}
+
+// Put sets the value for a key, replacing any existing value
+func (l *SkipList[K, V]) Put(key K, value V) {
+	if l.head.next == nil {
+		l.head.next = make([]*node[K, V], maxLevel)
+	}
+	var preds [maxLevel]*node[K, V]
+	if x := l.findPreds(key, &preds); x != nil && x.key == key {
+		x.value = value
+		return
+	}
+
+	level := randomLevel()
+	for i := l.level; i < level; i++ {
+		preds[i] = &l.head
+	}
+	l.level = max(l.level, level)
+
+	n := &node[K, V]{key: key, value: value, next: make([]*node[K, V], level)}
+	for i := range level {
+		n.next[i] = preds[i].next[i]
+		preds[i].next[i] = n
+	}
+	l.len++
+}
+
+// Get returns the value stored for a key
+func (l *SkipList[K, V]) Get(key K) (V, bool) {
+	if x := l.seek(key); x != nil && x.key == key {
+		return x.value, true
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes a key and reports whether it was present
+func (l *SkipList[K, V]) Delete(key K) bool {
+	var preds [maxLevel]*node[K, V]
+	x := l.findPreds(key, &preds)
+	if x == nil || x.key != key {
+		return false
+	}
+	for i := range x.next {
+		preds[i].next[i] = x.next[i]
+	}
+	for l.level > 0 && l.head.next[l.level-1] == nil {
+		l.level--
+	}
+	l.len--
+	return true
+}
+
+// All returns an iterator over all keys and values in ascending key order.
+// The skip list must not be modified during iteration.
+func (l *SkipList[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		if l.level == 0 {
+			return
+		}
+		for x := l.head.next[0]; x != nil; x = x.next[0] {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
+
+// Range returns an iterator over the keys k with lo <= k <= hi and their
+// values, in ascending key order. The skip list must not be modified during
+// iteration.
+func (l *SkipList[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for x := l.seek(lo); x != nil && cmp.Compare(x.key, hi) <= 0; x = x.next[0] {
+			if !yield(x.key, x.value) {
+				return
+			}
+		}
+	}
+}
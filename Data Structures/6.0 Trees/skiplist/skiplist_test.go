@@ -0,0 +1,295 @@
+package skiplist
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"sync"
+	"testing"
+
+	"go-mastery/trees/tree"
+)
+
+// checkLevels verifies that every level is sorted and is a subsequence of
+// the level below it, and that no level above Level() is in use.
+func checkLevels[K cmp.Ordered, V any](t *testing.T, l *SkipList[K, V]) {
+	t.Helper()
+	if l.level == 0 {
+		return
+	}
+	below := map[*node[K, V]]bool{}
+	for i := range maxLevel {
+		if i >= l.level {
+			if l.head.next[i] != nil {
+				t.Fatalf("level %d is in use above Level() = %d", i, l.level)
+			}
+			continue
+		}
+		if l.head.next[i] == nil {
+			t.Fatalf("level %d is empty below Level() = %d", i, l.level)
+		}
+		on := map[*node[K, V]]bool{}
+		for x := l.head.next[i]; x != nil; x = x.next[i] {
+			if x.next[i] != nil && x.next[i].key <= x.key {
+				t.Fatalf("level %d: %v is followed by %v", i, x.key, x.next[i].key)
+			}
+			if i > 0 && !below[x] {
+				t.Fatalf("level %d: %v is missing from the level below", i, x.key)
+			}
+			on[x] = true
+		}
+		below = on
+	}
+}
+
+// TestAgainstOrderedMap applies random operations to a SkipList and to the
+// AVL OrderedMap and compares every query.
+func TestAgainstOrderedMap(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	var l SkipList[int, int] // the zero value is ready to use
+	want := tree.New[int, int]()
+
+	for i := range 20000 {
+		key := rng.Intn(2000)
+		switch rng.Intn(4) {
+		case 0:
+			if got, wantOK := l.Delete(key), want.Delete(key); got != wantOK {
+				t.Fatalf("Delete(%d) = %t; want %t", key, got, wantOK)
+			}
+		case 1:
+			lo := rng.Intn(2000)
+			hi := lo + rng.Intn(100)
+			got := slices.Collect(keys(l.Range(lo, hi)))
+			wantKeys := slices.Collect(keys(want.Range(lo, hi)))
+			if !slices.Equal(got, wantKeys) {
+				t.Fatalf("Range(%d, %d) = %v; want %v", lo, hi, got, wantKeys)
+			}
+		default:
+			l.Put(key, i)
+			want.Put(key, i)
+		}
+		value, ok := l.Get(key)
+		wantValue, wantOK := want.Get(key)
+		if value != wantValue || ok != wantOK {
+			t.Fatalf("Get(%d) = %d, %t; want %d, %t", key, value, ok, wantValue, wantOK)
+		}
+		if l.Len() != want.Len() {
+			t.Fatalf("Len() = %d; want %d", l.Len(), want.Len())
+		}
+		if i%1000 == 0 {
+			checkLevels(t, &l)
+		}
+	}
+	checkLevels(t, &l)
+
+	for key := range l.All() {
+		l.Delete(key)
+	}
+	if l.Len() != 0 || l.Level() != 0 {
+		t.Fatalf("after deleting all keys: Len() = %d, Level() = %d", l.Len(), l.Level())
+	}
+}
+
+func keys[K, V any](seq func(func(K, V) bool)) func(func(K) bool) {
+	return func(yield func(K) bool) {
+		for k := range seq {
+			if !yield(k) {
+				return
+			}
+		}
+	}
+}
+
+func TestConcurrentSequential(t *testing.T) {
+	rng := rand.New(rand.NewSource(2))
+	l := NewConcurrent[int, int]()
+	want := tree.New[int, int]()
+	for i := range 10000 {
+		key := rng.Intn(1000)
+		if rng.Intn(3) == 0 {
+			if got, wantOK := l.Delete(key), want.Delete(key); got != wantOK {
+				t.Fatalf("Delete(%d) = %t; want %t", key, got, wantOK)
+			}
+		} else {
+			l.Put(key, i)
+			want.Put(key, i)
+		}
+	}
+	if l.Len() != want.Len() {
+		t.Fatalf("Len() = %d; want %d", l.Len(), want.Len())
+	}
+	var got, wantPairs [][2]int
+	for k, v := range l.All() {
+		got = append(got, [2]int{k, v})
+	}
+	for k, v := range want.All() {
+		wantPairs = append(wantPairs, [2]int{k, v})
+	}
+	if !slices.Equal(got, wantPairs) {
+		t.Fatalf("All() disagrees with OrderedMap")
+	}
+	if got := slices.Collect(keys(l.Range(100, 110))); !slices.Equal(got, slices.Collect(keys(want.Range(100, 110)))) {
+		t.Fatalf("Range(100, 110) = %v", got)
+	}
+}
+
+// TestConcurrentWriters runs writers on interleaved keys while readers scan
+// the list. Run with -race.
+func TestConcurrentWriters(t *testing.T) {
+	const writers, perWriter = 8, 2000
+	l := NewConcurrent[int, int]()
+	done := make(chan struct{})
+
+	var readers sync.WaitGroup
+	for range 4 {
+		readers.Add(1)
+		go func() {
+			defer readers.Done()
+			for {
+				select {
+				case <-done:
+					return
+				default:
+				}
+				prev := -1
+				for k, v := range l.All() {
+					if k <= prev {
+						t.Errorf("All() yielded %d after %d", k, prev)
+						return
+					}
+					if v != k && v != -k {
+						t.Errorf("key %d has value %d", k, v)
+						return
+					}
+					prev = k
+				}
+			}
+		}()
+	}
+
+	var wg sync.WaitGroup
+	for w := range writers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Writer w owns the keys congruent to w, so the final state is known
+			for i := range perWriter {
+				key := i*writers + w
+				l.Put(key, key)
+				if i%2 == 1 {
+					l.Put(key, -key)
+				}
+				if i%3 == 0 {
+					if !l.Delete(key) {
+						t.Errorf("Delete(%d) = false", key)
+					}
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(done)
+	readers.Wait()
+
+	count := 0
+	for key := range writers * perWriter {
+		i := key / writers
+		value, ok := l.Get(key)
+		if i%3 == 0 {
+			if ok {
+				t.Fatalf("deleted key %d is present", key)
+			}
+			continue
+		}
+		count++
+		want := key
+		if i%2 == 1 {
+			want = -key
+		}
+		if !ok || value != want {
+			t.Fatalf("Get(%d) = %d, %t; want %d", key, value, ok, want)
+		}
+	}
+	if l.Len() != count {
+		t.Fatalf("Len() = %d; want %d", l.Len(), count)
+	}
+}
+
+// TestConcurrentSameKeys has all goroutines fight over a few keys.
+func TestConcurrentSameKeys(t *testing.T) {
+	l := NewConcurrent[int, int]()
+	var wg sync.WaitGroup
+	for g := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rng := rand.New(rand.NewSource(int64(g)))
+			for range 5000 {
+				key := rng.Intn(16)
+				if rng.Intn(2) == 0 {
+					l.Put(key, g)
+				} else {
+					l.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	count := 0
+	prev := -1
+	for k := range l.All() {
+		if k <= prev {
+			t.Fatalf("All() yielded %d after %d", k, prev)
+		}
+		prev = k
+		count++
+	}
+	if l.Len() != count {
+		t.Fatalf("Len() = %d but All() yields %d keys", l.Len(), count)
+	}
+}
+
+// BenchmarkReads compares lock-free reads with reads of an OrderedMap behind
+// a sync.RWMutex while one goroutine in sixteen writes.
+func BenchmarkReads(b *testing.B) {
+	const size = 1 << 16
+	b.Run("ConcurrentSkipList", func(b *testing.B) {
+		l := NewConcurrent[int, int]()
+		for i := range size {
+			l.Put(i, i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			rng := rand.New(rand.NewSource(rand.Int63()))
+			for i := 0; pb.Next(); i++ {
+				key := rng.Intn(size)
+				if i%16 == 0 {
+					l.Put(key, i)
+				} else {
+					l.Get(key)
+				}
+			}
+		})
+	})
+	b.Run("RWMutexOrderedMap", func(b *testing.B) {
+		var mu sync.RWMutex
+		m := tree.New[int, int]()
+		for i := range size {
+			m.Put(i, i)
+		}
+		b.RunParallel(func(pb *testing.PB) {
+			rng := rand.New(rand.NewSource(rand.Int63()))
+			for i := 0; pb.Next(); i++ {
+				key := rng.Intn(size)
+				if i%16 == 0 {
+					mu.Lock()
+					m.Put(key, i)
+					mu.Unlock()
+				} else {
+					mu.RLock()
+					m.Get(key)
+					mu.RUnlock()
+				}
+			}
+		})
+	})
+}
@@ -0,0 +1,298 @@
+// Package tree implements a sorted map on top of a self-balancing AVL tree.
+package tree
+
+import (
+	"cmp"
+	"iter"
+
+	"go-mastery/trees/internal/avl"
+)
+
+// node is a node of the AVL tree. height and size describe the subtree
+// rooted at the node and are kept up to date by every update.
+type node[K cmp.Ordered, V any] struct {
+	key    K
+	value  V
+	left   *node[K, V]
+	right  *node[K, V]
+	height int
+	size   int
+}
+
+// Left, Right, SetLeft, SetRight, Height and Update make *node an
+// avl.Node, so avl.Rebalance can rotate it
+
+func (n *node[K, V]) Left() *node[K, V]      { return n.left }
+func (n *node[K, V]) Right() *node[K, V]     { return n.right }
+func (n *node[K, V]) SetLeft(l *node[K, V])  { n.left = l }
+func (n *node[K, V]) SetRight(r *node[K, V]) { n.right = r }
+
+func (n *node[K, V]) Height() int {
+	if n == nil {
+		return 0
+	}
+	return n.height
+}
+
+func (n *node[K, V]) getSize() int {
+	if n == nil {
+		return 0
+	}
+	return n.size
+}
+
+// Update recomputes height and size from the children
+func (n *node[K, V]) Update() {
+	n.height = 1 + max(n.left.Height(), n.right.Height())
+	n.size = 1 + n.left.getSize() + n.right.getSize()
+}
+
+// OrderedMap is a map whose keys are kept in sorted order. It is backed by
+// an AVL tree, so the heights of the two subtrees of any node differ by at
+// most one and every operation takes O(log n) time, even when the keys
+// arrive already sorted. The zero value is an empty map ready to use.
+type OrderedMap[K cmp.Ordered, V any] struct {
+	root *node[K, V]
+}
+
+// New creates an empty ordered map
+func New[K cmp.Ordered, V any]() *OrderedMap[K, V] {
+	return &OrderedMap[K, V]{}
+}
+
+// Len returns the number of keys in the map
+func (m *OrderedMap[K, V]) Len() int {
+	return m.root.getSize()
+}
+
+// Height returns the height of the underlying tree
+func (m *OrderedMap[K, V]) Height() int {
+	return m.root.Height()
+}
+
+// Put sets the value for a key, replacing any existing value
+func (m *OrderedMap[K, V]) Put(key K, value V) {
+	m.root = put(m.root, key, value)
+}
+
+func put[K cmp.Ordered, V any](n *node[K, V], key K, value V) *node[K, V] {
+	if n == nil {
+		return &node[K, V]{key: key, value: value, height: 1, size: 1}
+	}
+	switch c := cmp.Compare(key, n.key); {
+	case c < 0:
+		n.left = put(n.left, key, value)
+	case c > 0:
+		n.right = put(n.right, key, value)
+	default:
+		n.value = value
+		return n
+	}
+	return avl.Rebalance(n)
+}
+
+// Get returns the value stored for a key
+func (m *OrderedMap[K, V]) Get(key K) (V, bool) {
+	n := m.root
+	for n != nil {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+	var zero V
+	return zero, false
+}
+
+// Delete removes a key and reports whether it was present
+func (m *OrderedMap[K, V]) Delete(key K) bool {
+	var deleted bool
+	m.root, deleted = remove(m.root, key)
+	return deleted
+}
+
+func remove[K cmp.Ordered, V any](n *node[K, V], key K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+	var deleted bool
+	switch c := cmp.Compare(key, n.key); {
+	case c < 0:
+		n.left, deleted = remove(n.left, key)
+	case c > 0:
+		n.right, deleted = remove(n.right, key)
+	default:
+		if n.left == nil {
+			return n.right, true
+		}
+		if n.right == nil {
+			return n.left, true
+		}
+		// Replace the node with its in-order successor
+		var successor *node[K, V]
+		n.right, successor = removeMin(n.right)
+		successor.left, successor.right = n.left, n.right
+		return avl.Rebalance(successor), true
+	}
+	if !deleted {
+		return n, false
+	}
+	return avl.Rebalance(n), true
+}
+
+// removeMin detaches the smallest node of a non-empty subtree and returns
+// the new subtree root together with the detached node
+func removeMin[K cmp.Ordered, V any](n *node[K, V]) (*node[K, V], *node[K, V]) {
+	if n.left == nil {
+		return n.right, n
+	}
+	var smallest *node[K, V]
+	n.left, smallest = removeMin(n.left)
+	return avl.Rebalance(n), smallest
+}
+
+// Min returns the smallest key and its value
+func (m *OrderedMap[K, V]) Min() (K, V, bool) {
+	n := m.root
+	if n == nil {
+		return entry[K, V](nil)
+	}
+	for n.left != nil {
+		n = n.left
+	}
+	return entry(n)
+}
+
+// Max returns the largest key and its value
+func (m *OrderedMap[K, V]) Max() (K, V, bool) {
+	n := m.root
+	if n == nil {
+		return entry[K, V](nil)
+	}
+	for n.right != nil {
+		n = n.right
+	}
+	return entry(n)
+}
+
+// Floor returns the largest key less than or equal to key
+func (m *OrderedMap[K, V]) Floor(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			best, n = n, n.right
+		default:
+			return entry(n)
+		}
+	}
+	return entry(best)
+}
+
+// Ceiling returns the smallest key greater than or equal to key
+func (m *OrderedMap[K, V]) Ceiling(key K) (K, V, bool) {
+	var best *node[K, V]
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			best, n = n, n.left
+		case c > 0:
+			n = n.right
+		default:
+			return entry(n)
+		}
+	}
+	return entry(best)
+}
+
+// entry unpacks a node, reporting false for nil
+func entry[K cmp.Ordered, V any](n *node[K, V]) (K, V, bool) {
+	if n == nil {
+		var key K
+		var value V
+		return key, value, false
+	}
+	return n.key, n.value, true
+}
+
+// Rank returns the number of keys strictly less than key
+func (m *OrderedMap[K, V]) Rank(key K) int {
+	rank := 0
+	for n := m.root; n != nil; {
+		switch c := cmp.Compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			rank += n.left.getSize() + 1
+			n = n.right
+		default:
+			return rank + n.left.getSize()
+		}
+	}
+	return rank
+}
+
+// Select returns the key with the given rank, that is the i-th smallest key
+// counting from zero. It reports false if i is out of range.
+func (m *OrderedMap[K, V]) Select(i int) (K, V, bool) {
+	if i < 0 || i >= m.Len() {
+		return entry[K, V](nil)
+	}
+	n := m.root
+	for {
+		leftSize := n.left.getSize()
+		switch {
+		case i < leftSize:
+			n = n.left
+		case i > leftSize:
+			i -= leftSize + 1
+			n = n.right
+		default:
+			return entry(n)
+		}
+	}
+}
+
+// All returns an iterator over all keys and values in ascending key order.
+// The map must not be modified during iteration.
+func (m *OrderedMap[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		inOrder(m.root, yield)
+	}
+}
+
+func inOrder[K cmp.Ordered, V any](n *node[K, V], yield func(K, V) bool) bool {
+	return n == nil ||
+		inOrder(n.left, yield) && yield(n.key, n.value) && inOrder(n.right, yield)
+}
+
+// Range returns an iterator over the keys k with lo <= k <= hi and their
+// values, in ascending key order. Subtrees outside the range are skipped.
+// The map must not be modified during iteration.
+func (m *OrderedMap[K, V]) Range(lo, hi K) iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		inRange(m.root, lo, hi, yield)
+	}
+}
+
+func inRange[K cmp.Ordered, V any](n *node[K, V], lo, hi K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+	if cmp.Less(lo, n.key) && !inRange(n.left, lo, hi, yield) {
+		return false
+	}
+	if cmp.Compare(lo, n.key) <= 0 && cmp.Compare(n.key, hi) <= 0 && !yield(n.key, n.value) {
+		return false
+	}
+	if cmp.Less(n.key, hi) {
+		return inRange(n.right, lo, hi, yield)
+	}
+	return true
+}
@@ -0,0 +1,172 @@
+package tree
+
+import (
+	"cmp"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// checkInvariants verifies that keys are in BST order, that heights and
+// sizes are correct, and that every node is balanced.
+func checkInvariants[K cmp.Ordered, V any](t *testing.T, m *OrderedMap[K, V]) {
+	t.Helper()
+	var walk func(n *node[K, V], lo, hi *K) (height, size int)
+	walk = func(n *node[K, V], lo, hi *K) (int, int) {
+		if n == nil {
+			return 0, 0
+		}
+		if (lo != nil && n.key <= *lo) || (hi != nil && n.key >= *hi) {
+			t.Fatalf("key %v is out of BST order", n.key)
+		}
+		lh, ls := walk(n.left, lo, &n.key)
+		rh, rs := walk(n.right, &n.key, hi)
+		if lh-rh > 1 || rh-lh > 1 {
+			t.Fatalf("node %v is unbalanced: left height %d, right height %d", n.key, lh, rh)
+		}
+		height, size := 1+max(lh, rh), 1+ls+rs
+		if n.height != height || n.size != size {
+			t.Fatalf("node %v caches height %d size %d; want %d %d", n.key, n.height, n.size, height, size)
+		}
+		return height, size
+	}
+	walk(m.root, nil, nil)
+}
+
+// TestSortedInsertsStayBalanced covers the case that turns the plain BST
+// into a linked list.
+func TestSortedInsertsStayBalanced(t *testing.T) {
+	m := New[int, int]()
+	const n = 1 << 16
+	for i := range n {
+		m.Put(i, i)
+	}
+	checkInvariants(t, m)
+	// An AVL tree with n nodes is at most about 1.44 log2(n) high.
+	if m.Height() > 24 {
+		t.Fatalf("Height() = %d for %d ascending keys", m.Height(), n)
+	}
+	for i := n - 1; i >= 0; i -= 2 {
+		m.Delete(i)
+	}
+	checkInvariants(t, m)
+	if m.Len() != n/2 {
+		t.Fatalf("Len() = %d; want %d", m.Len(), n/2)
+	}
+}
+
+// TestAgainstSortedSlice applies random operations to an OrderedMap and to a
+// sorted slice of keys and compares every query.
+func TestAgainstSortedSlice(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := New[int, string]()
+	var keys []int
+	for step := range 5000 {
+		key := rng.Intn(1000)
+		i, found := slices.BinarySearch(keys, key)
+		if rng.Intn(3) == 0 {
+			if m.Delete(key) != found {
+				t.Fatalf("Delete(%d) = %t; want %t", key, !found, found)
+			}
+			if found {
+				keys = slices.Delete(keys, i, i+1)
+			}
+		} else {
+			m.Put(key, "v")
+			if !found {
+				keys = slices.Insert(keys, i, key)
+			}
+		}
+		if step%500 == 0 {
+			checkInvariants(t, m)
+		}
+	}
+	checkInvariants(t, m)
+
+	var got []int
+	for k := range m.All() {
+		got = append(got, k)
+	}
+	if !slices.Equal(got, keys) {
+		t.Fatalf("All() returned %d keys out of order or missing", len(got))
+	}
+	if k, _, _ := m.Min(); k != keys[0] {
+		t.Fatalf("Min() = %d; want %d", k, keys[0])
+	}
+	if k, _, _ := m.Max(); k != keys[len(keys)-1] {
+		t.Fatalf("Max() = %d; want %d", k, keys[len(keys)-1])
+	}
+
+	for probe := -1; probe <= 1001; probe++ {
+		i, found := slices.BinarySearch(keys, probe)
+		if rank := m.Rank(probe); rank != i {
+			t.Fatalf("Rank(%d) = %d; want %d", probe, rank, i)
+		}
+		floor, _, ok := m.Floor(probe)
+		switch {
+		case found && (!ok || floor != probe):
+			t.Fatalf("Floor(%d) = %d, %t; want the key itself", probe, floor, ok)
+		case !found && i == 0 && ok:
+			t.Fatalf("Floor(%d) = %d; want none", probe, floor)
+		case !found && i > 0 && floor != keys[i-1]:
+			t.Fatalf("Floor(%d) = %d; want %d", probe, floor, keys[i-1])
+		}
+		ceiling, _, ok := m.Ceiling(probe)
+		switch {
+		case i == len(keys) && ok:
+			t.Fatalf("Ceiling(%d) = %d; want none", probe, ceiling)
+		case i < len(keys) && ceiling != keys[i]:
+			t.Fatalf("Ceiling(%d) = %d; want %d", probe, ceiling, keys[i])
+		}
+	}
+
+	for i, want := range keys {
+		if k, _, ok := m.Select(i); !ok || k != want {
+			t.Fatalf("Select(%d) = %d, %t; want %d", i, k, ok, want)
+		}
+	}
+	if _, _, ok := m.Select(len(keys)); ok {
+		t.Fatalf("Select(Len()) should report false")
+	}
+
+	for _, bounds := range [][2]int{{100, 200}, {-5, 3}, {990, 2000}, {500, 500}, {7, 6}} {
+		lo, hi := bounds[0], bounds[1]
+		var want []int
+		for _, k := range keys {
+			if lo <= k && k <= hi {
+				want = append(want, k)
+			}
+		}
+		var got []int
+		for k := range m.Range(lo, hi) {
+			got = append(got, k)
+		}
+		if !slices.Equal(got, want) {
+			t.Fatalf("Range(%d, %d) = %v; want %v", lo, hi, got, want)
+		}
+	}
+}
+
+func TestEmptyAndUpdate(t *testing.T) {
+	var m OrderedMap[string, int]
+	if _, _, ok := m.Min(); ok {
+		t.Fatalf("Min() on empty map should report false")
+	}
+	if _, _, ok := m.Floor("x"); ok {
+		t.Fatalf("Floor() on empty map should report false")
+	}
+	if m.Delete("x") {
+		t.Fatalf("Delete() on empty map should report false")
+	}
+	m.Put("a", 1)
+	m.Put("a", 2)
+	if v, ok := m.Get("a"); !ok || v != 2 || m.Len() != 1 {
+		t.Fatalf("Get(a) = %d, %t with Len() = %d; want 2, true, 1", v, ok, m.Len())
+	}
+	for k := range m.Range("a", "z") {
+		if k != "a" {
+			t.Fatalf("Range yielded %q", k)
+		}
+		break
+	}
+}
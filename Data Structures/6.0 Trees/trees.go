@@ -1,6 +1,18 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"go-mastery/trees/btree"
+	"go-mastery/trees/interval"
+	"go-mastery/trees/rangequery"
+	"go-mastery/trees/skiplist"
+	"go-mastery/trees/tree"
+)
 
 // Node represents a node in the binary search tree
 type Node struct {
@@ -63,4 +75,126 @@ func main() {
 
 	fmt.Println("\nSearch for key 7:", root.Search(7)) // Output: true
 	fmt.Println("Search for key 9:", root.Search(9))   // Output: false
+
+	// Ascending keys turn the Node BST into a linked list; OrderedMap stays balanced
+	readings := tree.New[int, float64]()
+	for ts := 1000; ts < 2000; ts++ {
+		readings.Put(ts, float64(ts%60))
+	}
+	fmt.Println("Height for 1000 keys:", readings.Height()) // Output: Height for 1000 keys: 10
+
+	readings.Delete(1500)
+	floor, _, _ := readings.Floor(1500)
+	ceiling, _, _ := readings.Ceiling(1500)
+	fmt.Println("Floor/Ceiling of 1500:", floor, ceiling) // Output: Floor/Ceiling of 1500: 1499 1501
+
+	fmt.Println("Rank of 1600:", readings.Rank(1600)) // Output: Rank of 1600: 599
+	for ts, value := range readings.Range(1498, 1502) {
+		fmt.Printf("%d=%.0f ", ts, value)
+	}
+	fmt.Println() // Output: 1498=58 1499=59 1501=1 1502=2
+
+	// A ConcurrentSkipList can be written and read from many goroutines
+	index := skiplist.NewConcurrent[string, int]()
+	var wg sync.WaitGroup
+	for _, word := range []string{"pear", "apple", "fig", "plum", "kiwi"} {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			index.Put(word, len(word))
+		}()
+	}
+	wg.Wait()
+	for word, n := range index.Range("b", "m") {
+		fmt.Printf("%s=%d ", word, n)
+	}
+	fmt.Println() // Output: fig=3 kiwi=4
+
+	bPlusTree()
+	rollingWindow()
+	calendar()
+}
+
+// bPlusTree bulk-loads a file-backed B+tree, reopens it and scans a range
+func bPlusTree() {
+	dir, err := os.MkdirTemp("", "btree")
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "readings.db")
+
+	db, err := btree.Open(path, btree.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	err = db.BulkLoad(func(yield func([]byte, []byte) bool) {
+		for ts := range 100_000 {
+			if !yield(fmt.Appendf(nil, "ts%08d", ts), fmt.Appendf(nil, "%d", ts%60)) {
+				return
+			}
+		}
+	})
+	if err == nil {
+		err = db.Close()
+	}
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	db, err = btree.Open(path, btree.Options{})
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer db.Close()
+	info, _ := os.Stat(path)
+	fmt.Println("Keys on disk:", db.Len(), "pages:", info.Size()/btree.DefaultPageSize) // Output: Keys on disk: 100000 pages: 1615
+	for key, value := range db.Range([]byte("ts00050000"), []byte("ts00050002")) {
+		fmt.Printf("%s=%s ", key, value)
+	}
+	fmt.Println() // Output: ts00050000=20 ts00050001=21 ts00050002=22
+}
+
+// rollingWindow keeps a minute of per-second metrics in a ring of 60 slots
+// and queries the last ten seconds
+func rollingWindow() {
+	const window = 60
+	requests := rangequery.NewFenwick[int](window)
+	slowest := rangequery.NewSegmentTree(make([]int, window), func(a, b int) int { return max(a, b) }, 0)
+	for second := range 150 {
+		slot := second % window
+		requests.Set(slot, second%7)
+		slowest.Set(slot, second%13*10)
+	}
+	// Second 149 is in slot 29, so the last ten seconds are slots 20 to 29
+	fmt.Println("Requests:", requests.RangeSum(20, 30), "of", requests.PrefixSum(window)) // Output: Requests: 24 of 177
+	fmt.Println("Slowest:", slowest.Query(20, 30))                                        // Output: Slowest: 120
+
+	// A lazy segment tree adds to a whole range at once, here to backfill
+	// a batch that was counted late
+	counts := rangequery.NewLazySegmentTree(make([]int, window), rangequery.AddSum[int]())
+	counts.Update(20, 30, 3)
+	counts.Update(25, 60, 1)
+	fmt.Println("Backfilled:", counts.Query(20, 30), counts.Get(27)) // Output: Backfilled: 35 4
+}
+
+// calendar stores meetings as intervals of minutes since midnight and
+// checks new bookings for conflicts
+func calendar() {
+	meetings := interval.New[int, string]()
+	meetings.Insert(interval.Interval[int]{Lo: 9 * 60, Hi: 10 * 60}, "standup")
+	meetings.Insert(interval.Interval[int]{Lo: 9*60 + 30, Hi: 11 * 60}, "review")
+	meetings.Insert(interval.Interval[int]{Lo: 14 * 60, Hi: 15 * 60}, "planning")
+
+	for iv, name := range meetings.Overlapping(interval.Point(9*60 + 45)) {
+		fmt.Printf("%s %d-%d ", name, iv.Lo, iv.Hi)
+	}
+	fmt.Println() // Output: standup 540-600 review 570-660
+
+	lunch := interval.Interval[int]{Lo: 12 * 60, Hi: 13 * 60}
+	conflicts := 0
+	for range meetings.Overlapping(lunch) {
+		conflicts++
+	}
+	fmt.Println("Lunch conflicts:", conflicts) // Output: Lunch conflicts: 0
 }
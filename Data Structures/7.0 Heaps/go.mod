@@ -0,0 +1,3 @@
+module go-mastery/heaps
+
+go 1.23.4
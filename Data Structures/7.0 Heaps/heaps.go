@@ -3,6 +3,8 @@ package main
 import (
 	"container/heap"
 	"fmt"
+
+	"go-mastery/heaps/pq"
 )
 
 // An IntHeap is a min-heap of integers.
@@ -43,4 +45,37 @@ func main() {
 	for ints.Len() > 0 {
 		fmt.Printf("%d ", heap.Pop(ints))
 	}
+	fmt.Println() // Output: 1 2 3 5
+
+	// PriorityQueue works for any value type and returns handles for updates
+	tasks := pq.NewMin[string, int]()
+	tasks.Push("write report", 3)
+	backup := tasks.Push("backup", 5)
+	deploy := tasks.Push("deploy", 4)
+	tasks.Push("email", 1)
+
+	tasks.Update(backup, 0) // decrease-key: backup is now most urgent
+	tasks.Remove(deploy)    // cancelled
+
+	for tasks.Len() > 0 {
+		task, _ := tasks.Pop()
+		fmt.Printf("%s (%d) ", task.Value, task.Priority)
+	}
+	// Output: backup (0) email (1) write report (3)
+	fmt.Println()
+
+	// Every heap in pq implements pq.Heap, so they can be swapped freely
+	var h pq.Heap[string, int] = pq.NewDaryMin[string, int](4)
+	h.Push("b", 2)
+	h.Push("a", 1)
+	top, _ := h.Peek()
+	fmt.Println("4-ary heap top:", top.Value) // Output: 4-ary heap top: a
+
+	// Pairing heaps merge in O(1)
+	left, right := pq.NewPairingMin[string, int](), pq.NewPairingMin[string, int]()
+	left.Push("x", 10)
+	right.Push("y", 5)
+	left.Meld(right)
+	first, _ := left.Pop()
+	fmt.Println("Melded top:", first.Value, "len:", left.Len()) // Output: Melded top: y len: 1
 }
@@ -0,0 +1,114 @@
+package pq
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+)
+
+// The benchmarks run the same workloads against every Heap in minHeaps.
+// Compare arities and the pairing heap with:
+//
+//	go test -bench . -benchmem ./pq
+
+// benchmarkSizes are the numbers of items held by the heap.
+var benchmarkSizes = []int{1 << 10, 1 << 16}
+
+// priorities returns n pseudo-random priorities, the same on every run.
+func priorities(n int) []int {
+	rng := rand.New(rand.NewSource(42))
+	out := make([]int, n)
+	for i := range out {
+		out[i] = rng.Intn(1 << 30)
+	}
+	return out
+}
+
+// fill pushes every priority and returns the handles.
+func fill(h Heap[int, int], prios []int) []*Item[int, int] {
+	handles := make([]*Item[int, int], len(prios))
+	for i, p := range prios {
+		handles[i] = h.Push(i, p)
+	}
+	return handles
+}
+
+func BenchmarkInsert(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		prios := priorities(size)
+		for _, impl := range minHeaps {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					fill(impl.new(), prios)
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkPop(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		prios := priorities(size)
+		for _, impl := range minHeaps {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, size), func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					b.StopTimer()
+					h := impl.new()
+					fill(h, prios)
+					b.StartTimer()
+					for h.Len() > 0 {
+						h.Pop()
+					}
+				}
+			})
+		}
+	}
+}
+
+func BenchmarkDecreaseKey(b *testing.B) {
+	for _, size := range benchmarkSizes {
+		prios := priorities(size)
+		for _, impl := range minHeaps {
+			b.Run(fmt.Sprintf("%s/%d", impl.name, size), func(b *testing.B) {
+				h := impl.new()
+				handles := fill(h, prios)
+				rng := rand.New(rand.NewSource(7))
+				b.ResetTimer()
+				for i := 0; i < b.N; i++ {
+					item := handles[rng.Intn(size)]
+					if item.Priority <= 0 {
+						b.StopTimer()
+						h.Update(item, 1<<30) // reset so priorities never run out
+						b.StartTimer()
+						continue
+					}
+					h.Update(item, item.Priority-rng.Intn(1<<20)-1)
+				}
+			})
+		}
+	}
+}
+
+// BenchmarkDijkstraWorkload mimics Dijkstra on a dense graph: every pop is
+// followed by many decrease-keys on the items still queued.
+func BenchmarkDijkstraWorkload(b *testing.B) {
+	const vertices, decreasesPerPop = 2048, 64
+	for _, impl := range minHeaps {
+		b.Run(impl.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				rng := rand.New(rand.NewSource(1))
+				h := impl.new()
+				handles := fill(h, priorities(vertices))
+				for h.Len() > 0 {
+					top, _ := h.Pop()
+					for range decreasesPerPop {
+						item := handles[rng.Intn(vertices)]
+						if item.Priority > top.Priority {
+							h.Update(item, top.Priority+(item.Priority-top.Priority)/2)
+						}
+					}
+				}
+			}
+		})
+	}
+}
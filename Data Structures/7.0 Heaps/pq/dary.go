@@ -0,0 +1,148 @@
+package pq
+
+import "cmp"
+
+// DaryHeap is a priority queue stored as a d-ary heap: every node has up to
+// d children instead of two. A wider heap is shallower, so Push and Update
+// (decrease-key) move items through fewer levels, while Pop compares more
+// children per level. Dijkstra on dense graphs performs far more
+// decrease-keys than pops, which is where d > 2 pays off.
+type DaryHeap[T, P any] struct {
+	list  []*Item[T, P]
+	less  func(a, b P) bool
+	arity int
+}
+
+// NewDary creates a d-ary heap with the given arity that pops the item
+// whose priority is less than all others according to less.
+// It panics if arity is less than 2.
+func NewDary[T, P any](arity int, less func(a, b P) bool) *DaryHeap[T, P] {
+	if arity < 2 {
+		panic("pq: d-ary heap arity must be at least 2")
+	}
+	return &DaryHeap[T, P]{less: less, arity: arity}
+}
+
+// NewDaryMin creates a d-ary heap that pops the smallest priority first
+func NewDaryMin[T any, P cmp.Ordered](arity int) *DaryHeap[T, P] {
+	return NewDary[T](arity, cmp.Less[P])
+}
+
+// Len returns the number of items in the heap
+func (h *DaryHeap[T, P]) Len() int {
+	return len(h.list)
+}
+
+// Push adds a value with the given priority and returns its handle
+func (h *DaryHeap[T, P]) Push(value T, priority P) *Item[T, P] {
+	item := &Item[T, P]{Value: value, Priority: priority, index: len(h.list)}
+	h.list = append(h.list, item)
+	h.up(item.index)
+	return item
+}
+
+// Pop removes and returns the item with the highest priority
+func (h *DaryHeap[T, P]) Pop() (*Item[T, P], error) {
+	if len(h.list) == 0 {
+		return nil, ErrEmpty
+	}
+	top := h.list[0]
+	h.removeAt(0)
+	return top, nil
+}
+
+// Peek returns the item with the highest priority without removing it
+func (h *DaryHeap[T, P]) Peek() (*Item[T, P], error) {
+	if len(h.list) == 0 {
+		return nil, ErrEmpty
+	}
+	return h.list[0], nil
+}
+
+// Contains reports whether the item is still in this heap
+func (h *DaryHeap[T, P]) Contains(item *Item[T, P]) bool {
+	return item != nil && item.index >= 0 && item.index < len(h.list) && h.list[item.index] == item
+}
+
+// Update changes the priority of an item that is still in the heap.
+// It reports false if the item is no longer in the heap.
+func (h *DaryHeap[T, P]) Update(item *Item[T, P], priority P) bool {
+	if !h.Contains(item) {
+		return false
+	}
+	item.Priority = priority
+	h.fix(item.index)
+	return true
+}
+
+// Remove takes an item out of the heap wherever it is.
+// It reports false if the item is no longer in the heap.
+func (h *DaryHeap[T, P]) Remove(item *Item[T, P]) bool {
+	if !h.Contains(item) {
+		return false
+	}
+	h.removeAt(item.index)
+	return true
+}
+
+// removeAt moves the last item into slot i and restores the heap property
+func (h *DaryHeap[T, P]) removeAt(i int) {
+	last := len(h.list) - 1
+	removed := h.list[i]
+	h.swap(i, last)
+	h.list[last] = nil // avoid memory leak
+	h.list = h.list[:last]
+	removed.index = -1
+	if i < last {
+		h.fix(i)
+	}
+}
+
+// fix moves the item at i up or down to its place
+func (h *DaryHeap[T, P]) fix(i int) {
+	if !h.up(i) {
+		h.down(i)
+	}
+}
+
+func (h *DaryHeap[T, P]) swap(i, j int) {
+	h.list[i], h.list[j] = h.list[j], h.list[i]
+	h.list[i].index = i
+	h.list[j].index = j
+}
+
+// up moves the item at i towards the root and reports whether it moved
+func (h *DaryHeap[T, P]) up(i int) bool {
+	start := i
+	for i > 0 {
+		parent := (i - 1) / h.arity
+		if !h.less(h.list[i].Priority, h.list[parent].Priority) {
+			break
+		}
+		h.swap(i, parent)
+		i = parent
+	}
+	return i != start
+}
+
+// down moves the item at i towards the leaves
+func (h *DaryHeap[T, P]) down(i int) {
+	n := len(h.list)
+	for {
+		first := h.arity*i + 1
+		if first >= n {
+			return
+		}
+		best := first
+		for c := first + 1; c < min(first+h.arity, n); c++ {
+			if h.less(h.list[c].Priority, h.list[best].Priority) {
+				best = c
+			}
+		}
+		if !h.less(h.list[best].Priority, h.list[i].Priority) {
+			return
+		}
+		h.swap(i, best)
+		i = best
+	}
+}
@@ -0,0 +1,203 @@
+package pq
+
+import "cmp"
+
+// inPairing marks the index of an Item that is in a PairingHeap. It can
+// never be a position in PriorityQueue or DaryHeap.
+const inPairing = -2
+
+// owner identifies a PairingHeap. Meld points the melded heap's owner at
+// the heap it joined, so its items change hands without being visited.
+type owner struct {
+	joined *owner
+}
+
+// find returns the owner at the end of the chain of melds, shortening the
+// chain on the way
+func (o *owner) find() *owner {
+	for o.joined != nil {
+		if o.joined.joined != nil {
+			o.joined = o.joined.joined
+		}
+		o = o.joined
+	}
+	return o
+}
+
+// PairingHeap is a priority queue stored as a heap-ordered multiway tree.
+// Push, Meld and decrease-key take O(1) time: they only link two trees
+// together. Pop pays for that laziness by merging the root's children in
+// two passes, which takes O(log n) amortized time.
+//
+// Each item records the heap it was pushed to, so Contains, Update and
+// Remove reject handles of other heaps. After a Meld, that record leads
+// to the heap the item now belongs to.
+type PairingHeap[T, P any] struct {
+	root *Item[T, P]
+	size int
+	less func(a, b P) bool
+	id   *owner
+}
+
+// NewPairing creates a pairing heap that pops the item whose priority is
+// less than all others according to less
+func NewPairing[T, P any](less func(a, b P) bool) *PairingHeap[T, P] {
+	return &PairingHeap[T, P]{less: less, id: &owner{}}
+}
+
+// NewPairingMin creates a pairing heap that pops the smallest priority first
+func NewPairingMin[T any, P cmp.Ordered]() *PairingHeap[T, P] {
+	return NewPairing[T](cmp.Less[P])
+}
+
+// Len returns the number of items in the heap
+func (h *PairingHeap[T, P]) Len() int {
+	return h.size
+}
+
+// Push adds a value with the given priority and returns its handle
+func (h *PairingHeap[T, P]) Push(value T, priority P) *Item[T, P] {
+	item := &Item[T, P]{Value: value, Priority: priority, index: inPairing, owner: h.id}
+	h.root = h.link(h.root, item)
+	h.size++
+	return item
+}
+
+// Pop removes and returns the item with the highest priority
+func (h *PairingHeap[T, P]) Pop() (*Item[T, P], error) {
+	if h.root == nil {
+		return nil, ErrEmpty
+	}
+	top := h.root
+	h.root = h.mergePairs(top.child)
+	h.size--
+	top.child, top.owner = nil, nil
+	top.index = -1
+	return top, nil
+}
+
+// Peek returns the item with the highest priority without removing it
+func (h *PairingHeap[T, P]) Peek() (*Item[T, P], error) {
+	if h.root == nil {
+		return nil, ErrEmpty
+	}
+	return h.root, nil
+}
+
+// Meld moves every item of other into h in O(1) time and leaves other empty.
+// Handles of other's items become handles of h.
+func (h *PairingHeap[T, P]) Meld(other *PairingHeap[T, P]) {
+	if other == h {
+		return
+	}
+	h.root = h.link(h.root, other.root)
+	h.size += other.size
+	other.id.joined = h.id
+	other.root, other.size, other.id = nil, 0, &owner{}
+}
+
+// Contains reports whether the item is still in this heap
+func (h *PairingHeap[T, P]) Contains(item *Item[T, P]) bool {
+	return item != nil && item.index == inPairing && item.owner.find() == h.id
+}
+
+// Update changes the priority of an item that is still in the heap.
+// Moving an item towards the front (decrease-key for a min-heap) takes O(1)
+// time; moving it back costs a Remove and a Push.
+// It reports false if the item is no longer in the heap.
+func (h *PairingHeap[T, P]) Update(item *Item[T, P], priority P) bool {
+	if !h.Contains(item) {
+		return false
+	}
+	if !h.less(item.Priority, priority) {
+		// The item only moves towards the root: cut its subtree out and
+		// link it back in at the top.
+		item.Priority = priority
+		if item != h.root {
+			h.cut(item)
+			h.root = h.link(h.root, item)
+		}
+		return true
+	}
+	h.Remove(item)
+	item.Priority = priority
+	item.index, item.owner = inPairing, h.id
+	h.root = h.link(h.root, item)
+	h.size++
+	return true
+}
+
+// Remove takes an item out of the heap wherever it is.
+// It reports false if the item is no longer in the heap.
+func (h *PairingHeap[T, P]) Remove(item *Item[T, P]) bool {
+	if !h.Contains(item) {
+		return false
+	}
+	if item == h.root {
+		h.Pop()
+		return true
+	}
+	h.cut(item)
+	h.root = h.link(h.root, h.mergePairs(item.child))
+	h.size--
+	item.child, item.owner = nil, nil
+	item.index = -1
+	return true
+}
+
+// link makes the root with the lower priority the leftmost child of the
+// other root and returns the combined tree. Either tree may be nil.
+func (h *PairingHeap[T, P]) link(a, b *Item[T, P]) *Item[T, P] {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	if h.less(b.Priority, a.Priority) {
+		a, b = b, a
+	}
+	b.prev = a
+	b.next = a.child
+	if a.child != nil {
+		a.child.prev = b
+	}
+	a.child = b
+	a.next, a.prev = nil, nil
+	return a
+}
+
+// cut detaches the subtree rooted at item from its parent and siblings
+func (h *PairingHeap[T, P]) cut(item *Item[T, P]) {
+	if item.prev.child == item {
+		item.prev.child = item.next
+	} else {
+		item.prev.next = item.next
+	}
+	if item.next != nil {
+		item.next.prev = item.prev
+	}
+	item.next, item.prev = nil, nil
+}
+
+// mergePairs combines a list of sibling trees into one tree: first link
+// them in pairs from left to right, then fold the pairs from right to left
+func (h *PairingHeap[T, P]) mergePairs(first *Item[T, P]) *Item[T, P] {
+	var pairs []*Item[T, P]
+	for a := first; a != nil; {
+		b := a.next
+		var rest *Item[T, P]
+		if b != nil {
+			rest = b.next
+			b.next, b.prev = nil, nil
+		}
+		a.next, a.prev = nil, nil
+		pairs = append(pairs, h.link(a, b))
+		a = rest
+	}
+	var root *Item[T, P]
+	for i := len(pairs) - 1; i >= 0; i-- {
+		root = h.link(pairs[i], root)
+	}
+	return root
+}
@@ -0,0 +1,155 @@
+// Package pq implements generic priority queues whose items can be
+// re-prioritised or removed through the handle returned by Push.
+package pq
+
+import (
+	"cmp"
+	"container/heap"
+	"errors"
+)
+
+// ErrEmpty is returned by Pop and Peek when the queue has no items
+var ErrEmpty = errors.New("priority queue is empty")
+
+// Heap is the API shared by the priority queues in this package
+type Heap[T, P any] interface {
+	// Len returns the number of items in the queue
+	Len() int
+	// Push adds a value with the given priority and returns its handle
+	Push(value T, priority P) *Item[T, P]
+	// Pop removes and returns the item with the highest priority
+	Pop() (*Item[T, P], error)
+	// Peek returns the item with the highest priority without removing it
+	Peek() (*Item[T, P], error)
+	// Update changes the priority of a queued item
+	Update(item *Item[T, P], priority P) bool
+	// Remove takes a queued item out of the queue
+	Remove(item *Item[T, P]) bool
+}
+
+// Item is a value stored in a priority queue together with its priority.
+// The *Item returned by Push is a handle for Update and Remove.
+type Item[T, P any] struct {
+	Value    T
+	Priority P
+	index    int // position in the heap, -1 once the item has left the queue
+
+	// Links used by PairingHeap only
+	owner *owner      // the heap the item is in, nil once it has left
+	child *Item[T, P] // leftmost child
+	next  *Item[T, P] // right sibling
+	prev  *Item[T, P] // left sibling, or parent for a leftmost child
+}
+
+// items implements heap.Interface and keeps every Item's index up to date
+type items[T, P any] struct {
+	list []*Item[T, P]
+	less func(a, b P) bool
+}
+
+// Len returns the number of items in the heap.
+func (h *items[T, P]) Len() int { return len(h.list) }
+
+// Less reports whether item i should be popped before item j.
+func (h *items[T, P]) Less(i, j int) bool { return h.less(h.list[i].Priority, h.list[j].Priority) }
+
+// Swap swaps items i and j and records their new positions.
+func (h *items[T, P]) Swap(i, j int) {
+	h.list[i], h.list[j] = h.list[j], h.list[i]
+	h.list[i].index = i
+	h.list[j].index = j
+}
+
+// Push appends an item to the heap.
+func (h *items[T, P]) Push(x any) {
+	item := x.(*Item[T, P])
+	item.index = len(h.list)
+	h.list = append(h.list, item)
+}
+
+// Pop removes and returns the last item of the heap.
+func (h *items[T, P]) Pop() any {
+	n := len(h.list)
+	item := h.list[n-1]
+	h.list[n-1] = nil // avoid memory leak
+	item.index = -1   // for safety
+	h.list = h.list[:n-1]
+	return item
+}
+
+// PriorityQueue is a binary heap of values ordered by a user-supplied
+// comparison of their priorities
+type PriorityQueue[T, P any] struct {
+	heap items[T, P]
+}
+
+// New creates a priority queue that pops the item whose priority is
+// less than all others according to less
+func New[T, P any](less func(a, b P) bool) *PriorityQueue[T, P] {
+	return &PriorityQueue[T, P]{heap: items[T, P]{less: less}}
+}
+
+// NewMin creates a priority queue that pops the smallest priority first
+func NewMin[T any, P cmp.Ordered]() *PriorityQueue[T, P] {
+	return New[T](cmp.Less[P])
+}
+
+// NewMax creates a priority queue that pops the largest priority first
+func NewMax[T any, P cmp.Ordered]() *PriorityQueue[T, P] {
+	return New[T](func(a, b P) bool { return cmp.Less(b, a) })
+}
+
+// Len returns the number of items in the queue
+func (pq *PriorityQueue[T, P]) Len() int {
+	return pq.heap.Len()
+}
+
+// Push adds a value with the given priority and returns its handle
+func (pq *PriorityQueue[T, P]) Push(value T, priority P) *Item[T, P] {
+	item := &Item[T, P]{Value: value, Priority: priority}
+	heap.Push(&pq.heap, item)
+	return item
+}
+
+// Pop removes and returns the item with the highest priority
+func (pq *PriorityQueue[T, P]) Pop() (*Item[T, P], error) {
+	if pq.Len() == 0 {
+		return nil, ErrEmpty
+	}
+	return heap.Pop(&pq.heap).(*Item[T, P]), nil
+}
+
+// Peek returns the item with the highest priority without removing it
+func (pq *PriorityQueue[T, P]) Peek() (*Item[T, P], error) {
+	if pq.Len() == 0 {
+		return nil, ErrEmpty
+	}
+	return pq.heap.list[0], nil
+}
+
+// Contains reports whether the item is still in this queue
+func (pq *PriorityQueue[T, P]) Contains(item *Item[T, P]) bool {
+	return item != nil && item.index >= 0 && item.index < pq.Len() && pq.heap.list[item.index] == item
+}
+
+// Update changes the priority of an item that is still in the queue, moving
+// it up or down as needed. This covers decrease-key and increase-key.
+// It reports false if the item is no longer in the queue.
+func (pq *PriorityQueue[T, P]) Update(item *Item[T, P], priority P) bool {
+	if !pq.Contains(item) {
+		return false
+	}
+	item.Priority = priority
+	heap.Fix(&pq.heap, item.index)
+	return true
+}
+
+// Remove takes an item out of the queue wherever it is.
+// It reports false if the item is no longer in the queue.
+func (pq *PriorityQueue[T, P]) Remove(item *Item[T, P]) bool {
+	if !pq.Contains(item) {
+		return false
+	}
+	heap.Remove(&pq.heap, item.index)
+	return true
+}
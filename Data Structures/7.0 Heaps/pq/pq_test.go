@@ -0,0 +1,245 @@
+package pq
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+// drain pops every item and returns their priorities in pop order.
+func drain[T, P any](pq *PriorityQueue[T, P]) []P {
+	var out []P
+	for pq.Len() > 0 {
+		item, _ := pq.Pop()
+		out = append(out, item.Priority)
+	}
+	return out
+}
+
+func TestMinAndMax(t *testing.T) {
+	values := []int{5, 2, 8, 1, 9, 3}
+	minQ, maxQ := NewMin[string, int](), NewMax[string, int]()
+	for _, v := range values {
+		minQ.Push("x", v)
+		maxQ.Push("x", v)
+	}
+	if top, _ := minQ.Peek(); top.Priority != 1 {
+		t.Fatalf("min Peek() = %d; want 1", top.Priority)
+	}
+	if got := drain(minQ); !slices.Equal(got, []int{1, 2, 3, 5, 8, 9}) {
+		t.Fatalf("min-heap popped %v", got)
+	}
+	if got := drain(maxQ); !slices.Equal(got, []int{9, 8, 5, 3, 2, 1}) {
+		t.Fatalf("max-heap popped %v", got)
+	}
+	if _, err := minQ.Pop(); !errors.Is(err, ErrEmpty) {
+		t.Fatalf("Pop() on empty queue: err = %v; want ErrEmpty", err)
+	}
+}
+
+func TestCustomLess(t *testing.T) {
+	type job struct {
+		name     string
+		deadline int
+	}
+	// Earlier deadline first, ties broken by name.
+	q := New[job](func(a, b job) bool {
+		if a.deadline != b.deadline {
+			return a.deadline < b.deadline
+		}
+		return a.name < b.name
+	})
+	for _, j := range []job{{"b", 2}, {"a", 2}, {"c", 1}} {
+		q.Push(j, j)
+	}
+	var names []string
+	for q.Len() > 0 {
+		item, _ := q.Pop()
+		names = append(names, item.Value.name)
+	}
+	if !slices.Equal(names, []string{"c", "a", "b"}) {
+		t.Fatalf("popped %v", names)
+	}
+}
+
+// minHeaps lists every Heap implementation, each ordered as a min-heap of ints.
+var minHeaps = []struct {
+	name string
+	new  func() Heap[int, int]
+}{
+	{"Binary", func() Heap[int, int] { return NewMin[int, int]() }},
+	{"Dary2", func() Heap[int, int] { return NewDaryMin[int, int](2) }},
+	{"Dary4", func() Heap[int, int] { return NewDaryMin[int, int](4) }},
+	{"Dary8", func() Heap[int, int] { return NewDaryMin[int, int](8) }},
+	{"Pairing", func() Heap[int, int] { return NewPairingMin[int, int]() }},
+}
+
+// TestUpdateAndRemove applies random pushes, updates, removes and pops and
+// compares the queue with a map of live handles.
+func TestUpdateAndRemove(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	q := NewMin[int, int]()
+	live := make(map[*Item[int, int]]bool)
+	var handles []*Item[int, int]
+
+	for i := range 5000 {
+		switch op := rng.Intn(10); {
+		case op < 4:
+			h := q.Push(i, rng.Intn(1000))
+			live[h] = true
+			handles = append(handles, h)
+		case op < 7 && len(handles) > 0:
+			h := handles[rng.Intn(len(handles))]
+			if q.Update(h, rng.Intn(1000)) != live[h] {
+				t.Fatalf("Update() disagrees with liveness of handle")
+			}
+		case op < 8 && len(handles) > 0:
+			h := handles[rng.Intn(len(handles))]
+			if q.Remove(h) != live[h] {
+				t.Fatalf("Remove() disagrees with liveness of handle")
+			}
+			delete(live, h)
+		case q.Len() > 0:
+			top, _ := q.Pop()
+			for h := range live {
+				if h.Priority < top.Priority {
+					t.Fatalf("Pop() returned %d but %d is still queued", top.Priority, h.Priority)
+				}
+			}
+			delete(live, top)
+			if q.Contains(top) || q.Update(top, 0) {
+				t.Fatalf("popped handle still usable")
+			}
+		}
+		if q.Len() != len(live) {
+			t.Fatalf("Len() = %d; want %d", q.Len(), len(live))
+		}
+	}
+	if got := drain(q); !slices.IsSorted(got) {
+		t.Fatalf("final drain is not sorted: %v", got)
+	}
+}
+
+// TestHeapsUpdateAndRemove applies random pushes, updates, removes and pops
+// and compares each Heap implementation with a map of live handles.
+func TestHeapsUpdateAndRemove(t *testing.T) {
+	for _, impl := range minHeaps {
+		t.Run(impl.name, func(t *testing.T) {
+			rng := rand.New(rand.NewSource(1))
+			q := impl.new()
+			live := make(map[*Item[int, int]]bool)
+			var handles []*Item[int, int]
+
+			for i := range 5000 {
+				switch op := rng.Intn(10); {
+				case op < 4:
+					h := q.Push(i, rng.Intn(1000))
+					live[h] = true
+					handles = append(handles, h)
+				case op < 7 && len(handles) > 0:
+					h := handles[rng.Intn(len(handles))]
+					if q.Update(h, rng.Intn(1000)) != live[h] {
+						t.Fatalf("Update() disagrees with liveness of handle")
+					}
+				case op < 8 && len(handles) > 0:
+					h := handles[rng.Intn(len(handles))]
+					if q.Remove(h) != live[h] {
+						t.Fatalf("Remove() disagrees with liveness of handle")
+					}
+					delete(live, h)
+				case q.Len() > 0:
+					top, _ := q.Pop()
+					for h := range live {
+						if h.Priority < top.Priority {
+							t.Fatalf("Pop() returned %d but %d is still queued", top.Priority, h.Priority)
+						}
+					}
+					delete(live, top)
+					if q.Update(top, 0) || q.Remove(top) {
+						t.Fatalf("popped handle still usable")
+					}
+				}
+				if q.Len() != len(live) {
+					t.Fatalf("Len() = %d; want %d", q.Len(), len(live))
+				}
+			}
+			var got []int
+			for q.Len() > 0 {
+				item, _ := q.Pop()
+				got = append(got, item.Priority)
+			}
+			if !slices.IsSorted(got) {
+				t.Fatalf("final drain is not sorted: %v", got)
+			}
+			if _, err := q.Peek(); !errors.Is(err, ErrEmpty) {
+				t.Fatalf("Peek() on empty heap: err = %v; want ErrEmpty", err)
+			}
+		})
+	}
+}
+
+func TestPairingMeld(t *testing.T) {
+	a, b := NewPairingMin[string, int](), NewPairingMin[string, int]()
+	a.Push("a3", 3)
+	a.Push("a1", 1)
+	moved := b.Push("b2", 2)
+	b.Push("b0", 0)
+
+	a.Meld(b)
+	if a.Len() != 4 || b.Len() != 0 {
+		t.Fatalf("after Meld: Len() = %d and %d; want 4 and 0", a.Len(), b.Len())
+	}
+	// Handles from the melded heap now belong to a.
+	if !a.Update(moved, -1) {
+		t.Fatalf("Update() rejected a handle from the melded heap")
+	}
+	var got []string
+	for a.Len() > 0 {
+		item, _ := a.Pop()
+		got = append(got, item.Value)
+	}
+	if !slices.Equal(got, []string{"b2", "b0", "a1", "a3"}) {
+		t.Fatalf("popped %v", got)
+	}
+	// b is empty but still usable, and its old handles are not its own.
+	if b.Contains(moved) || b.Remove(moved) {
+		t.Fatalf("emptied heap accepted a handle that moved to another heap")
+	}
+	h := b.Push("b5", 5)
+	if !b.Contains(h) || a.Contains(h) {
+		t.Fatalf("handle pushed after Meld belongs to the wrong heap")
+	}
+}
+
+func TestForeignHandle(t *testing.T) {
+	a, b := NewMin[string, int](), NewMin[string, int]()
+	h := a.Push("x", 1)
+	b.Push("y", 2)
+	if b.Contains(h) || b.Update(h, 0) || b.Remove(h) {
+		t.Fatalf("queue accepted a handle from another queue")
+	}
+	c, d := NewDaryMin[string, int](4), NewDaryMin[string, int](4)
+	h = c.Push("x", 1)
+	d.Push("y", 2)
+	if d.Contains(h) || d.Update(h, 0) || d.Remove(h) {
+		t.Fatalf("d-ary heap accepted a handle from another heap")
+	}
+
+	// The root handles of the other heaps have index 0, and a handle from
+	// another pairing heap looks just like one of its own
+	p, q := NewPairingMin[string, int](), NewPairingMin[string, int]()
+	p.Push("z", 3)
+	for name, h := range map[string]*Item[string, int]{
+		"binary root": a.Push("w", 0),
+		"d-ary root":  c.Push("w", 0),
+		"pairing":     q.Push("w", 0),
+	} {
+		if p.Contains(h) || p.Update(h, 0) || p.Remove(h) {
+			t.Fatalf("pairing heap accepted a %s handle", name)
+		}
+	}
+	if p.Len() != 1 || q.Len() != 1 {
+		t.Fatalf("Len() = %d and %d after rejecting handles; want 1 and 1", p.Len(), q.Len())
+	}
+}
@@ -1,57 +1,13 @@
 package main
 
-import "fmt"
+import (
+	"fmt"
 
-// DisjointSet represents a disjoint set data structure.
-type DisjointSet struct {
-	parent map[int]int
-	rank   map[int]int
-}
-
-// NewDisjointSet initializes a new Disjoint Set.
-func NewDisjointSet() *DisjointSet {
-	return &DisjointSet{
-		parent: make(map[int]int),
-		rank:   make(map[int]int),
-	}
-}
-
-// MakeSet creates a new set containing the given element.
-func (ds *DisjointSet) MakeSet(x int) {
-	ds.parent[x] = x
-	ds.rank[x] = 0
-}
-
-// Find returns the representative of the set containing x.
-func (ds *DisjointSet) Find(x int) int {
-	if ds.parent[x] != x {
-		ds.parent[x] = ds.Find(ds.parent[x]) // Path compression
-	}
-	return ds.parent[x]
-}
-
-// Union merges the sets containing x and y.
-func (ds *DisjointSet) Union(x, y int) {
-	xRoot := ds.Find(x)
-	yRoot := ds.Find(y)
-
-	if xRoot == yRoot {
-		return
-	}
-
-	// Union by rank
-	if ds.rank[xRoot] < ds.rank[yRoot] {
-		ds.parent[xRoot] = yRoot
-	} else if ds.rank[xRoot] > ds.rank[yRoot] {
-		ds.parent[yRoot] = xRoot
-	} else {
-		ds.parent[yRoot] = xRoot
-		ds.rank[xRoot]++
-	}
-}
+	"go-mastery/disjoint-union/disjointset"
+)
 
 func main() {
-	ds := NewDisjointSet()
+	ds := disjointset.New[int]()
 	elements := []int{1, 2, 3, 4, 5}
 
 	// Initialize sets for each element
@@ -66,6 +22,29 @@ func main() {
 
 	// Find representatives
 	for _, elem := range elements {
-		fmt.Printf("Representative of %d: %d\n", elem, ds.Find(elem))
+		root, _ := ds.Find(elem)
+		fmt.Printf("Representative of %d: %d\n", elem, root)
+	}
+
+	size, _ := ds.Size(4)
+	fmt.Println("Size of the set containing 4:", size) // Output: Size of the set containing 4: 4
+	fmt.Println("Number of sets:", ds.Count())         // Output: Number of sets: 2
+	fmt.Println("Components:", ds.Components())        // Output: Components: [[1 2 3 4] [5]]
+
+	// Unknown elements are reported instead of silently mapping to 0
+	if _, err := ds.Find(42); err != nil {
+		fmt.Println("Find(42):", err) // Output: Find(42): element is not in the disjoint set
+	}
+
+	// Rollback mode can undo unions, e.g. for offline dynamic connectivity
+	links := disjointset.NewWithRollback[string]()
+	for _, host := range []string{"web", "api", "db"} {
+		links.MakeSet(host)
 	}
+	links.Union("web", "api")
+	snapshot := links.Snapshot()
+	links.Union("api", "db")
+	fmt.Println("Sets before rollback:", links.Count()) // Output: Sets before rollback: 1
+	links.Rollback(snapshot)
+	fmt.Println("Sets after rollback:", links.Count()) // Output: Sets after rollback: 2
 }
@@ -0,0 +1,215 @@
+// Package disjointset implements a generic disjoint set (union-find) that
+// tracks set sizes and can optionally undo unions.
+package disjointset
+
+import "errors"
+
+// ErrUnknownElement is returned for elements that were never added with MakeSet
+var ErrUnknownElement = errors.New("element is not in the disjoint set")
+
+// change records one update so that a rollback-mode set can undo it
+type change struct {
+	child  int // root attached below parent by Union, or -1 for MakeSet
+	parent int
+}
+
+// DisjointSet represents a collection of disjoint sets of elements.
+// Elements are stored by index; parent and size are indexed the same way.
+type DisjointSet[T comparable] struct {
+	index    map[T]int
+	elements []T
+	parent   []int
+	size     []int // size of the set, valid at roots only
+	count    int   // number of sets
+
+	rollback bool
+	history  []change
+}
+
+// New initializes a disjoint set that uses path compression and union by size
+func New[T comparable]() *DisjointSet[T] {
+	return &DisjointSet[T]{index: make(map[T]int)}
+}
+
+// NewWithRollback initializes a disjoint set whose unions can be undone with
+// Snapshot and Rollback. It does not compress paths, because a compressed
+// path cannot be restored cheaply; union by size still keeps Find at O(log n).
+func NewWithRollback[T comparable]() *DisjointSet[T] {
+	ds := New[T]()
+	ds.rollback = true
+	return ds
+}
+
+// MakeSet creates a new set containing only x.
+// It reports false, and changes nothing, if x is already present.
+func (ds *DisjointSet[T]) MakeSet(x T) bool {
+	if _, exists := ds.index[x]; exists {
+		return false
+	}
+	i := len(ds.elements)
+	ds.index[x] = i
+	ds.elements = append(ds.elements, x)
+	ds.parent = append(ds.parent, i)
+	ds.size = append(ds.size, 1)
+	ds.count++
+	if ds.rollback {
+		ds.history = append(ds.history, change{child: -1, parent: i})
+	}
+	return true
+}
+
+// Contains reports whether x was added with MakeSet
+func (ds *DisjointSet[T]) Contains(x T) bool {
+	_, exists := ds.index[x]
+	return exists
+}
+
+// root returns the index of the root of the set containing element i
+func (ds *DisjointSet[T]) root(i int) int {
+	r := i
+	for ds.parent[r] != r {
+		r = ds.parent[r]
+	}
+	if !ds.rollback {
+		// Path compression
+		for ds.parent[i] != r {
+			ds.parent[i], i = r, ds.parent[i]
+		}
+	}
+	return r
+}
+
+// lookup returns the root index of the set containing x
+func (ds *DisjointSet[T]) lookup(x T) (int, error) {
+	i, exists := ds.index[x]
+	if !exists {
+		return 0, ErrUnknownElement
+	}
+	return ds.root(i), nil
+}
+
+// Find returns the representative of the set containing x
+func (ds *DisjointSet[T]) Find(x T) (T, error) {
+	r, err := ds.lookup(x)
+	if err != nil {
+		var zero T
+		return zero, err
+	}
+	return ds.elements[r], nil
+}
+
+// Union merges the sets containing x and y and reports whether they were
+// separate before
+func (ds *DisjointSet[T]) Union(x, y T) (bool, error) {
+	xRoot, err := ds.lookup(x)
+	if err != nil {
+		return false, err
+	}
+	yRoot, err := ds.lookup(y)
+	if err != nil {
+		return false, err
+	}
+	if xRoot == yRoot {
+		return false, nil
+	}
+
+	// Union by size: attach the smaller tree below the larger one
+	if ds.size[xRoot] < ds.size[yRoot] {
+		xRoot, yRoot = yRoot, xRoot
+	}
+	ds.parent[yRoot] = xRoot
+	ds.size[xRoot] += ds.size[yRoot]
+	ds.count--
+	if ds.rollback {
+		ds.history = append(ds.history, change{child: yRoot, parent: xRoot})
+	}
+	return true, nil
+}
+
+// Connected reports whether x and y are in the same set
+func (ds *DisjointSet[T]) Connected(x, y T) (bool, error) {
+	xRoot, err := ds.lookup(x)
+	if err != nil {
+		return false, err
+	}
+	yRoot, err := ds.lookup(y)
+	if err != nil {
+		return false, err
+	}
+	return xRoot == yRoot, nil
+}
+
+// Size returns the number of elements in the set containing x
+func (ds *DisjointSet[T]) Size(x T) (int, error) {
+	r, err := ds.lookup(x)
+	if err != nil {
+		return 0, err
+	}
+	return ds.size[r], nil
+}
+
+// Count returns the number of disjoint sets
+func (ds *DisjointSet[T]) Count() int {
+	return ds.count
+}
+
+// Len returns the number of elements across all sets
+func (ds *DisjointSet[T]) Len() int {
+	return len(ds.elements)
+}
+
+// Components returns the members of every set. Sets are ordered by their
+// earliest added element, and members within a set by the order they were added.
+func (ds *DisjointSet[T]) Components() [][]T {
+	position := make(map[int]int) // root index -> position in components
+	components := make([][]T, 0, ds.count)
+	for i, x := range ds.elements {
+		r := ds.root(i)
+		p, seen := position[r]
+		if !seen {
+			p = len(components)
+			position[r] = p
+			components = append(components, make([]T, 0, ds.size[r]))
+		}
+		components[p] = append(components[p], x)
+	}
+	return components
+}
+
+// Snapshot returns a marker for the current state that can be passed to
+// Rollback. It panics unless the set was created with NewWithRollback.
+func (ds *DisjointSet[T]) Snapshot() int {
+	if !ds.rollback {
+		panic("disjointset: Snapshot requires NewWithRollback")
+	}
+	return len(ds.history)
+}
+
+// Rollback undoes every MakeSet and successful Union made since the given
+// snapshot, newest first. It panics unless the set was created with
+// NewWithRollback or if the snapshot was already rolled back past.
+func (ds *DisjointSet[T]) Rollback(snapshot int) {
+	if !ds.rollback {
+		panic("disjointset: Rollback requires NewWithRollback")
+	}
+	if snapshot < 0 || snapshot > len(ds.history) {
+		panic("disjointset: invalid snapshot")
+	}
+	for len(ds.history) > snapshot {
+		c := ds.history[len(ds.history)-1]
+		ds.history = ds.history[:len(ds.history)-1]
+		if c.child < 0 {
+			// Undo MakeSet; it is always the most recently added element
+			delete(ds.index, ds.elements[c.parent])
+			ds.elements = ds.elements[:c.parent]
+			ds.parent = ds.parent[:c.parent]
+			ds.size = ds.size[:c.parent]
+			ds.count--
+		} else {
+			// Undo Union; the child is still a root of its original size
+			ds.parent[c.child] = c.child
+			ds.size[c.parent] -= ds.size[c.child]
+			ds.count++
+		}
+	}
+}
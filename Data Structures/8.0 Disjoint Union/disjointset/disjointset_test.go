@@ -0,0 +1,111 @@
+package disjointset
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestUnionFindAndSize(t *testing.T) {
+	ds := New[string]()
+	for _, x := range []string{"a", "b", "c", "d", "e"} {
+		ds.MakeSet(x)
+	}
+	if ds.MakeSet("a") {
+		t.Fatalf("MakeSet(a) twice should report false")
+	}
+	ds.Union("a", "b")
+	ds.Union("c", "d")
+	if merged, _ := ds.Union("b", "a"); merged {
+		t.Fatalf("Union of an already merged pair should report false")
+	}
+	ds.Union("b", "c")
+
+	if ds.Count() != 2 || ds.Len() != 5 {
+		t.Fatalf("Count() = %d, Len() = %d; want 2, 5", ds.Count(), ds.Len())
+	}
+	if size, _ := ds.Size("d"); size != 4 {
+		t.Fatalf("Size(d) = %d; want 4", size)
+	}
+	ra, _ := ds.Find("a")
+	rd, _ := ds.Find("d")
+	if ra != rd {
+		t.Fatalf("Find(a) = %q, Find(d) = %q; want the same representative", ra, rd)
+	}
+	want := [][]string{{"a", "b", "c", "d"}, {"e"}}
+	if got := ds.Components(); !slices.EqualFunc(got, want, slices.Equal) {
+		t.Fatalf("Components() = %v; want %v", got, want)
+	}
+}
+
+func TestUnknownElements(t *testing.T) {
+	ds := New[int]()
+	ds.MakeSet(1)
+	if _, err := ds.Find(0); !errors.Is(err, ErrUnknownElement) {
+		t.Fatalf("Find(0): err = %v; want ErrUnknownElement", err)
+	}
+	if _, err := ds.Union(1, 2); !errors.Is(err, ErrUnknownElement) {
+		t.Fatalf("Union(1, 2): err = %v; want ErrUnknownElement", err)
+	}
+	if _, err := ds.Size(2); !errors.Is(err, ErrUnknownElement) {
+		t.Fatalf("Size(2): err = %v; want ErrUnknownElement", err)
+	}
+	if _, err := ds.Connected(2, 1); !errors.Is(err, ErrUnknownElement) {
+		t.Fatalf("Connected(2, 1): err = %v; want ErrUnknownElement", err)
+	}
+	if ds.Contains(2) || !ds.Contains(1) {
+		t.Fatalf("Contains() is wrong")
+	}
+}
+
+// TestRollback checks that rolling back restores exactly the sets, sizes and
+// count seen at the snapshot, including after nested snapshots.
+func TestRollback(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	ds := NewWithRollback[int]()
+	for i := range 50 {
+		ds.MakeSet(i)
+	}
+
+	type state struct {
+		snapshot   int
+		count      int
+		components [][]int
+	}
+	var saved []state
+	for round := range 10 {
+		saved = append(saved, state{ds.Snapshot(), ds.Count(), ds.Components()})
+		for range 8 {
+			ds.Union(rng.Intn(50), rng.Intn(50))
+		}
+		ds.MakeSet(100 + round)
+		ds.Union(100+round, rng.Intn(50))
+	}
+	for i := len(saved) - 1; i >= 0; i-- {
+		ds.Rollback(saved[i].snapshot)
+		if ds.Count() != saved[i].count {
+			t.Fatalf("after Rollback: Count() = %d; want %d", ds.Count(), saved[i].count)
+		}
+		if got := ds.Components(); !slices.EqualFunc(got, saved[i].components, slices.Equal) {
+			t.Fatalf("after Rollback: Components() = %v; want %v", got, saved[i].components)
+		}
+		for _, comp := range saved[i].components {
+			if size, _ := ds.Size(comp[0]); size != len(comp) {
+				t.Fatalf("after Rollback: Size(%d) = %d; want %d", comp[0], size, len(comp))
+			}
+		}
+	}
+	if ds.Count() != 50 || ds.Contains(100) {
+		t.Fatalf("rolling back to the first snapshot should leave 50 singletons")
+	}
+}
+
+func TestSnapshotRequiresRollbackMode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("Snapshot() on a path-compressing set should panic")
+		}
+	}()
+	New[int]().Snapshot()
+}
@@ -0,0 +1,3 @@
+module go-mastery/disjoint-union
+
+go 1.23.4
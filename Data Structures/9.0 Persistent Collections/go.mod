@@ -0,0 +1,3 @@
+module go-mastery/persistent-collections
+
+go 1.24.0
@@ -0,0 +1,43 @@
+package main
+
+import (
+	"fmt"
+
+	"go-mastery/persistent-collections/persistent"
+)
+
+func main() {
+	// Lists share their tails: both versions reuse the nodes of base
+	base := persistent.ListOf(2, 3)
+	a := base.Prepend(1)
+	b := base.Prepend(10)
+	fmt.Println(a) // Output: 1 -> 2 -> 3 -> nil
+	fmt.Println(b) // Output: 10 -> 2 -> 3 -> nil
+
+	// Config snapshots: every Set returns a new version
+	v1 := persistent.NewMap[string, string]().
+		Set("host", "localhost").
+		Set("port", "8080")
+	v2 := v1.Set("port", "9090").Delete("host")
+
+	port, _ := v1.Get("port")
+	fmt.Println("v1 port:", port, "len:", v1.Len()) // Output: v1 port: 8080 len: 2
+	port, _ = v2.Get("port")
+	fmt.Println("v2 port:", port, "len:", v2.Len()) // Output: v2 port: 9090 len: 1
+
+	// Undo history: keep every version of a document and step back for free
+	history := []*persistent.Vector[string]{persistent.NewVector[string]()}
+	for _, line := range []string{"first", "second", "third"} {
+		history = append(history, history[len(history)-1].Append(line))
+	}
+	current := history[len(history)-1].Set(0, "FIRST")
+	fmt.Println("Current:", current.Get(0), current.Len())              // Output: Current: FIRST 3
+	fmt.Println("Two steps back:", history[1].Get(0), history[1].Len()) // Output: Two steps back: first 1
+
+	for i, line := range current.Pop().All() {
+		fmt.Printf("%d: %s\n", i, line)
+	}
+	// Output:
+	// 0: FIRST
+	// 1: second
+}
@@ -0,0 +1,97 @@
+// Package persistent implements immutable collections. Every update returns
+// a new version and leaves the old one untouched; the versions share all the
+// structure that the update did not change, so keeping many versions around
+// costs little more than keeping one.
+//
+// Because nothing is ever modified in place, any version can be read from
+// many goroutines at once without locking.
+package persistent
+
+import (
+	"fmt"
+	"iter"
+	"strings"
+)
+
+// List is an immutable singly linked list. A nil *List is the empty list,
+// and every method can be called on it.
+type List[T any] struct {
+	head T
+	tail *List[T]
+	len  int
+}
+
+// ListOf creates a list holding the given values in order
+func ListOf[T any](values ...T) *List[T] {
+	var l *List[T]
+	for i := len(values) - 1; i >= 0; i-- {
+		l = l.Prepend(values[i])
+	}
+	return l
+}
+
+// Prepend returns a new list with value in front of l. It takes O(1) time,
+// and the new list shares every node of l.
+func (l *List[T]) Prepend(value T) *List[T] {
+	return &List[T]{head: value, tail: l, len: l.Len() + 1}
+}
+
+// Head returns the first value of the list
+func (l *List[T]) Head() (T, bool) {
+	if l == nil {
+		var zero T
+		return zero, false
+	}
+	return l.head, true
+}
+
+// Tail returns the list without its first value. The tail of the empty list is empty.
+func (l *List[T]) Tail() *List[T] {
+	if l == nil {
+		return nil
+	}
+	return l.tail
+}
+
+// Len returns the number of values in the list
+func (l *List[T]) Len() int {
+	if l == nil {
+		return 0
+	}
+	return l.len
+}
+
+// IsEmpty checks if the list is empty
+func (l *List[T]) IsEmpty() bool {
+	return l == nil
+}
+
+// Reverse returns a new list with the values in reverse order
+func (l *List[T]) Reverse() *List[T] {
+	var reversed *List[T]
+	for value := range l.All() {
+		reversed = reversed.Prepend(value)
+	}
+	return reversed
+}
+
+// All returns an iterator over the values from front to back
+func (l *List[T]) All() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for current := l; current != nil; current = current.tail {
+			if !yield(current.head) {
+				return
+			}
+		}
+	}
+}
+
+// String formats the list as "a -> b -> c -> nil"
+func (l *List[T]) String() string {
+	var sb strings.Builder
+	for value := range l.All() {
+		fmt.Fprintf(&sb, "%v -> ", value)
+	}
+	sb.WriteString("nil")
+	return sb.String()
+}
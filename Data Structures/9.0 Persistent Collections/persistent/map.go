@@ -0,0 +1,248 @@
+package persistent
+
+import (
+	"hash/maphash"
+	"iter"
+	"math/bits"
+	"slices"
+)
+
+const (
+	hamtBits  = 5
+	hamtWidth = 1 << hamtBits // children per node
+	hamtMask  = hamtWidth - 1
+)
+
+// hamtEntry is either a key-value pair or, when child is set, a subtree
+type hamtEntry[K comparable, V any] struct {
+	hash  uint64
+	key   K
+	value V
+	child *hamtNode[K, V]
+}
+
+// hamtNode stores only the entries that are present. Bit i of bitmap is set
+// when slot i is occupied, and the entry for slot i is found at the number of
+// set bits below i. Nodes deeper than the 64 bits of the hash are collision
+// nodes: their keys share a hash and are kept in a plain list.
+type hamtNode[K comparable, V any] struct {
+	bitmap  uint32
+	entries []hamtEntry[K, V]
+}
+
+// position returns the slot bit for a hash at the given depth and the index
+// its entry has, or would have, in entries
+func (n *hamtNode[K, V]) position(hash uint64, shift uint) (uint32, int) {
+	bit := uint32(1) << ((hash >> shift) & hamtMask)
+	return bit, bits.OnesCount32(n.bitmap & (bit - 1))
+}
+
+// Map is an immutable hash map implemented as a hash array mapped trie
+// (HAMT). Each level of the trie consumes five bits of the key's hash, so
+// Get, Set and Delete visit at most 13 nodes, and an update copies only the
+// nodes on the path to the key.
+type Map[K comparable, V any] struct {
+	root *hamtNode[K, V]
+	size int
+	seed maphash.Seed
+}
+
+// NewMap creates an empty map. All versions derived from it share its hash seed.
+func NewMap[K comparable, V any]() *Map[K, V] {
+	return &Map[K, V]{root: &hamtNode[K, V]{}, seed: maphash.MakeSeed()}
+}
+
+// Len returns the number of key-value pairs in the map
+func (m *Map[K, V]) Len() int {
+	return m.size
+}
+
+// Get retrieves the value associated with the given key
+func (m *Map[K, V]) Get(key K) (V, bool) {
+	hash := maphash.Comparable(m.seed, key)
+	n := m.root
+	for shift := uint(0); ; shift += hamtBits {
+		if shift >= 64 {
+			for _, e := range n.entries {
+				if e.key == key {
+					return e.value, true
+				}
+			}
+			break
+		}
+		bit, i := n.position(hash, shift)
+		if n.bitmap&bit == 0 {
+			break
+		}
+		e := &n.entries[i]
+		if e.child == nil {
+			if e.hash == hash && e.key == key {
+				return e.value, true
+			}
+			break
+		}
+		n = e.child
+	}
+	var zero V
+	return zero, false
+}
+
+// Set returns a new map in which key is associated with value
+func (m *Map[K, V]) Set(key K, value V) *Map[K, V] {
+	entry := hamtEntry[K, V]{hash: maphash.Comparable(m.seed, key), key: key, value: value}
+	root, added := set(m.root, 0, entry)
+	size := m.size
+	if added {
+		size++
+	}
+	return &Map[K, V]{root: root, size: size, seed: m.seed}
+}
+
+// set returns a copy of n with entry stored below it, and whether the key is new
+func set[K comparable, V any](n *hamtNode[K, V], shift uint, entry hamtEntry[K, V]) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		entries := slices.Clone(n.entries)
+		for i := range entries {
+			if entries[i].key == entry.key {
+				entries[i] = entry
+				return &hamtNode[K, V]{entries: entries}, false
+			}
+		}
+		return &hamtNode[K, V]{entries: append(entries, entry)}, true
+	}
+
+	bit, i := n.position(entry.hash, shift)
+	if n.bitmap&bit == 0 {
+		return &hamtNode[K, V]{
+			bitmap:  n.bitmap | bit,
+			entries: slices.Insert(slices.Clone(n.entries), i, entry),
+		}, true
+	}
+
+	existing := n.entries[i]
+	var replacement hamtEntry[K, V]
+	added := false
+	switch {
+	case existing.child != nil:
+		var child *hamtNode[K, V]
+		child, added = set(existing.child, shift+hamtBits, entry)
+		replacement = hamtEntry[K, V]{child: child}
+	case existing.hash == entry.hash && existing.key == entry.key:
+		replacement = entry
+	default:
+		replacement = hamtEntry[K, V]{child: pair(shift+hamtBits, existing, entry)}
+		added = true
+	}
+	entries := slices.Clone(n.entries)
+	entries[i] = replacement
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}, added
+}
+
+// pair builds the smallest subtree that holds two leaves with different keys
+func pair[K comparable, V any](shift uint, a, b hamtEntry[K, V]) *hamtNode[K, V] {
+	if shift >= 64 {
+		return &hamtNode[K, V]{entries: []hamtEntry[K, V]{a, b}}
+	}
+	ai := (a.hash >> shift) & hamtMask
+	bi := (b.hash >> shift) & hamtMask
+	if ai == bi {
+		return &hamtNode[K, V]{
+			bitmap:  1 << ai,
+			entries: []hamtEntry[K, V]{{child: pair(shift+hamtBits, a, b)}},
+		}
+	}
+	if bi < ai {
+		a, b = b, a
+	}
+	return &hamtNode[K, V]{
+		bitmap:  1<<ai | 1<<bi,
+		entries: []hamtEntry[K, V]{a, b},
+	}
+}
+
+// Delete returns a new map without the given key. If the key is absent the
+// map itself is returned.
+func (m *Map[K, V]) Delete(key K) *Map[K, V] {
+	root, removed := remove(m.root, 0, maphash.Comparable(m.seed, key), key)
+	if !removed {
+		return m
+	}
+	if root == nil {
+		root = &hamtNode[K, V]{}
+	}
+	return &Map[K, V]{root: root, size: m.size - 1, seed: m.seed}
+}
+
+// remove returns a copy of n without key, or nil if nothing is left, and
+// whether the key was found
+func remove[K comparable, V any](n *hamtNode[K, V], shift uint, hash uint64, key K) (*hamtNode[K, V], bool) {
+	if shift >= 64 {
+		i := slices.IndexFunc(n.entries, func(e hamtEntry[K, V]) bool { return e.key == key })
+		if i < 0 {
+			return n, false
+		}
+		return shrink(&hamtNode[K, V]{entries: slices.Delete(slices.Clone(n.entries), i, i+1)}), true
+	}
+
+	bit, i := n.position(hash, shift)
+	if n.bitmap&bit == 0 {
+		return n, false
+	}
+	existing := n.entries[i]
+	if existing.child == nil {
+		if existing.hash != hash || existing.key != key {
+			return n, false
+		}
+		return shrink(&hamtNode[K, V]{
+			bitmap:  n.bitmap &^ bit,
+			entries: slices.Delete(slices.Clone(n.entries), i, i+1),
+		}), true
+	}
+
+	child, removed := remove(existing.child, shift+hamtBits, hash, key)
+	if !removed {
+		return n, false
+	}
+	entries := slices.Clone(n.entries)
+	switch {
+	case child == nil:
+		return shrink(&hamtNode[K, V]{
+			bitmap:  n.bitmap &^ bit,
+			entries: slices.Delete(entries, i, i+1),
+		}), true
+	case len(child.entries) == 1 && child.entries[0].child == nil:
+		// A subtree holding a single leaf collapses into that leaf
+		entries[i] = child.entries[0]
+	default:
+		entries[i] = hamtEntry[K, V]{child: child}
+	}
+	return &hamtNode[K, V]{bitmap: n.bitmap, entries: entries}, true
+}
+
+// shrink returns nil for a node without entries
+func shrink[K comparable, V any](n *hamtNode[K, V]) *hamtNode[K, V] {
+	if len(n.entries) == 0 {
+		return nil
+	}
+	return n
+}
+
+// All returns an iterator over the key-value pairs in no particular order
+func (m *Map[K, V]) All() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		walk(m.root, yield)
+	}
+}
+
+func walk[K comparable, V any](n *hamtNode[K, V], yield func(K, V) bool) bool {
+	for _, e := range n.entries {
+		if e.child != nil {
+			if !walk(e.child, yield) {
+				return false
+			}
+		} else if !yield(e.key, e.value) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,175 @@
+package persistent
+
+import (
+	"maps"
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestList(t *testing.T) {
+	var empty *List[int]
+	if empty.Len() != 0 || !empty.IsEmpty() || empty.Tail() != nil {
+		t.Fatalf("nil list is not empty")
+	}
+	base := ListOf(2, 3)
+	a := base.Prepend(1)
+	b := base.Prepend(10)
+
+	if got := slices.Collect(a.All()); !slices.Equal(got, []int{1, 2, 3}) {
+		t.Fatalf("a = %v", got)
+	}
+	if got := slices.Collect(b.All()); !slices.Equal(got, []int{10, 2, 3}) {
+		t.Fatalf("b = %v", got)
+	}
+	if a.Tail() != b.Tail() {
+		t.Fatalf("a and b should share their tail")
+	}
+	if got := a.Reverse().String(); got != "3 -> 2 -> 1 -> nil" {
+		t.Fatalf("Reverse() = %s", got)
+	}
+	if head, _ := a.Head(); head != 1 || a.Len() != 3 {
+		t.Fatalf("Head() = %d, Len() = %d", head, a.Len())
+	}
+}
+
+// TestMapVersions keeps every version of a map alive while applying random
+// updates, then checks that each version still holds exactly what it held
+// when it was created.
+func TestMapVersions(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	m := NewMap[int, int]()
+	want := map[int]int{}
+
+	var versions []*Map[int, int]
+	var snapshots []map[int]int
+	for i := range 3000 {
+		key := rng.Intn(500)
+		if rng.Intn(3) == 0 {
+			m = m.Delete(key)
+			delete(want, key)
+		} else {
+			m = m.Set(key, i)
+			want[key] = i
+		}
+		if i%100 == 0 {
+			versions = append(versions, m)
+			snapshots = append(snapshots, maps.Clone(want))
+		}
+	}
+	versions = append(versions, m)
+	snapshots = append(snapshots, want)
+
+	for v, version := range versions {
+		if version.Len() != len(snapshots[v]) {
+			t.Fatalf("version %d: Len() = %d; want %d", v, version.Len(), len(snapshots[v]))
+		}
+		if got := maps.Collect(version.All()); !maps.Equal(got, snapshots[v]) {
+			t.Fatalf("version %d changed after later updates", v)
+		}
+		for key := range 500 {
+			value, ok := version.Get(key)
+			wantValue, wantOK := snapshots[v][key]
+			if ok != wantOK || value != wantValue {
+				t.Fatalf("version %d: Get(%d) = %d, %t; want %d, %t", v, key, value, ok, wantValue, wantOK)
+			}
+		}
+	}
+}
+
+// TestMapCollisions forces every key into the same hash by reaching the
+// collision nodes below the last trie level directly.
+func TestMapCollisions(t *testing.T) {
+	m := NewMap[string, int]()
+	root := m.root
+	const hash = 0xdeadbeef
+	for i, key := range []string{"a", "b", "c"} {
+		root, _ = set(root, 0, hamtEntry[string, int]{hash: hash, key: key, value: i})
+	}
+	root, _ = set(root, 0, hamtEntry[string, int]{hash: hash, key: "b", value: 20})
+	m = &Map[string, int]{root: root, size: 3, seed: m.seed}
+
+	var got []int
+	for _, e := range collisionNode(root).entries {
+		got = append(got, e.value)
+	}
+	if !slices.Equal(got, []int{0, 20, 2}) {
+		t.Fatalf("collision node holds %v", got)
+	}
+
+	root, removed := remove(root, 0, hash, "a")
+	if !removed {
+		t.Fatalf("remove(a) did not find the key")
+	}
+	root, _ = remove(root, 0, hash, "c")
+	// A single leaf left below the root collapses back into the root.
+	if len(root.entries) != 1 || root.entries[0].child != nil || root.entries[0].key != "b" {
+		t.Fatalf("subtree with one key was not collapsed")
+	}
+}
+
+// collisionNode follows the only child of every node down to the collision level.
+func collisionNode[K comparable, V any](n *hamtNode[K, V]) *hamtNode[K, V] {
+	for shift := uint(0); shift < 64; shift += hamtBits {
+		n = n.entries[0].child
+	}
+	return n
+}
+
+// TestVectorVersions builds vectors large enough to need three trie levels
+// and checks Append, Set and Pop against slices while old versions are kept.
+func TestVectorVersions(t *testing.T) {
+	const n = 40000
+	v := NewVector[int]()
+	var versions []*Vector[int]
+	for i := range n {
+		v = v.Append(i)
+		if i%997 == 0 {
+			versions = append(versions, v)
+		}
+	}
+	for _, version := range versions {
+		for i := range version.Len() {
+			if version.Get(i) != i {
+				t.Fatalf("version of length %d: Get(%d) = %d", version.Len(), i, version.Get(i))
+			}
+		}
+	}
+
+	want := make([]int, n)
+	for i := range want {
+		want[i] = i
+	}
+	updated := v
+	for i := 0; i < n; i += 37 {
+		updated = updated.Set(i, -i)
+		want[i] = -i
+	}
+	if v.Get(37) != 37 || updated.Get(37) != -37 {
+		t.Fatalf("Set changed the original vector")
+	}
+
+	for updated.Len() > 0 {
+		if updated.Len()%1234 == 0 || updated.Len() < 70 {
+			var got []int
+			for _, value := range updated.All() {
+				got = append(got, value)
+			}
+			if !slices.Equal(got, want) {
+				t.Fatalf("length %d: All() disagrees with slice", updated.Len())
+			}
+		}
+		updated = updated.Pop()
+		want = want[:len(want)-1]
+	}
+	if v.Len() != n || v.Get(n-1) != n-1 {
+		t.Fatalf("Pop changed the original vector")
+	}
+
+	// Appending after popping must not clobber values shared with other versions.
+	short := v.Pop().Pop()
+	branch := short.Append(-1)
+	if v.Get(n-2) != n-2 || branch.Get(n-2) != -1 {
+		t.Fatalf("Append after Pop wrote into a shared tail")
+	}
+}
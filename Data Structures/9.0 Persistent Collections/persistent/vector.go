@@ -0,0 +1,219 @@
+package persistent
+
+import (
+	"iter"
+	"slices"
+)
+
+const (
+	vectorBits  = 5
+	vectorWidth = 1 << vectorBits // values per leaf, children per branch
+	vectorMask  = vectorWidth - 1
+)
+
+// vectorNode is a branch (children set) or a leaf (values set) of the trie
+type vectorNode[T any] struct {
+	children []*vectorNode[T]
+	values   []T
+}
+
+// Vector is an immutable indexed sequence implemented as a 32-way trie with
+// a separate tail for the last (up to) 32 values. Index i is found by reading
+// its bits five at a time from the top, so Get and Set touch at most
+// log32(n) nodes, and Set copies only those. Append usually just copies the
+// small tail; once the tail is full it is pushed into the trie as a leaf.
+type Vector[T any] struct {
+	root  *vectorNode[T]
+	tail  []T
+	size  int
+	shift uint // depth of the trie times vectorBits
+}
+
+// NewVector creates an empty vector
+func NewVector[T any]() *Vector[T] {
+	return &Vector[T]{root: &vectorNode[T]{}, shift: vectorBits}
+}
+
+// VectorOf creates a vector holding the given values in order
+func VectorOf[T any](values ...T) *Vector[T] {
+	v := NewVector[T]()
+	for _, value := range values {
+		v = v.Append(value)
+	}
+	return v
+}
+
+// Len returns the number of values in the vector
+func (v *Vector[T]) Len() int {
+	return v.size
+}
+
+// tailOffset returns the index of the first value stored in the tail
+func (v *Vector[T]) tailOffset() int {
+	if v.size < vectorWidth {
+		return 0
+	}
+	return ((v.size - 1) >> vectorBits) << vectorBits
+}
+
+// leafFor returns the slice of values that holds index i
+func (v *Vector[T]) leafFor(i int) []T {
+	if i >= v.tailOffset() {
+		return v.tail
+	}
+	n := v.root
+	for level := v.shift; level > 0; level -= vectorBits {
+		n = n.children[(i>>level)&vectorMask]
+	}
+	return n.values
+}
+
+// Get returns the value at index i. It panics if i is out of range.
+func (v *Vector[T]) Get(i int) T {
+	if i < 0 || i >= v.size {
+		panic("persistent: vector index out of range")
+	}
+	return v.leafFor(i)[i&vectorMask]
+}
+
+// Append returns a new vector with value added at the end
+func (v *Vector[T]) Append(value T) *Vector[T] {
+	// Room in the tail: copy it and add the value
+	if v.size-v.tailOffset() < vectorWidth {
+		tail := make([]T, len(v.tail)+1, vectorWidth)
+		copy(tail, v.tail)
+		tail[len(v.tail)] = value
+		return &Vector[T]{root: v.root, tail: tail, size: v.size + 1, shift: v.shift}
+	}
+
+	// The tail is full: push it into the trie and start a new tail
+	leaf := &vectorNode[T]{values: v.tail}
+	root, shift := v.root, v.shift
+	if v.size>>vectorBits > 1<<v.shift {
+		// The trie is full at this depth, so grow a new root above it
+		root = &vectorNode[T]{children: []*vectorNode[T]{v.root, newPath(v.shift, leaf)}}
+		shift += vectorBits
+	} else {
+		root = v.pushTail(v.shift, v.root, leaf)
+	}
+	tail := make([]T, 1, vectorWidth)
+	tail[0] = value
+	return &Vector[T]{root: root, tail: tail, size: v.size + 1, shift: shift}
+}
+
+// pushTail returns a copy of parent with leaf added as its last leaf
+func (v *Vector[T]) pushTail(level uint, parent, leaf *vectorNode[T]) *vectorNode[T] {
+	i := ((v.size - 1) >> level) & vectorMask
+	children := slices.Clone(parent.children)
+	var child *vectorNode[T]
+	switch {
+	case level == vectorBits:
+		child = leaf
+	case i < len(children):
+		child = v.pushTail(level-vectorBits, children[i], leaf)
+	default:
+		child = newPath(level-vectorBits, leaf)
+	}
+	if i < len(children) {
+		children[i] = child
+	} else {
+		children = append(children, child)
+	}
+	return &vectorNode[T]{children: children}
+}
+
+// newPath wraps leaf in single-child branches until it sits at the given level
+func newPath[T any](level uint, leaf *vectorNode[T]) *vectorNode[T] {
+	if level == 0 {
+		return leaf
+	}
+	return &vectorNode[T]{children: []*vectorNode[T]{newPath(level-vectorBits, leaf)}}
+}
+
+// Set returns a new vector with the value at index i replaced.
+// It panics if i is out of range.
+func (v *Vector[T]) Set(i int, value T) *Vector[T] {
+	if i < 0 || i >= v.size {
+		panic("persistent: vector index out of range")
+	}
+	if i >= v.tailOffset() {
+		tail := slices.Clone(v.tail)
+		tail[i&vectorMask] = value
+		return &Vector[T]{root: v.root, tail: tail, size: v.size, shift: v.shift}
+	}
+	return &Vector[T]{root: setPath(v.shift, v.root, i, value), tail: v.tail, size: v.size, shift: v.shift}
+}
+
+// setPath returns a copy of the path from n to index i with the value replaced
+func setPath[T any](level uint, n *vectorNode[T], i int, value T) *vectorNode[T] {
+	if level == 0 {
+		values := slices.Clone(n.values)
+		values[i&vectorMask] = value
+		return &vectorNode[T]{values: values}
+	}
+	children := slices.Clone(n.children)
+	j := (i >> level) & vectorMask
+	children[j] = setPath(level-vectorBits, children[j], i, value)
+	return &vectorNode[T]{children: children}
+}
+
+// Pop returns a new vector without its last value. It panics if the vector is empty.
+func (v *Vector[T]) Pop() *Vector[T] {
+	switch {
+	case v.size == 0:
+		panic("persistent: Pop from empty vector")
+	case v.size == 1:
+		return NewVector[T]()
+	case v.size-v.tailOffset() > 1:
+		// The tail is never modified in place, so it can be shared
+		return &Vector[T]{root: v.root, tail: v.tail[:len(v.tail)-1], size: v.size - 1, shift: v.shift}
+	}
+
+	// The tail becomes empty: the last leaf of the trie becomes the new tail
+	tail := v.leafFor(v.size - 2)
+	root := v.popTail(v.shift, v.root)
+	shift := v.shift
+	if root == nil {
+		root = &vectorNode[T]{}
+	}
+	if shift > vectorBits && len(root.children) == 1 {
+		root = root.children[0]
+		shift -= vectorBits
+	}
+	return &Vector[T]{root: root, tail: tail, size: v.size - 1, shift: shift}
+}
+
+// popTail returns a copy of n without its last leaf, or nil if n becomes empty
+func (v *Vector[T]) popTail(level uint, n *vectorNode[T]) *vectorNode[T] {
+	i := ((v.size - 2) >> level) & vectorMask
+	if level > vectorBits {
+		child := v.popTail(level-vectorBits, n.children[i])
+		if child == nil && i == 0 {
+			return nil
+		}
+		children := slices.Clone(n.children[:i+1])
+		if child == nil {
+			children = children[:i]
+		} else {
+			children[i] = child
+		}
+		return &vectorNode[T]{children: children}
+	}
+	if i == 0 {
+		return nil
+	}
+	return &vectorNode[T]{children: slices.Clone(n.children[:i])}
+}
+
+// All returns an iterator over the indexes and values from front to back
+func (v *Vector[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for start := 0; start < v.size; start += vectorWidth {
+			for j, value := range v.leafFor(start) {
+				if !yield(start+j, value) {
+					return
+				}
+			}
+		}
+	}
+}